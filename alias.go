@@ -0,0 +1,38 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+// aliasServices matches the domains of privacy forwarding services, and their subdomains, such
+// as the per-user subdomains of addy.io.
+var aliasServices = mustDomainTrie([]string{
+	// SimpleLogin
+	".simplelogin.com", ".simplelogin.co", ".simplelogin.fr", ".aleeas.com", ".slmail.me",
+	".slmails.com", ".silomails.com", ".8alias.com", ".8shield.net", ".dralias.com",
+	// Firefox Relay
+	".mozmail.com", ".relay.firefox.com",
+	// DuckDuckGo Email Protection
+	".duck.com",
+	// Sign in with Apple and iCloud Hide My Email
+	".privaterelay.appleid.com",
+	// addy.io
+	".addy.io", ".anonaddy.com", ".anonaddy.me",
+})
+
+func mustDomainTrie(rules []string) *DomainTrie {
+	t, err := NewDomainTrie(rules)
+	if err != nil {
+		panic(err)
+	}
+	return t
+}
+
+// IsAliasService reports whether the email address belongs to a privacy forwarding service, such as
+// SimpleLogin, Firefox Relay, DuckDuckGo Email Protection or Sign in with Apple. Unlike disposable
+// addresses these deliver reliably, but hide the identity of the owner. Hide My Email addresses
+// at icloud.com can't be told apart from regular iCloud addresses and are not recognized.
+func (e EmailAddress) IsAliasService() bool {
+	return aliasServices.Match(e.Domain)
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "testing"
+
+func TestEmailAddress_IsAliasService(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want bool
+	}{
+		{"1", EmailAddress{"shop.x7k2", "simplelogin.com"}, true},
+		{"2", EmailAddress{"abc123", "mozmail.com"}, true},
+		{"3", EmailAddress{"quiet-fox", "Duck.com."}, true},
+		{"4", EmailAddress{"xyz", "privaterelay.appleid.com"}, true},
+		{"5", EmailAddress{"shop", "jane.anonaddy.com"}, true},
+		{"6", EmailAddress{"jane", "gmail.com"}, false},
+		{"7", EmailAddress{"jane", "icloud.com"}, false},
+		{"8", EmailAddress{"jane", "notduck.com"}, false},
+		{"9", EmailAddress{"jane", "mailinator.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsAliasService(); got != tt.want {
+				t.Errorf("EmailAddress.IsAliasService() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
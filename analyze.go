@@ -0,0 +1,242 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"math"
+	"sort"
+	"strings"
+)
+
+// AnalyzeOptions configures the classifiers of Analyze.
+type AnalyzeOptions struct {
+	// Disposable lists the domains of disposable email providers. No address is counted as
+	// disposable when it is nil.
+	Disposable DomainList
+
+	// Free lists the domains of free mail providers, defaults to FreeProviders.
+	Free DomainList
+
+	// Roles lists the local parts of role accounts, such as info and support, defaults to
+	// RoleAccounts.
+	Roles []string
+
+	// TopDomains is the number of domains reported in QualityReport.TopDomains, defaults to 10.
+	TopDomains int
+}
+
+// DomainShare is the number of addresses at a domain.
+type DomainShare struct {
+	Domain string
+	Count  int
+	Share  float64
+}
+
+// QualityReport describes the quality of a list of email addresses. Fractions are between 0 and 1.
+type QualityReport struct {
+	// Total is the number of addresses.
+	Total int
+
+	// Disposable, Role and Free are the fractions of addresses at disposable providers, of role
+	// accounts and at free mail providers.
+	Disposable float64
+	Role       float64
+	Free       float64
+
+	// BotLikeness counts the addresses by how machine generated their local part looks, in five
+	// buckets from human-like ([0, 0.2)) to machine generated ([0.8, 1]).
+	BotLikeness [5]int
+
+	// TopDomains are the most common domains, in descending order.
+	TopDomains []DomainShare
+
+	// Concentration is the Herfindahl index of the domains: the sum of the squared shares of
+	// every domain. It approaches 0 for a list spread over many domains and is 1 for a list of a
+	// single domain.
+	Concentration float64
+
+	// Verdicts counts the addresses by the outcome of their host check, it is only set by
+	// AnalyzeResults.
+	Verdicts map[HostStatus]int
+
+	// Score is the overall quality from 0 to 100 and Grade its letter: A (90 and above), B (80),
+	// C (70), D (60) or F.
+	Score float64
+	Grade string
+}
+
+// Analyze reports on the quality of a list of email addresses, such as the fraction of disposable
+// and role addresses, how machine generated the local parts look and how concentrated the list is
+// on a few domains. The score starts at 100 and is lowered by 60 points times the fraction of
+// disposable addresses, 20 times the fraction of role accounts, 40 times the fraction of
+// addresses that look machine generated (a bot-likeness of 0.6 or more) and 40 points times the
+// concentration above 0.25.
+func Analyze(emails []*EmailAddress, opts AnalyzeOptions) QualityReport {
+	return analyze(emails, nil, opts)
+}
+
+// AnalyzeResults is like Analyze, but includes the outcome of the host checks in the report. The
+// score is further lowered by 60 points times the fraction of invalid addresses and 20 points
+// times the fraction of unverifiable addresses.
+func AnalyzeResults(results []HostResult, opts AnalyzeOptions) QualityReport {
+	emails := make([]*EmailAddress, 0, len(results))
+	verdicts := make(map[HostStatus]int)
+	for _, r := range results {
+		if r.Address != nil {
+			emails = append(emails, r.Address)
+			verdicts[r.Status]++
+		}
+	}
+	return analyze(emails, verdicts, opts)
+}
+
+func analyze(emails []*EmailAddress, verdicts map[HostStatus]int, opts AnalyzeOptions) QualityReport {
+	if opts.Free == nil {
+		opts.Free = FreeProviders()
+	}
+	if opts.TopDomains <= 0 {
+		opts.TopDomains = 10
+	}
+	roles := currentRoles()
+	if opts.Roles != nil {
+		roles = newRoleSet(opts.Roles)
+	}
+
+	report := QualityReport{Total: len(emails), Verdicts: verdicts}
+	if report.Total == 0 {
+		report.Grade = "F"
+		return report
+	}
+	var disposable, role, free, bots int
+	domains := make(map[string]int)
+	for _, e := range emails {
+		domain := strings.TrimSuffix(strings.ToLower(e.Domain), ".")
+		domains[domain]++
+		if listed(opts.Disposable, domain) {
+			disposable++
+		}
+		if listed(opts.Free, domain) {
+			free++
+		}
+		if roles.contains(e.LocalPart) {
+			role++
+		}
+		b := botLikeness(roleLocalPart(e.LocalPart))
+		if b >= 0.6 {
+			bots++
+		}
+		report.BotLikeness[int(math.Min(b*5, 4))]++
+	}
+
+	n := float64(report.Total)
+	report.Disposable = float64(disposable) / n
+	report.Role = float64(role) / n
+	report.Free = float64(free) / n
+	for d, c := range domains {
+		share := float64(c) / n
+		report.Concentration += share * share
+		report.TopDomains = append(report.TopDomains, DomainShare{Domain: d, Count: c, Share: share})
+	}
+	sort.Slice(report.TopDomains, func(i, j int) bool {
+		a, b := report.TopDomains[i], report.TopDomains[j]
+		return a.Count > b.Count || a.Count == b.Count && a.Domain < b.Domain
+	})
+	if len(report.TopDomains) > opts.TopDomains {
+		report.TopDomains = report.TopDomains[:opts.TopDomains]
+	}
+
+	score := 100 - 60*report.Disposable - 20*report.Role - 40*float64(bots)/n -
+		40*math.Max(0, report.Concentration-0.25)
+	if verdicts != nil {
+		score -= 60*float64(verdicts[HostInvalid])/n + 20*float64(verdicts[HostUnverifiable])/n
+	}
+	report.Score = math.Max(0, score)
+	report.Grade = grade(report.Score)
+	return report
+}
+
+func grade(score float64) string {
+	switch {
+	case score >= 90:
+		return "A"
+	case score >= 80:
+		return "B"
+	case score >= 70:
+		return "C"
+	case score >= 60:
+		return "D"
+	default:
+		return "F"
+	}
+}
+
+// botLikeness returns how machine generated a local part looks, from 0 for a human-like name such
+// as john.doe to 1 for a random string such as x7f3k9q2hw8d.
+func botLikeness(local string) float64 {
+	var letters, digits, vowels, run, maxRun, digitRun, maxDigitRun, switches int
+	hex := len(local) >= 8
+	prev := rune(0)
+	for _, r := range local {
+		switch {
+		case r >= '0' && r <= '9':
+			digits++
+			run = 0
+			if digitRun++; digitRun > maxDigitRun {
+				maxDigitRun = digitRun
+			}
+			if prev >= 'a' && prev <= 'z' {
+				switches++
+			}
+		case r >= 'a' && r <= 'z':
+			letters++
+			digitRun = 0
+			if strings.ContainsRune("aeiouy", r) {
+				vowels++
+				run = 0
+			} else if run++; run > maxRun {
+				maxRun = run
+			}
+			if r > 'f' {
+				hex = false
+			}
+			if prev >= '0' && prev <= '9' {
+				switches++
+			}
+		default:
+			hex = false
+			run = 0
+			digitRun = 0
+		}
+		prev = r
+	}
+	if letters+digits == 0 {
+		return 0
+	}
+
+	score := 0.0
+	if ratio := float64(digits) / float64(letters+digits); ratio > 0.3 {
+		score += 0.4 * math.Min(1, ratio/0.6)
+	}
+	if letters >= 4 && float64(vowels)/float64(letters) < 0.2 {
+		score += 0.3
+	}
+	if maxRun >= 5 {
+		score += 0.3
+	}
+	if maxDigitRun >= 5 {
+		score += 0.2
+	}
+	if switches >= 4 {
+		score += 0.2
+	}
+	if hex && digits > 0 && letters > 0 {
+		score += 0.3
+	}
+	if len(local) > 16 && !strings.ContainsAny(local, ".-_") {
+		score += 0.2
+	}
+	return math.Min(1, score)
+}
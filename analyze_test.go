@@ -0,0 +1,120 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"testing"
+)
+
+func Test_botLikeness(t *testing.T) {
+	tests := []struct {
+		name  string
+		local string
+		human bool
+	}{
+		{"1", "john.doe", true},
+		{"2", "jane", true},
+		{"3", "j.smith1984", true},
+		{"4", "info", true},
+		{"5", "x7f3k9q2hw8d", false},
+		{"6", "3fa85f6457174562", false},
+		{"7", "qwrtzxcvbnm", false},
+		{"8", "user83920174", false},
+		{"9", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := botLikeness(tt.local)
+			if human := got < 0.6; human != tt.human {
+				t.Errorf("botLikeness(%q) = %v, want human %v", tt.local, got, tt.human)
+			}
+		})
+	}
+}
+
+func TestAnalyze(t *testing.T) {
+	emails := []*EmailAddress{
+		{"john.doe", "gmail.com"},
+		{"jane", "example.com"},
+		{"info", "example.com"},
+		{"x7f3k9q2hw8d", "mailinator.com"},
+	}
+	got := Analyze(emails, AnalyzeOptions{Disposable: NewDomainList([]string{"mailinator.com"}), TopDomains: 2})
+	want := QualityReport{
+		Total:         4,
+		Disposable:    0.25,
+		Role:          0.25,
+		Free:          0.25,
+		TopDomains:    []DomainShare{{"example.com", 2, 0.5}, {"gmail.com", 1, 0.25}},
+		Concentration: 0.25*0.25 + 0.5*0.5 + 0.25*0.25,
+	}
+	if got.Total != want.Total || got.Disposable != want.Disposable || got.Role != want.Role || got.Free != want.Free {
+		t.Errorf("Analyze() = %+v, want %+v", got, want)
+	}
+	if !reflect.DeepEqual(got.TopDomains, want.TopDomains) {
+		t.Errorf("Analyze() TopDomains = %v, want %v", got.TopDomains, want.TopDomains)
+	}
+	if math.Abs(got.Concentration-want.Concentration) > 1e-9 {
+		t.Errorf("Analyze() Concentration = %v, want %v", got.Concentration, want.Concentration)
+	}
+	if got.BotLikeness[0]+got.BotLikeness[1]+got.BotLikeness[2] != 3 || got.BotLikeness[3]+got.BotLikeness[4] != 1 {
+		t.Errorf("Analyze() BotLikeness = %v, want 3 human and 1 bot", got.BotLikeness)
+	}
+	// 100 - 60*0.25 - 20*0.25 - 40*0.25 - 40*0.125
+	if math.Abs(got.Score-65) > 1e-9 || got.Grade != "D" {
+		t.Errorf("Analyze() Score = %v, Grade = %v, want 65, D", got.Score, got.Grade)
+	}
+	if got.Verdicts != nil {
+		t.Errorf("Analyze() Verdicts = %v, want nil", got.Verdicts)
+	}
+}
+
+func TestAnalyze_grade(t *testing.T) {
+	var clean []*EmailAddress
+	for i := 0; i < 100; i++ {
+		clean = append(clean, &EmailAddress{fmt.Sprintf("first%c.last", 'a'+i%26), fmt.Sprintf("company%d.com", i)})
+	}
+	tests := []struct {
+		name   string
+		emails []*EmailAddress
+		opts   AnalyzeOptions
+		want   string
+	}{
+		{"1", clean, AnalyzeOptions{}, "A"},
+		{"2", clean, AnalyzeOptions{Disposable: NewDomainList([]string{"com"})}, "F"},
+		{"3", []*EmailAddress{{"a.b", "one.com"}}, AnalyzeOptions{}, "C"},
+		{"4", nil, AnalyzeOptions{}, "F"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Analyze(tt.emails, tt.opts).Grade; got != tt.want {
+				t.Errorf("Analyze().Grade = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestAnalyzeResults(t *testing.T) {
+	results := []HostResult{
+		{Address: &EmailAddress{"john.doe", "a.com"}, Status: HostVerified},
+		{Address: &EmailAddress{"jane.doe", "b.com"}, Status: HostVerified},
+		{Address: &EmailAddress{"jim.doe", "c.com"}, Status: HostInvalid},
+		{Address: &EmailAddress{"joe.doe", "d.com"}, Status: HostUnverifiable},
+		{Address: nil, Status: HostInvalid},
+	}
+	got := AnalyzeResults(results, AnalyzeOptions{})
+	want := map[HostStatus]int{HostVerified: 2, HostInvalid: 1, HostUnverifiable: 1}
+	if got.Total != 4 || !reflect.DeepEqual(got.Verdicts, want) {
+		t.Errorf("AnalyzeResults() = %v, %v, want 4, %v", got.Total, got.Verdicts, want)
+	}
+	// 100 - 60*0.25 - 20*0.25
+	if math.Abs(got.Score-80) > 1e-9 || got.Grade != "B" {
+		t.Errorf("AnalyzeResults() Score = %v, Grade = %v, want 80, B", got.Score, got.Grade)
+	}
+}
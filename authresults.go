@@ -0,0 +1,204 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strings"
+)
+
+// AuthenticationResults is a parsed Authentication-Results header (RFC 8601), as added by the
+// receiving mail host.
+type AuthenticationResults struct {
+	// AuthServID identifies the host that performed the checks, ie. mx.google.com.
+	AuthServID string
+
+	// Results are the outcomes of the checks, in header order. It is empty if the host performed
+	// no checks.
+	Results []AuthResult
+}
+
+// AuthResult is the outcome of a single authentication method, such as SPF or DKIM.
+type AuthResult struct {
+	// Method is the authentication method in lower case, ie. spf, dkim or dmarc.
+	Method string
+
+	// Result is the verdict in lower case, ie. pass, fail, softfail or none.
+	Result string
+
+	// Reason is the explanation given for the verdict, if any.
+	Reason string
+
+	// Properties maps the properties of the check, ie. smtp.mailfrom or header.d, to their value.
+	Properties map[string]string
+
+	// Domain is the domain that was authenticated, ie. smtp.mailfrom for SPF, header.d for DKIM and
+	// header.from for DMARC. It is empty if the result names no domain.
+	Domain string
+
+	// Address is the authenticated email address, if the identity includes a local part.
+	Address *EmailAddress
+}
+
+// identityProperties are the properties holding the authenticated identity of each method, most
+// specific first.
+var identityProperties = map[string][]string{
+	"spf":       {"smtp.mailfrom", "smtp.helo"},
+	"dkim":      {"header.d", "header.i"},
+	"dmarc":     {"header.from"},
+	"sender-id": {"header.sender", "header.from"},
+}
+
+// ParseAuthenticationResults parses the value of an Authentication-Results header, with or without
+// the header name. Comments are ignored.
+//
+//	ar, err := emailaddress.ParseAuthenticationResults("mx.example.net; spf=pass smtp.mailfrom=jane@example.com; dkim=pass header.d=example.com")
+//	for _, r := range ar.Results {
+//		fmt.Println(r.Method, r.Result, r.Domain)
+//	}
+//	// spf pass example.com
+//	// dkim pass example.com
+func ParseAuthenticationResults(header string) (*AuthenticationResults, error) {
+	if i := strings.IndexByte(header, ':'); i >= 0 && strings.EqualFold(strings.TrimSpace(header[:i]), "Authentication-Results") {
+		header = header[i+1:]
+	}
+	p := &authParser{s: header}
+
+	ar := &AuthenticationResults{}
+	if ar.AuthServID = p.value(); ar.AuthServID == "" {
+		return nil, fmt.Errorf("invalid Authentication-Results header, missing authserv-id: %q", header)
+	}
+	if v := p.value(); v != "" && strings.Trim(v, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid Authentication-Results header, unexpected %q after authserv-id", v)
+	}
+	for p.next(';') {
+		key := p.key()
+		if strings.EqualFold(key, "none") && !p.peek('=') {
+			continue
+		}
+		if !p.next('=') {
+			return nil, fmt.Errorf("invalid Authentication-Results header, missing result of %q", key)
+		}
+		r := AuthResult{
+			Method:     strings.ToLower(key),
+			Result:     strings.ToLower(p.value()),
+			Properties: make(map[string]string),
+		}
+		if i := strings.IndexByte(r.Method, '/'); i >= 0 {
+			r.Method = r.Method[:i]
+		}
+		for !p.done() && !p.peek(';') {
+			name := strings.ToLower(p.key())
+			if name == "" || !p.next('=') {
+				return nil, fmt.Errorf("invalid Authentication-Results header, malformed property of %s", r.Method)
+			}
+			if value := p.value(); name == "reason" {
+				r.Reason = value
+			} else {
+				r.Properties[name] = value
+			}
+		}
+		r.identify()
+		ar.Results = append(ar.Results, r)
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("invalid Authentication-Results header, unexpected %q", p.s[p.i:])
+	}
+	return ar, nil
+}
+
+// identify sets the authenticated domain from the first identity property of the method, and the
+// address from the first identity property with a local part.
+func (r *AuthResult) identify() {
+	for _, name := range identityProperties[r.Method] {
+		v := r.Properties[name]
+		if v == "" {
+			continue
+		}
+		i := strings.LastIndexByte(v, '@')
+		if r.Domain == "" {
+			r.Domain = strings.TrimSuffix(v[i+1:], ".")
+		}
+		if r.Address == nil && i > 0 {
+			r.Address, _ = Parse(v)
+		}
+	}
+}
+
+// authParser tokenizes an Authentication-Results header, skipping whitespace and comments.
+type authParser struct {
+	s string
+	i int
+}
+
+// skip advances past whitespace and (possibly nested) comments.
+func (p *authParser) skip() {
+	depth := 0
+	for ; p.i < len(p.s); p.i++ {
+		switch c := p.s[p.i]; {
+		case c == '\\' && depth > 0:
+			p.i++
+		case c == '(':
+			depth++
+		case c == ')' && depth > 0:
+			depth--
+		case depth == 0 && c != ' ' && c != '\t' && c != '\r' && c != '\n':
+			return
+		}
+	}
+}
+
+func (p *authParser) done() bool {
+	p.skip()
+	return p.i >= len(p.s)
+}
+
+func (p *authParser) peek(c byte) bool {
+	p.skip()
+	return p.i < len(p.s) && p.s[p.i] == c
+}
+
+// next consumes c if it is the next character.
+func (p *authParser) next(c byte) bool {
+	if !p.peek(c) {
+		return false
+	}
+	p.i++
+	return true
+}
+
+// key returns the next token up to an =.
+func (p *authParser) key() string {
+	p.skip()
+	start := p.i
+	for p.i < len(p.s) && !strings.ContainsRune(" \t\r\n;=(\"", rune(p.s[p.i])) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
+
+// value returns the next token or quoted string, a value may contain an =.
+func (p *authParser) value() string {
+	p.skip()
+	if p.i < len(p.s) && p.s[p.i] == '"' {
+		var b strings.Builder
+		for p.i++; p.i < len(p.s) && p.s[p.i] != '"'; p.i++ {
+			if p.s[p.i] == '\\' && p.i+1 < len(p.s) {
+				p.i++
+			}
+			b.WriteByte(p.s[p.i])
+		}
+		if p.i < len(p.s) {
+			p.i++
+		}
+		return b.String()
+	}
+	start := p.i
+	for p.i < len(p.s) && !strings.ContainsRune(" \t\r\n;(", rune(p.s[p.i])) {
+		p.i++
+	}
+	return p.s[start:p.i]
+}
@@ -0,0 +1,93 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseAuthenticationResults(t *testing.T) {
+	header := "Authentication-Results: mx.google.com;\r\n" +
+		"       dkim=pass header.i=@example.com header.s=s1 header.b=Ab+c/9=;\r\n" +
+		"       spf=pass (google.com: domain of jane@example.com designates 192.0.2.1 as permitted sender) smtp.mailfrom=jane@example.com;\r\n" +
+		"       dmarc=fail reason=\"policy (strict)\" (p=REJECT sp=REJECT dis=NONE) header.from=example.org"
+	got, err := ParseAuthenticationResults(header)
+	if err != nil {
+		t.Fatalf("ParseAuthenticationResults() error = %v", err)
+	}
+	want := &AuthenticationResults{
+		AuthServID: "mx.google.com",
+		Results: []AuthResult{
+			{
+				Method:     "dkim",
+				Result:     "pass",
+				Properties: map[string]string{"header.i": "@example.com", "header.s": "s1", "header.b": "Ab+c/9="},
+				Domain:     "example.com",
+			},
+			{
+				Method:     "spf",
+				Result:     "pass",
+				Properties: map[string]string{"smtp.mailfrom": "jane@example.com"},
+				Domain:     "example.com",
+				Address:    &EmailAddress{"jane", "example.com"},
+			},
+			{
+				Method:     "dmarc",
+				Result:     "fail",
+				Reason:     "policy (strict)",
+				Properties: map[string]string{"header.from": "example.org"},
+				Domain:     "example.org",
+			},
+		},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("ParseAuthenticationResults() = %+v, want %+v", got, want)
+	}
+}
+
+func TestParseAuthenticationResults_variants(t *testing.T) {
+	tests := []struct {
+		name    string
+		header  string
+		results int
+		domain  string
+		wantErr bool
+	}{
+		{"1", "example.net; none", 0, "", false},
+		{"2", "example.net 1; spf=softfail smtp.mailfrom=example.com", 1, "example.com", false},
+		{"3", "example.net; dkim/1=PASS header.d=Example.com. header.i=news@sub.example.com", 1, "Example.com", false},
+		{"4", "example.net; spf = pass smtp.helo = mail.example.com", 1, "mail.example.com", false},
+		{"5", "example.net; iprev=pass policy.iprev=192.0.2.1", 1, "", false},
+		{"6", "", 0, "", true},
+		{"7", "example.net; spf", 0, "", true},
+		{"8", "example.net; spf=pass smtp.mailfrom", 0, "", true},
+		{"9", "example.net foo; spf=pass", 0, "", true},
+		{"10", "example.net; spf=pass reason=\"unterminated", 1, "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseAuthenticationResults(tt.header)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ParseAuthenticationResults() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if len(got.Results) != tt.results {
+				t.Fatalf("ParseAuthenticationResults() = %v results, want %v", len(got.Results), tt.results)
+			}
+			if tt.results > 0 && got.Results[0].Domain != tt.domain {
+				t.Errorf("ParseAuthenticationResults() domain = %v, want %v", got.Results[0].Domain, tt.domain)
+			}
+		})
+	}
+
+	got, _ := ParseAuthenticationResults("example.net; dkim=pass header.d=example.com header.i=news@sub.example.com")
+	if a := got.Results[0].Address; a == nil || a.String() != "news@sub.example.com" {
+		t.Errorf("ParseAuthenticationResults() address = %v, want news@sub.example.com", a)
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	// defaultBatchWorkers is the number of domains ValidateBatch validates at the same time.
+	defaultBatchWorkers = 8
+
+	// maxBatchRecipients is the number of recipients ValidateBatch probes over one connection.
+	maxBatchRecipients = 50
+)
+
+// WithBatchWorkers sets the number of domains ValidateBatch validates at the same time. Defaults
+// to 8.
+func WithBatchWorkers(n int) Option {
+	return func(v *Verifier) {
+		v.batchWorkers = n
+	}
+}
+
+// ValidateBatch validates emails like CheckHost with a Verifier configured with opts. See
+// Verifier.ValidateBatch.
+func ValidateBatch(ctx context.Context, emails []*EmailAddress, opts ...Option) []HostResult {
+	return NewVerifier(opts...).ValidateBatch(ctx, emails)
+}
+
+// ValidateBatch validates emails like CheckHost and returns their results in the same order, ie.
+// to write them with WriteResultsCSV. Addresses are grouped by domain, the mail hosts of every
+// domain are looked up once and its addresses are probed over one connection at a time, with up
+// to 50 recipients per connection. Domains are validated concurrently, see WithBatchWorkers.
+func (v *Verifier) ValidateBatch(ctx context.Context, emails []*EmailAddress) []HostResult {
+	results := make([]HostResult, len(emails))
+	var domains []string
+	groups := make(map[string][]int)
+	for i, e := range emails {
+		results[i].Address = e
+		if c, ok := v.resultCache.get(e.String()); ok {
+			results[i].Status, results[i].Err = c.Status, c.err()
+			continue
+		}
+		domain := strings.ToLower(asciiDomain(e.Domain))
+		if _, ok := groups[domain]; !ok {
+			domains = append(domains, domain)
+		}
+		groups[domain] = append(groups[domain], i)
+	}
+
+	workers := v.batchWorkers
+	if workers <= 0 {
+		workers = defaultBatchWorkers
+	}
+	jobs := make(chan []int)
+	var wg sync.WaitGroup
+	for i := 0; i < workers && i < len(domains); i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range jobs {
+				v.validateDomain(ctx, emails, group, results)
+			}
+		}()
+	}
+	for _, domain := range domains {
+		jobs <- groups[domain]
+	}
+	close(jobs)
+	wg.Wait()
+	return results
+}
+
+// validateDomain validates the addresses with the indexes group, which share their domain, and
+// stores their results.
+func (v *Verifier) validateDomain(ctx context.Context, emails []*EmailAddress, group []int, results []HostResult) {
+	e := *emails[group[0]]
+	setAll := func(status HostStatus, err error) {
+		for _, i := range group {
+			results[i].Status, results[i].Err = status, err
+		}
+	}
+	if err := v.checkLists(e.Domain); err != nil {
+		setAll(HostInvalid, err)
+		return
+	}
+	hosts, err := v.resolveHosts(ctx, e.Domain, true)
+	if err != nil {
+		if errors.Is(err, ErrUnverifiable) {
+			setAll(HostUnverifiable, err)
+		} else {
+			setAll(HostInvalid, err)
+		}
+		return
+	}
+	if err = v.checkParked(e.Domain, hosts[0]); err != nil {
+		setAll(HostInvalid, err)
+		return
+	}
+
+	for len(group) > 0 {
+		n := len(group)
+		if n > maxBatchRecipients {
+			n = maxBatchRecipients
+		}
+		v.probeBatch(ctx, hosts, emails, group[:n], results)
+		group = group[n:]
+	}
+}
+
+// probeBatch probes the addresses with the indexes group over one connection per mail host, like
+// probeHosts. Recipients are probed again at the next mail host while their host can't be reached
+// or replies with a temporary failure, and temporary failures are retried as configured by
+// WithRetry.
+func (v *Verifier) probeBatch(ctx context.Context, hosts []string, emails []*EmailAddress, group []int, results []HostResult) {
+	b := &batchProbe{emails: emails, group: group, errs: make([]error, len(group)), hosts: make([]string, len(group))}
+	if !v.offline {
+		pending := make([]int, len(group))
+		for p := range pending {
+			pending[p] = p
+		}
+		hosts = v.probeTargets(hosts)
+		v.probeRound(ctx, b, hosts, pending)
+		v.retryBatch(ctx, b, hosts)
+	}
+
+	for p, i := range group {
+		err := b.errs[p]
+		status := HostUnverifiable
+		switch {
+		case err == nil:
+			if err = v.notProbed(); err == nil {
+				status = HostVerified
+			}
+		case rejected(err):
+			status = HostInvalid
+		}
+		results[i].Status, results[i].Err = status, err
+		if status != HostUnverifiable {
+			v.resultCache.put(cacheEntry{Key: emails[i].String(), Status: status}, err)
+		}
+	}
+}
+
+// batchProbe holds the recipients of probeBatch. errs and hosts, the host that gave the error, are
+// indexed by the position of the recipient in group.
+type batchProbe struct {
+	emails []*EmailAddress
+	group  []int
+	errs   []error
+	hosts  []string
+}
+
+// probeRound probes the recipients at the positions pending at hosts in order, until every
+// recipient got an answer that doesn't fall through to the next host.
+func (v *Verifier) probeRound(ctx context.Context, b *batchProbe, hosts []string, pending []int) {
+	for _, host := range hosts {
+		if len(pending) == 0 || ctx.Err() != nil {
+			break
+		}
+		rcpts := make([]EmailAddress, len(pending))
+		for j, p := range pending {
+			rcpts[j] = *b.emails[b.group[p]]
+		}
+		_, rcptErrs, err := v.session(ctx, host, rcpts[0], rcpts)
+		var next []int
+		for j, p := range pending {
+			rcptErr := err
+			if err == nil {
+				rcptErr = rcptErrs[j]
+			}
+			if rcptErr == nil || !fallThrough(rcptErr) {
+				b.errs[p], b.hosts[p] = rcptErr, host
+				continue
+			}
+			// Prefer the error of a host that replied over one that couldn't be reached.
+			if b.errs[p] == nil || ErrorCode(b.errs[p]) == CodeSMTPUnreachable && ErrorCode(rcptErr) != CodeSMTPUnreachable {
+				b.errs[p], b.hosts[p] = rcptErr, host
+			}
+			next = append(next, p)
+		}
+		if len(next) > 0 {
+			v.logf(ctx, "smtp: %s: %d recipients failed temporarily, trying the next mail host", host, len(next))
+		}
+		pending = next
+	}
+
+	// Recipients that were not probed before ctx was done have no error yet.
+	for _, p := range pending {
+		if b.errs[p] == nil {
+			b.errs[p] = ctx.Err()
+		}
+	}
+}
+
+// retryBatch probes the recipients of b that failed temporarily again, like probeHosts, at the
+// host that answered or at all hosts with WithRetryRotation.
+func (v *Verifier) retryBatch(ctx context.Context, b *batchProbe, hosts []string) {
+	backoff := v.retryDelay
+	for attempt := 1; attempt < v.retries; attempt++ {
+		var retry []int
+		for p, err := range b.errs {
+			if ErrorCode(err) == CodeSMTPTemporary {
+				retry = append(retry, p)
+			}
+		}
+		if len(retry) == 0 {
+			return
+		}
+		v.logf(ctx, "smtp: %d recipients failed temporarily, retrying in %v", len(retry), backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			for _, p := range retry {
+				b.errs[p] = ctx.Err()
+			}
+			return
+		}
+		backoff *= 2
+
+		for _, p := range retry {
+			b.errs[p] = nil
+		}
+		if v.retryRotate {
+			v.probeRound(ctx, b, hosts, retry)
+			continue
+		}
+		var order []string
+		byHost := make(map[string][]int)
+		for _, p := range retry {
+			host := b.hosts[p]
+			if _, ok := byHost[host]; !ok {
+				order = append(order, host)
+			}
+			byHost[host] = append(byHost[host], p)
+		}
+		for _, host := range order {
+			v.probeRound(ctx, b, []string{host}, byHost[host])
+		}
+	}
+}
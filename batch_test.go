@@ -0,0 +1,179 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestVerifier_ValidateBatch(t *testing.T) {
+	primary := newTestServer(t, "127.0.0.1:0")
+	primary.rcpt = func(addr string) string {
+		switch addr {
+		case "fake@example.com":
+			return "550 5.1.1 no such user"
+		case "greylisted@example.com", "greylisted@example.net":
+			return "450 4.7.1 greylisted"
+		}
+		return "250 OK"
+	}
+	backup := newTestServer(t, "127.0.0.2:"+strconv.Itoa(primary.port()))
+	backup.rcpt = func(addr string) string {
+		if addr == "greylisted@example.net" {
+			return "250 OK"
+		}
+		return "450 4.7.1 greylisted"
+	}
+	r := &testResolver{mx: map[string][]*net.MX{
+		"example.com": {{Host: primary.host(), Pref: 10}},
+		"example.net": {{Host: primary.host(), Pref: 10}, {Host: backup.host(), Pref: 20}},
+	}}
+	v := NewVerifier(WithPort(primary.port()), WithBatchWorkers(2), WithDenyList(NewDomainList([]string{"denied.com"})))
+	v.resolver = r
+
+	emails := []*EmailAddress{
+		{"info", "example.com"},
+		{"fake", "example.com"},
+		{"info", "example.org"},
+		{"greylisted", "example.com"},
+		{"greylisted", "example.net"},
+		{"info", "denied.com"},
+		{"sales", "Example.com"},
+	}
+	tests := []struct {
+		name     string
+		want     HostStatus
+		wantCode Code
+	}{
+		{"1", HostVerified, ""},
+		{"2", HostInvalid, CodeMailboxRejected},
+		{"3", HostInvalid, CodeNoMX},
+		{"4", HostUnverifiable, CodeSMTPTemporary},
+		{"5", HostVerified, ""},
+		{"6", HostInvalid, CodeDenied},
+		{"7", HostVerified, ""},
+	}
+	results := v.ValidateBatch(context.Background(), emails)
+	if len(results) != len(tests) {
+		t.Fatalf("len(Verifier.ValidateBatch()) = %d, want %d", len(results), len(tests))
+	}
+	for i, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := results[i]
+			if got.Address != emails[i] || got.Status != tt.want || ErrorCode(got.Err) != tt.wantCode {
+				t.Errorf("Verifier.ValidateBatch()[%d] = %v, %v, %v, want %v, %v, code %q", i, got.Address, got.Status, got.Err, emails[i], tt.want, tt.wantCode)
+			}
+		})
+	}
+
+	// example.com and example.net are probed over one connection each, example.org is looked up
+	// with an MX and an A query.
+	if n := countCommand(primary.commands(), "MAIL FROM"); n != 2 {
+		t.Errorf("MAIL FROM commands = %d, want 2", n)
+	}
+	if r.queries != 4 {
+		t.Errorf("DNS queries = %d, want 4", r.queries)
+	}
+}
+
+func TestVerifier_ValidateBatch_retry(t *testing.T) {
+	tests := []struct {
+		name  string
+		opts  []Option
+		want  HostStatus
+		rcpts int
+	}{
+		{"1", nil, HostUnverifiable, 2},
+		{"2", []Option{WithRetry(2, time.Millisecond)}, HostVerified, 3},
+		{"3", []Option{WithRetry(2, time.Millisecond), WithRetryRotation()}, HostVerified, 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0")
+			var replied int32
+			s.rcpt = func(addr string) string {
+				if addr == "info@example.com" && atomic.AddInt32(&replied, 1) == 1 {
+					return "421 4.3.2 try again later"
+				}
+				return "250 OK"
+			}
+			v := NewVerifier(append([]Option{WithPort(s.port())}, tt.opts...)...)
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+
+			results := v.ValidateBatch(context.Background(), []*EmailAddress{{"info", "example.com"}, {"sales", "example.com"}})
+			if got := results[0]; got.Status != tt.want {
+				t.Errorf("Verifier.ValidateBatch()[0] = %v, %v, want %v", got.Status, got.Err, tt.want)
+			}
+			if got := results[1]; got.Status != HostVerified {
+				t.Errorf("Verifier.ValidateBatch()[1] = %v, %v, want %v", got.Status, got.Err, HostVerified)
+			}
+			if n := countRcpt(s.commands()); n != tt.rcpts {
+				t.Errorf("RCPT commands = %d, want %d", n, tt.rcpts)
+			}
+		})
+	}
+}
+
+func TestVerifier_ValidateBatch_chunks(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	v := NewVerifier(WithPort(s.port()), WithCache(time.Minute))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+
+	emails := make([]*EmailAddress, maxBatchRecipients+10)
+	for i := range emails {
+		emails[i] = &EmailAddress{fmt.Sprintf("user%d", i), "example.com"}
+	}
+	for _, r := range v.ValidateBatch(context.Background(), emails) {
+		if r.Status != HostVerified {
+			t.Errorf("Verifier.ValidateBatch() %v = %v, %v, want %v", r.Address, r.Status, r.Err, HostVerified)
+		}
+	}
+	if n := countCommand(s.commands(), "MAIL FROM"); n != 2 {
+		t.Errorf("MAIL FROM commands = %d, want 2", n)
+	}
+	if n := countRcpt(s.commands()); n != len(emails) {
+		t.Errorf("RCPT commands = %d, want %d", n, len(emails))
+	}
+
+	// The results are cached.
+	v.ValidateBatch(context.Background(), emails[:1])
+	if n := countRcpt(s.commands()); n != len(emails) {
+		t.Errorf("RCPT commands after a cached batch = %d, want %d", n, len(emails))
+	}
+}
+
+func TestVerifier_ValidateBatch_context(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	v := NewVerifier(WithPort(s.port()))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	for _, r := range v.ValidateBatch(ctx, []*EmailAddress{{"info", "example.com"}, {"sales", "example.com"}}) {
+		if r.Status != HostUnverifiable || r.Err != context.Canceled {
+			t.Errorf("Verifier.ValidateBatch() %v = %v, %v, want %v, %v", r.Address, r.Status, r.Err, HostUnverifiable, context.Canceled)
+		}
+	}
+}
+
+func countCommand(cmds []string, prefix string) int {
+	n := 0
+	for _, c := range cmds {
+		if strings.HasPrefix(strings.ToUpper(c), prefix) {
+			n++
+		}
+	}
+	return n
+}
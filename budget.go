@@ -0,0 +1,199 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrBudgetExhausted is returned when a query budget of a Verifier is used up. It wraps
+// ErrUnverifiable, so CheckHost reports such addresses as HostUnverifiable.
+var ErrBudgetExhausted = fmt.Errorf("%w: query budget exhausted", ErrUnverifiable)
+
+// Budget limits the number of queries in a period, ie. Budget{Limit: 1000, Period: 24 * time.Hour}
+// allows 1000 queries a day. The period starts with the first query and a new period starts once
+// it has passed.
+type Budget struct {
+	Limit  int
+	Period time.Duration
+}
+
+// WithDNSBudget limits the DNS queries sent to each resolver, as configured with WithResolvers.
+// A resolver whose budget is used up is skipped, once the budgets of all resolvers are used up
+// lookups fail with an error wrapping ErrBudgetExhausted. Budgets are included in the snapshots of
+// ExportCache and ImportCache, or kept in the store of WithCacheStore, so they hold across runs.
+func WithDNSBudget(b Budget) Option {
+	return func(v *Verifier) {
+		v.dnsBudget = newBudget(b)
+	}
+}
+
+// WithProbeBudget limits the SMTP connections made to each mail provider, the registered domain
+// of the mail host (ie. google.com for aspmx.l.google.com). Once the budget of a provider is used
+// up probes fail with an error wrapping ErrBudgetExhausted. Budgets are included in the snapshots
+// of ExportCache and ImportCache, or kept in the store of WithCacheStore, so they hold across runs.
+func WithProbeBudget(b Budget) Option {
+	return func(v *Verifier) {
+		v.probeBudget = newBudget(b)
+	}
+}
+
+// budget counts the queries per key, a nil budget allows every query.
+type budget struct {
+	Budget
+
+	// store holds the counters under prefix instead of counters, if set.
+	store  Cache
+	prefix string
+
+	mu       sync.Mutex
+	counters map[string]budgetEntry
+}
+
+// budgetEntry counts the queries of a key in the current period, it is also the format of the
+// budget entries of a cache snapshot.
+type budgetEntry struct {
+	Key   string    `json:"key"`
+	Used  int       `json:"used"`
+	Reset time.Time `json:"reset"`
+}
+
+func newBudget(b Budget) *budget {
+	return &budget{Budget: b, counters: make(map[string]budgetEntry)}
+}
+
+// take uses a query of the budget of key, it returns ErrBudgetExhausted if none is left.
+func (b *budget) take(key string) error {
+	if b == nil {
+		return nil
+	}
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	e, ok := b.load(key)
+	if !ok || !now.Before(e.Reset) {
+		e = budgetEntry{Key: key, Reset: now.Add(b.Period)}
+	}
+	if e.Used >= b.Limit {
+		return fmt.Errorf("%w for %s until %s", ErrBudgetExhausted, key, e.Reset.Format(time.RFC3339))
+	}
+	e.Used++
+	b.save(e)
+	return nil
+}
+
+// load returns the counter of key, from the store if b has one. b.mu must be held.
+func (b *budget) load(key string) (budgetEntry, bool) {
+	if b.store == nil {
+		e, ok := b.counters[key]
+		return e, ok
+	}
+	v, ok := b.store.Get(b.prefix + key)
+	if !ok {
+		return budgetEntry{}, false
+	}
+	var e budgetEntry
+	if err := json.Unmarshal(v, &e); err != nil {
+		return budgetEntry{}, false
+	}
+	return e, true
+}
+
+// save stores the counter e until its period has passed. b.mu must be held.
+func (b *budget) save(e budgetEntry) {
+	if b.store == nil {
+		b.counters[e.Key] = e
+		return
+	}
+	if v, err := json.Marshal(e); err == nil {
+		b.store.Set(b.prefix+e.Key, v, time.Until(e.Reset))
+	}
+}
+
+// snapshot returns the counters of the current periods, which are none if they are kept in a
+// store.
+func (b *budget) snapshot() []budgetEntry {
+	if b == nil || b.store != nil {
+		return nil
+	}
+	now := time.Now()
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	var entries []budgetEntry
+	for _, e := range b.counters {
+		if now.Before(e.Reset) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// restore adds a counter of a snapshot, a counter of a period that has passed is ignored.
+func (b *budget) restore(e budgetEntry) {
+	if b == nil || !time.Now().Before(e.Reset) {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if c, ok := b.load(e.Key); !ok || c.Used < e.Used {
+		b.save(e)
+	}
+}
+
+// provider returns the key of the probe budget of a mail host.
+func provider(host string) string {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if net.ParseIP(host) != nil {
+		return host
+	}
+	if d, err := effectiveTLDPlusOne(host); err == nil {
+		return d
+	}
+	return host
+}
+
+// budgetResolver counts the queries of resolver against a budget.
+type budgetResolver struct {
+	resolver resolver
+	budget   *budget
+	key      string
+}
+
+func (r *budgetResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if err := r.budget.take(r.key); err != nil {
+		return nil, err
+	}
+	return r.resolver.LookupMX(ctx, name)
+}
+
+func (r *budgetResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	if err := r.budget.take(r.key); err != nil {
+		return nil, err
+	}
+	return r.resolver.LookupIP(ctx, network, host)
+}
+
+func (r *budgetResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	if err := r.budget.take(r.key); err != nil {
+		return nil, err
+	}
+	return r.resolver.LookupTXT(ctx, name)
+}
+
+func (r *budgetResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	if err := r.budget.take(r.key); err != nil {
+		return nil, err
+	}
+	return r.resolver.LookupAddr(ctx, addr)
+}
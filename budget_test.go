@@ -0,0 +1,148 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func TestWithDNSBudget(t *testing.T) {
+	v := NewVerifier(WithDNSBudget(Budget{Limit: 2, Period: time.Hour}))
+	if r, ok := v.resolver.(*budgetResolver); !ok || r.key != "system" {
+		t.Errorf("NewVerifier() resolver = %T, want the system resolver with a budget", v.resolver)
+	}
+	v = NewVerifier(WithResolvers("192.0.2.1", "192.0.2.2"), WithDNSBudget(Budget{Limit: 2, Period: time.Hour}))
+	h := v.resolver.(*hedgedResolver)
+	for i, r := range h.resolvers {
+		if b, ok := r.(*budgetResolver); !ok || b.key != h.servers[i] {
+			t.Errorf("NewVerifier() resolver %d = %T, want a budget for %s", i, r, h.servers[i])
+		}
+	}
+
+	v = NewVerifier()
+	v.resolver = &budgetResolver{
+		resolver: &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com", Pref: 10}}}},
+		budget:   newBudget(Budget{Limit: 2, Period: time.Hour}),
+		key:      "test",
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := v.lookupHost(context.Background(), "example.com"); err != nil {
+			t.Fatalf("Verifier.lookupHost() error = %v", err)
+		}
+	}
+	_, err := v.lookupHost(context.Background(), "example.com")
+	if !errors.Is(err, ErrBudgetExhausted) || !errors.Is(err, ErrUnverifiable) || ErrorCode(err) != CodeBudgetExhausted {
+		t.Errorf("Verifier.lookupHost() error = %v, want %v", err, ErrBudgetExhausted)
+	}
+	if status, _ := v.CheckHost(context.Background(), EmailAddress{"info", "example.com"}); status != HostUnverifiable {
+		t.Errorf("Verifier.CheckHost() = %v, want %v", status, HostUnverifiable)
+	}
+}
+
+func TestWithProbeBudget(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	v := NewVerifier(WithProbeBudget(Budget{Limit: 1, Period: time.Hour}))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+	v.port = s.port()
+
+	if status, err := v.CheckHost(context.Background(), EmailAddress{"info", "example.com"}); status != HostVerified {
+		t.Fatalf("Verifier.CheckHost() = %v, %v, want %v", status, err, HostVerified)
+	}
+	status, err := v.CheckHost(context.Background(), EmailAddress{"sales", "example.com"})
+	if status != HostUnverifiable || !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Verifier.CheckHost() = %v, %v, want %v, %v", status, err, HostUnverifiable, ErrBudgetExhausted)
+	}
+
+	var buf bytes.Buffer
+	if err := v.ExportCache(&buf); err != nil {
+		t.Fatalf("Verifier.ExportCache() error = %v", err)
+	}
+	w := NewVerifier(WithProbeBudget(Budget{Limit: 1, Period: time.Hour}))
+	if err := w.ImportCache(&buf); err != nil {
+		t.Fatalf("Verifier.ImportCache() error = %v", err)
+	}
+	if err := w.probeBudget.take(s.host()); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("imported budget take() error = %v, want %v", err, ErrBudgetExhausted)
+	}
+}
+
+func TestWithProbeBudget_store(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	store := newMapStore()
+	var vs []*Verifier
+	for i := 0; i < 2; i++ {
+		v := NewVerifier(WithPort(s.port()), WithProbeBudget(Budget{Limit: 1, Period: time.Hour}), WithCacheStore(store))
+		v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+		vs = append(vs, v)
+	}
+
+	if status, err := vs[0].CheckHost(context.Background(), EmailAddress{"info", "example.com"}); status != HostVerified {
+		t.Fatalf("Verifier.CheckHost() = %v, %v, want %v", status, err, HostVerified)
+	}
+	status, err := vs[1].CheckHost(context.Background(), EmailAddress{"sales", "example.com"})
+	if status != HostUnverifiable || !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("Verifier.CheckHost() with a shared store = %v, %v, want %v, %v", status, err, HostUnverifiable, ErrBudgetExhausted)
+	}
+	if ttl := store.ttls["probebudget:"+s.host()]; ttl <= 0 || ttl > time.Hour {
+		t.Errorf("ttl of the budget = %v, want at most the period", ttl)
+	}
+	if got := vs[0].probeBudget.snapshot(); len(got) != 0 {
+		t.Errorf("budget.snapshot() = %v, want counters only in the store", got)
+	}
+}
+
+func Test_budget(t *testing.T) {
+	b := newBudget(Budget{Limit: 1, Period: 20 * time.Millisecond})
+	if err := b.take("a"); err != nil {
+		t.Fatalf("budget.take() error = %v", err)
+	}
+	if err := b.take("a"); !errors.Is(err, ErrBudgetExhausted) {
+		t.Errorf("budget.take() error = %v, want %v", err, ErrBudgetExhausted)
+	}
+	if err := b.take("b"); err != nil {
+		t.Errorf("budget.take() other key error = %v", err)
+	}
+	time.Sleep(30 * time.Millisecond)
+	if err := b.take("a"); err != nil {
+		t.Errorf("budget.take() next period error = %v", err)
+	}
+	b.restore(budgetEntry{Key: "c", Used: 5, Reset: time.Now().Add(-time.Second)})
+	if err := b.take("c"); err != nil {
+		t.Errorf("budget.take() after restoring a passed period error = %v", err)
+	}
+
+	var nilBudget *budget
+	if err := nilBudget.take("a"); err != nil {
+		t.Errorf("nil budget.take() error = %v", err)
+	}
+}
+
+func Test_provider(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{"1", "aspmx.l.google.com.", "google.com"},
+		{"2", "MX1.Example.co.uk", "example.co.uk"},
+		{"3", "192.0.2.1", "192.0.2.1"},
+		{"4", "localhost", "localhost"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := provider(tt.host); got != tt.want {
+				t.Errorf("provider() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
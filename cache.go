@@ -0,0 +1,464 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// cacheVersion is the version of the cache snapshot format.
+const cacheVersion = 1
+
+// WithCache caches the mail host of every domain, the outcome of every CheckHost and Verify and
+// whether a domain is a catch-all for ttl. Only definitive answers are cached: resolved hosts,
+// domains that don't exist, addresses that are verified or invalid and catch-all probes that were
+// answered. Temporary failures and unverifiable addresses are always retried. Use ExportCache and
+// ImportCache to keep the cache between runs.
+func WithCache(ttl time.Duration) Option {
+	return func(v *Verifier) {
+		v.hostCache = newCache(ttl)
+		v.resultCache = newCache(ttl)
+		v.catchAllCache = newCache(ttl)
+	}
+}
+
+// WithHostCache caches the mail hosts of every domain for ttl, like WithCache, but not the outcome
+// of CheckHost. The package level functions cache mail hosts for 5 minutes.
+func WithHostCache(ttl time.Duration) Option {
+	return func(v *Verifier) {
+		v.hostCache = newCache(ttl)
+	}
+}
+
+// WithNegativeCacheTTL sets how long the caches of WithCache and WithHostCache keep definitive
+// failures, such as domains that don't exist and rejected addresses, if it should differ from the
+// ttl of the cache.
+func WithNegativeCacheTTL(ttl time.Duration) Option {
+	return func(v *Verifier) {
+		v.negativeTTL = ttl
+	}
+}
+
+// Cache is a store for the caches of WithCache and WithHostCache and the query budgets, ie. to
+// share answers between processes through Redis or keep them in a file. Values are JSON and must be kept for the given
+// ttl at most. A Cache must be safe for concurrent use by multiple goroutines.
+type Cache interface {
+	Get(key string) (value []byte, ok bool)
+	Set(key string, value []byte, ttl time.Duration)
+}
+
+// WithCacheStore keeps the entries of the caches of WithCache and WithHostCache in c instead of
+// in memory. Keys of mail hosts start with host:, keys of CheckHost results with result: and keys
+// of catch-all domains with catchall:. The counters of WithDNSBudget and WithProbeBudget are kept
+// in c too, with keys starting with dnsbudget: and probebudget:, so Verifiers sharing c share
+// their budgets. Since a Cache can't update a counter atomically, concurrent processes may
+// together exceed a budget by a few queries.
+func WithCacheStore(c Cache) Option {
+	return func(v *Verifier) {
+		v.cacheStore = c
+	}
+}
+
+// cache holds answers, such as the results of DNS lookups, by a case-insensitive key. A nil cache
+// never hits.
+type cache struct {
+	ttl         time.Duration
+	negativeTTL time.Duration
+
+	// store holds the entries under prefix instead of entries, if set.
+	store  Cache
+	prefix string
+
+	// metrics, if set, receives every lookup, by the prefix without colon.
+	metrics Metrics
+
+	mu      sync.Mutex
+	entries map[string]cacheEntry
+	hits    uint64
+	misses  uint64
+}
+
+// CacheStats counts the lookups of the caches of a Verifier created with WithCache.
+type CacheStats struct {
+	// HostHits and HostMisses count the lookups of the mail host of a domain.
+	HostHits   uint64
+	HostMisses uint64
+
+	// ResultHits and ResultMisses count the lookups of the outcome of CheckHost and Verify.
+	ResultHits   uint64
+	ResultMisses uint64
+
+	// CatchAllHits and CatchAllMisses count the lookups of whether a domain is a catch-all.
+	CatchAllHits   uint64
+	CatchAllMisses uint64
+}
+
+// HitRate returns the fraction of all lookups that hit the cache.
+func (s CacheStats) HitRate() float64 {
+	total := s.HostHits + s.HostMisses + s.ResultHits + s.ResultMisses + s.CatchAllHits + s.CatchAllMisses
+	if total == 0 {
+		return 0
+	}
+	return float64(s.HostHits+s.ResultHits+s.CatchAllHits) / float64(total)
+}
+
+// CacheStats returns the number of cache hits and misses since the Verifier was created.
+func (v *Verifier) CacheStats() CacheStats {
+	var s CacheStats
+	s.HostHits, s.HostMisses = v.hostCache.stats()
+	s.ResultHits, s.ResultMisses = v.resultCache.stats()
+	s.CatchAllHits, s.CatchAllMisses = v.catchAllCache.stats()
+	return s
+}
+
+// cacheEntry is a cached answer, it is also the format of the entries of a snapshot.
+type cacheEntry struct {
+	Key      string     `json:"key"`
+	Host     string     `json:"host,omitempty"`
+	Hosts    []string   `json:"hosts,omitempty"`
+	Status   HostStatus `json:"status,omitempty"`
+	CatchAll bool       `json:"catch_all,omitempty"`
+	Code     Code       `json:"code,omitempty"`
+	Err      string     `json:"error,omitempty"`
+	Expires  time.Time  `json:"expires"`
+
+	// Sentinels are the names of the sentinel errors the error matches, see cachedSentinels,
+	// and SMTP is the reply of the mail host it wraps, if any.
+	Sentinels []string   `json:"sentinels,omitempty"`
+	SMTP      *SMTPError `json:"smtp,omitempty"`
+
+	// Result holds the steps of the answer of Verify, nil if the answer is of CheckHost.
+	Result *cachedResult `json:"result,omitempty"`
+}
+
+// cachedResult holds the steps of a ValidationResult besides those of its cacheEntry.
+type cachedResult struct {
+	Verdict           Verdict           `json:"verdict"`
+	HasMX             bool              `json:"has_mx,omitempty"`
+	SMTPConnected     bool              `json:"smtp_connected,omitempty"`
+	RecipientAccepted bool              `json:"recipient_accepted,omitempty"`
+	Extensions        map[string]string `json:"extensions,omitempty"`
+	TLS               *cachedTLS        `json:"tls,omitempty"`
+}
+
+// cachedTLS is a TLSState, with the message of its certificate error.
+type cachedTLS struct {
+	Version          uint16 `json:"version"`
+	CipherSuite      uint16 `json:"cipher_suite"`
+	ServerName       string `json:"server_name,omitempty"`
+	CertificateValid bool   `json:"certificate_valid,omitempty"`
+	CertificateError string `json:"certificate_error,omitempty"`
+}
+
+// resultEntry returns the cache entry of r, a definitive result of Verify.
+func resultEntry(r ValidationResult) cacheEntry {
+	e := cacheEntry{Key: r.Address.String(), Status: HostVerified, Host: r.Host, CatchAll: r.CatchAll}
+	if r.Verdict == VerdictUndeliverable {
+		e.Status = HostInvalid
+	}
+	e.Result = &cachedResult{
+		Verdict:           r.Verdict,
+		HasMX:             r.HasMX,
+		SMTPConnected:     r.SMTPConnected,
+		RecipientAccepted: r.RecipientAccepted,
+	}
+	if r.Capabilities != nil {
+		e.Result.Extensions = r.Capabilities.Extensions
+	}
+	if s := r.TLS; s != nil {
+		e.Result.TLS = &cachedTLS{Version: s.Version, CipherSuite: s.CipherSuite, ServerName: s.ServerName, CertificateValid: s.CertificateValid}
+		if s.CertificateError != nil {
+			e.Result.TLS.CertificateError = s.CertificateError.Error()
+		}
+	}
+	return e
+}
+
+// result returns r, which holds the parsed address, with the steps of the cached answer of Verify.
+func (e cacheEntry) result(r ValidationResult) ValidationResult {
+	c := e.Result
+	r.HasMX, r.Host, r.SMTPConnected, r.RecipientAccepted, r.CatchAll = c.HasMX, e.Host, c.SMTPConnected, c.RecipientAccepted, e.CatchAll
+	if t := c.TLS; t != nil {
+		r.TLS = &TLSState{Version: t.Version, CipherSuite: t.CipherSuite, ServerName: t.ServerName, CertificateValid: t.CertificateValid}
+		if t.CertificateError != "" {
+			r.TLS.CertificateError = errors.New(t.CertificateError)
+		}
+	}
+	if r.SMTPConnected {
+		r.Capabilities = &Capabilities{Host: e.Host, Extensions: c.Extensions, TLS: r.TLS}
+	}
+	r.Verdict, r.Err = c.Verdict, e.err()
+	if e.SMTP != nil {
+		r.SMTPCode, r.SMTPEnhancedCode, r.SMTPMessage = e.SMTP.Code, e.SMTP.EnhancedCode, e.SMTP.Message
+	}
+	return r
+}
+
+// cachedSentinels are the sentinel errors a cached error keeps matching, besides the sentinel of
+// its Code.
+var cachedSentinels = map[string]error{
+	"unverifiable":     ErrUnverifiable,
+	"budget-exhausted": ErrBudgetExhausted,
+	"parked":           ErrParked,
+	"denied":           ErrDenied,
+	"disposable":       ErrDisposable,
+}
+
+// cachedError is an error restored from the cache. It matches the sentinels and the *SMTPError
+// of the original error with errors.Is and errors.As.
+type cachedError struct {
+	msg       string
+	sentinels []error
+	smtp      *SMTPError
+}
+
+func (e *cachedError) Error() string {
+	return e.msg
+}
+
+// Is reports whether the original error matched target.
+func (e *cachedError) Is(target error) bool {
+	for _, sentinel := range e.sentinels {
+		if errors.Is(sentinel, target) {
+			return true
+		}
+	}
+	return false
+}
+
+// Unwrap returns the reply of the mail host the original error wrapped, if any.
+func (e *cachedError) Unwrap() error {
+	if e.smtp == nil {
+		return nil
+	}
+	return e.smtp
+}
+
+// cacheSnapshot is the format written by ExportCache.
+type cacheSnapshot struct {
+	Version      int           `json:"version"`
+	Hosts        []cacheEntry  `json:"hosts"`
+	Results      []cacheEntry  `json:"results"`
+	CatchAll     []cacheEntry  `json:"catch_all,omitempty"`
+	DNSBudgets   []budgetEntry `json:"dns_budgets,omitempty"`
+	ProbeBudgets []budgetEntry `json:"probe_budgets,omitempty"`
+}
+
+func newCache(ttl time.Duration) *cache {
+	return &cache{ttl: ttl, entries: make(map[string]cacheEntry)}
+}
+
+// hosts returns the cached mail hosts of the answer, snapshots written before backup hosts were
+// cached only hold the most preferred host.
+func (e cacheEntry) hosts() []string {
+	if len(e.Hosts) == 0 && e.Host != "" {
+		return []string{e.Host}
+	}
+	return e.Hosts
+}
+
+// err returns the cached error of the answer, with its code, sentinels and reply.
+func (e cacheEntry) err() error {
+	if e.Err == "" {
+		return nil
+	}
+	err := &cachedError{msg: e.Err, smtp: e.SMTP}
+	for _, name := range e.Sentinels {
+		if sentinel, ok := cachedSentinels[name]; ok {
+			err.sentinels = append(err.sentinels, sentinel)
+		}
+	}
+	if e.Code != "" {
+		return newError(e.Code, err)
+	}
+	return err
+}
+
+// setErr records err in the entry, so that err can rebuild an equivalent error.
+func (e *cacheEntry) setErr(err error) {
+	e.Code, e.Err = ErrorCode(err), err.Error()
+	errors.As(err, &e.SMTP)
+	for name, sentinel := range cachedSentinels {
+		if errors.Is(err, sentinel) {
+			e.Sentinels = append(e.Sentinels, name)
+		}
+	}
+	sort.Strings(e.Sentinels)
+}
+
+func (c *cache) get(key string) (cacheEntry, bool) {
+	if c == nil {
+		return cacheEntry{}, false
+	}
+	key = strings.ToLower(key)
+	if c.store != nil {
+		e, ok := c.load(key)
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		return e, c.count(ok)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	e, ok := c.entries[key]
+	if ok && !time.Now().Before(e.Expires) {
+		delete(c.entries, key)
+		ok = false
+	}
+	return e, c.count(ok)
+}
+
+// load returns the entry of key from the store, if it is valid and has not expired.
+func (c *cache) load(key string) (cacheEntry, bool) {
+	b, ok := c.store.Get(c.prefix + key)
+	if !ok {
+		return cacheEntry{}, false
+	}
+	var e cacheEntry
+	if err := json.Unmarshal(b, &e); err != nil || !time.Now().Before(e.Expires) {
+		return cacheEntry{}, false
+	}
+	return e, true
+}
+
+// count counts a hit or miss, c.mu must be held.
+func (c *cache) count(ok bool) bool {
+	if c.metrics != nil {
+		c.metrics.CacheLookup(strings.TrimSuffix(c.prefix, ":"), ok)
+	}
+	if !ok {
+		c.misses++
+		return false
+	}
+	c.hits++
+	return true
+}
+
+func (c *cache) stats() (hits, misses uint64) {
+	if c == nil {
+		return 0, 0
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.hits, c.misses
+}
+
+// put caches e with err as its error until the ttl of the cache expires.
+func (c *cache) put(e cacheEntry, err error) {
+	if c == nil {
+		return
+	}
+	ttl := c.ttl
+	if err != nil {
+		e.setErr(err)
+		if c.negativeTTL > 0 {
+			ttl = c.negativeTTL
+		}
+	}
+	e.Expires = time.Now().Add(ttl)
+	c.add(e)
+}
+
+func (c *cache) add(e cacheEntry) {
+	e.Key = strings.ToLower(e.Key)
+	if c.store != nil {
+		if b, err := json.Marshal(e); err == nil {
+			c.store.Set(c.prefix+e.Key, b, time.Until(e.Expires))
+		}
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[e.Key] = e
+}
+
+// snapshot returns the unexpired entries, which are none if they are kept in a store.
+func (c *cache) snapshot() []cacheEntry {
+	entries := []cacheEntry{}
+	if c == nil || c.store != nil {
+		return entries
+	}
+	now := time.Now()
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for _, e := range c.entries {
+		if now.Before(e.Expires) {
+			entries = append(entries, e)
+		}
+	}
+	return entries
+}
+
+// ExportCache writes a snapshot of the unexpired cache entries and the query budgets used in the
+// current periods to w as JSON. Entries and budgets kept in the store of WithCacheStore are not
+// written. It returns an error if the Verifier was created without WithCache, WithDNSBudget and
+// WithProbeBudget.
+func (v *Verifier) ExportCache(w io.Writer) error {
+	if !v.hasCache() {
+		return fmt.Errorf("verifier has no cache")
+	}
+	return json.NewEncoder(w).Encode(cacheSnapshot{
+		Version:      cacheVersion,
+		Hosts:        v.hostCache.snapshot(),
+		Results:      v.resultCache.snapshot(),
+		CatchAll:     v.catchAllCache.snapshot(),
+		DNSBudgets:   v.dnsBudget.snapshot(),
+		ProbeBudgets: v.probeBudget.snapshot(),
+	})
+}
+
+// hasCache reports whether the Verifier holds any state to export.
+func (v *Verifier) hasCache() bool {
+	return v.hostCache != nil || v.dnsBudget != nil || v.probeBudget != nil
+}
+
+// ImportCache reads a snapshot written by ExportCache from r and adds its unexpired entries to the
+// cache. Entries keep the expiry time they were exported with, so a snapshot never extends the
+// ttl of an answer. Budgets used in periods that have not passed are restored. It returns an error
+// if the Verifier was created without WithCache, WithDNSBudget and WithProbeBudget.
+func (v *Verifier) ImportCache(r io.Reader) error {
+	if !v.hasCache() {
+		return fmt.Errorf("verifier has no cache")
+	}
+	var s cacheSnapshot
+	if err := json.NewDecoder(r).Decode(&s); err != nil {
+		return fmt.Errorf("failed reading cache snapshot: %w", err)
+	}
+	if s.Version != cacheVersion {
+		return fmt.Errorf("unsupported cache snapshot version %d", s.Version)
+	}
+	now := time.Now()
+	for _, e := range s.Hosts {
+		if v.hostCache != nil && now.Before(e.Expires) {
+			v.hostCache.add(e)
+		}
+	}
+	for _, e := range s.Results {
+		if v.resultCache != nil && now.Before(e.Expires) && e.Status != HostUnverifiable {
+			v.resultCache.add(e)
+		}
+	}
+	for _, e := range s.CatchAll {
+		if v.catchAllCache != nil && now.Before(e.Expires) {
+			v.catchAllCache.add(e)
+		}
+	}
+	for _, e := range s.DNSBudgets {
+		v.dnsBudget.restore(e)
+	}
+	for _, e := range s.ProbeBudgets {
+		v.probeBudget.restore(e)
+	}
+	return nil
+}
@@ -0,0 +1,357 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestVerifier_cache(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		switch addr {
+		case "fake@example.com":
+			return "550 no such user"
+		case "greylisted@example.com":
+			return "450 try again later"
+		}
+		return "250 OK"
+	}
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+	v := NewVerifier(WithCache(time.Minute))
+	v.resolver = r
+	v.port = s.port()
+
+	tests := []struct {
+		name        string
+		e           EmailAddress
+		want        HostStatus
+		wantQueries int
+		wantRcpts   int
+	}{
+		{"1", EmailAddress{"info", "example.com"}, HostVerified, 1, 1},
+		{"2", EmailAddress{"info", "Example.com"}, HostVerified, 1, 1},
+		{"3", EmailAddress{"fake", "example.com"}, HostInvalid, 1, 2},
+		{"4", EmailAddress{"fake", "example.com"}, HostInvalid, 1, 2},
+		{"5", EmailAddress{"greylisted", "example.com"}, HostUnverifiable, 1, 3},
+		{"6", EmailAddress{"greylisted", "example.com"}, HostUnverifiable, 1, 4},
+		{"7", EmailAddress{"info", "example.org"}, HostInvalid, 3, 4},
+		{"8", EmailAddress{"other", "example.org"}, HostInvalid, 3, 4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.CheckHost(context.Background(), tt.e)
+			if got != tt.want {
+				t.Errorf("Verifier.CheckHost() = %v, %v, want %v", got, err, tt.want)
+			}
+			if (err != nil) != (tt.want != HostVerified) {
+				t.Errorf("Verifier.CheckHost() error = %v", err)
+			}
+			if r.queries != tt.wantQueries {
+				t.Errorf("DNS queries = %d, want %d", r.queries, tt.wantQueries)
+			}
+			if got := countRcpt(s.commands()); got != tt.wantRcpts {
+				t.Errorf("RCPT commands = %d, want %d", got, tt.wantRcpts)
+			}
+		})
+	}
+}
+
+func TestVerifier_cacheErrors(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(string) string { return "550 5.1.1 no such user" }
+	_, loopback, _ := net.ParseCIDR("127.0.0.0/8")
+
+	tests := []struct {
+		name   string
+		opts   []Option
+		e      EmailAddress
+		target error
+		smtp   bool
+	}{
+		{"1", []Option{WithCache(time.Minute)}, EmailAddress{"fake", "example.com"}, ErrRecipientRejected, true},
+		{"2", []Option{WithCache(time.Minute), WithCacheStore(newMapStore())}, EmailAddress{"fake", "example.com"}, ErrRecipientRejected, true},
+		{"3", []Option{WithCache(time.Minute), WithParkingList(nil, []*net.IPNet{loopback})}, EmailAddress{"info", "example.com"}, ErrParked, false},
+		{"4", []Option{WithCache(time.Minute), WithCacheStore(newMapStore()), WithParkingList(nil, []*net.IPNet{loopback})}, EmailAddress{"info", "example.com"}, ErrParked, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append([]Option{WithPort(s.port())}, tt.opts...)...)
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+			var errs []error
+			for i := 0; i < 2; i++ {
+				_, err := v.CheckHost(context.Background(), tt.e)
+				errs = append(errs, err)
+			}
+			if stats := v.CacheStats(); stats.ResultHits != 1 {
+				t.Fatalf("Verifier.CacheStats() = %+v, want a hit", stats)
+			}
+			for i, err := range errs {
+				if !errors.Is(err, tt.target) || ErrorCode(err) != ErrorCode(errs[0]) || err.Error() != errs[0].Error() {
+					t.Errorf("Verifier.CheckHost() #%d error = %v, want %v", i, err, tt.target)
+				}
+				var smtpErr *SMTPError
+				if errors.As(err, &smtpErr) != tt.smtp || (tt.smtp && (smtpErr.Code != 550 || smtpErr.EnhancedCode != "5.1.1")) {
+					t.Errorf("Verifier.CheckHost() #%d error = %v, want *SMTPError %v", i, err, tt.smtp)
+				}
+			}
+		})
+	}
+
+	c := newCache(time.Minute)
+	c.put(cacheEntry{Key: "example.com"}, newError(CodeBudgetExhausted, ErrBudgetExhausted))
+	if e, _ := c.get("example.com"); !errors.Is(e.err(), ErrBudgetExhausted) || !errors.Is(e.err(), ErrUnverifiable) {
+		t.Errorf("cacheEntry.err() = %v, want ErrBudgetExhausted", e.err())
+	}
+}
+
+func TestVerifier_cacheTemporaryFailure(t *testing.T) {
+	r := &testResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}
+	v := NewVerifier(WithCache(time.Minute))
+	v.resolver = r
+	for i := 0; i < 2; i++ {
+		if _, err := v.lookupHost(context.Background(), "example.com"); err == nil {
+			t.Fatalf("Verifier.lookupHost() error = nil, want error")
+		}
+	}
+	// A failed MX query is not followed by an A query.
+	if r.queries != 2 {
+		t.Errorf("DNS queries = %d, want 2", r.queries)
+	}
+}
+
+func TestVerifier_ExportCache(t *testing.T) {
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com", Pref: 10}}}}
+	v := NewVerifier(WithCache(time.Minute))
+	v.resolver = r
+	v.lookupHost(context.Background(), "example.com") // #nosec
+	v.lookupHost(context.Background(), "example.org") // #nosec
+	v.resultCache.put(cacheEntry{Key: "fake@example.com", Status: HostInvalid}, errors.New("550 no such user"))
+
+	var buf bytes.Buffer
+	if err := v.ExportCache(&buf); err != nil {
+		t.Fatalf("Verifier.ExportCache() error = %v", err)
+	}
+
+	warm := NewVerifier(WithCache(time.Minute))
+	warm.resolver = &testResolver{}
+	if err := warm.ImportCache(&buf); err != nil {
+		t.Fatalf("Verifier.ImportCache() error = %v", err)
+	}
+	if host, err := warm.lookupHost(context.Background(), "example.com"); host != "mx.example.com" || err != nil {
+		t.Errorf("Verifier.lookupHost() = %v, %v, want %v", host, err, "mx.example.com")
+	}
+	if _, err := warm.lookupHost(context.Background(), "example.org"); err == nil {
+		t.Errorf("Verifier.lookupHost() error = nil, want cached error")
+	}
+	if status, err := warm.CheckHost(context.Background(), EmailAddress{"fake", "example.com"}); status != HostInvalid || err == nil {
+		t.Errorf("Verifier.CheckHost() = %v, %v, want %v", status, err, HostInvalid)
+	}
+	if q := warm.resolver.(*testResolver).queries; q != 0 {
+		t.Errorf("DNS queries after import = %d, want 0", q)
+	}
+}
+
+func TestVerifier_ImportCache(t *testing.T) {
+	past := time.Now().Add(-time.Minute).Format(time.RFC3339)
+	future := time.Now().Add(time.Minute).Format(time.RFC3339)
+	tests := []struct {
+		name      string
+		snapshot  string
+		wantHosts int
+		wantErr   bool
+	}{
+		{"1", `{"version":1,"hosts":[{"key":"a.com","host":"mx.a.com","expires":"` + future + `"}]}`, 1, false},
+		{"2", `{"version":1,"hosts":[{"key":"a.com","host":"mx.a.com","expires":"` + past + `"}]}`, 0, false},
+		{"3", `{"version":2,"hosts":[]}`, 0, true},
+		{"4", `not json`, 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithCache(time.Minute))
+			err := v.ImportCache(strings.NewReader(tt.snapshot))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.ImportCache() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := len(v.hostCache.snapshot()); got != tt.wantHosts {
+				t.Errorf("len(hosts) = %d, want %d", got, tt.wantHosts)
+			}
+		})
+	}
+	if err := NewVerifier().ImportCache(strings.NewReader("{}")); err == nil {
+		t.Errorf("Verifier.ImportCache() without cache error = nil, want error")
+	}
+}
+
+func TestVerifier_CacheStats(t *testing.T) {
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com", Pref: 10}}}}
+	v := NewVerifier(WithCache(time.Minute))
+	v.resolver = r
+	for i := 0; i < 4; i++ {
+		v.lookupHost(context.Background(), "example.com") // #nosec
+	}
+	want := CacheStats{HostHits: 3, HostMisses: 1}
+	if got := v.CacheStats(); got != want {
+		t.Errorf("Verifier.CacheStats() = %+v, want %+v", got, want)
+	}
+	if got := want.HitRate(); got != 0.75 {
+		t.Errorf("CacheStats.HitRate() = %v, want 0.75", got)
+	}
+	if got := NewVerifier().CacheStats(); got != (CacheStats{}) {
+		t.Errorf("Verifier.CacheStats() without cache = %+v, want zero", got)
+	}
+}
+
+// mapStore is a Cache backed by a map.
+type mapStore struct {
+	mu      sync.Mutex
+	entries map[string][]byte
+	ttls    map[string]time.Duration
+}
+
+func newMapStore() *mapStore {
+	return &mapStore{entries: make(map[string][]byte), ttls: make(map[string]time.Duration)}
+}
+
+func (s *mapStore) Get(key string) ([]byte, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	b, ok := s.entries[key]
+	return b, ok
+}
+
+func (s *mapStore) Set(key string, value []byte, ttl time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.entries[key], s.ttls[key] = value, ttl
+}
+
+func TestWithCacheStore(t *testing.T) {
+	store := newMapStore()
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx2.example.com", Pref: 20}, {Host: "mx1.example.com", Pref: 10}}}}
+	v := NewVerifier(WithCache(time.Minute), WithCacheStore(store))
+	v.resolver = r
+	v.LookupHosts(context.Background(), "example.com") // #nosec
+	v.LookupHosts(context.Background(), "example.org") // #nosec
+	v.resultCache.put(cacheEntry{Key: "fake@example.com", Status: HostInvalid}, errors.New("550 no such user"))
+
+	for _, key := range []string{"host:example.com", "host:example.org", "result:fake@example.com"} {
+		if _, ok := store.Get(key); !ok {
+			t.Errorf("Cache.Get(%q) = false, want an entry", key)
+		} else if ttl := store.ttls[key]; ttl <= 0 || ttl > time.Minute {
+			t.Errorf("ttl of %q = %v, want at most a minute", key, ttl)
+		}
+	}
+	if got := len(v.hostCache.snapshot()); got != 0 {
+		t.Errorf("len(snapshot) = %d, want entries only in the store", got)
+	}
+
+	shared := NewVerifier(WithCacheStore(store), WithHostCache(time.Minute))
+	shared.resolver = &testResolver{}
+	want := []string{"mx1.example.com", "mx2.example.com"}
+	if hosts, err := shared.LookupHosts(context.Background(), "Example.com"); !reflect.DeepEqual(hosts, want) || err != nil {
+		t.Errorf("Verifier.LookupHosts() = %v, %v, want %v", hosts, err, want)
+	}
+	if _, err := shared.LookupHosts(context.Background(), "example.org"); ErrorCode(err) != CodeNoMX {
+		t.Errorf("Verifier.LookupHosts() error = %v, want the cached error", err)
+	}
+	if q := shared.resolver.(*testResolver).queries; q != 0 {
+		t.Errorf("DNS queries with a shared store = %d, want 0", q)
+	}
+
+	store.Set("host:example.net", []byte("not json"), time.Minute)
+	if _, ok := shared.hostCache.get("example.net"); ok {
+		t.Errorf("cache.get() of an invalid entry = true, want a miss")
+	}
+}
+
+func TestWithNegativeCacheTTL(t *testing.T) {
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com", Pref: 10}}}}
+	v := NewVerifier(WithNegativeCacheTTL(time.Nanosecond), WithHostCache(time.Minute))
+	v.resolver = r
+	for i := 0; i < 2; i++ {
+		v.LookupHosts(context.Background(), "example.com") // #nosec
+		v.LookupHosts(context.Background(), "example.org") // #nosec
+	}
+	// example.com is looked up once, example.org twice with an MX and an A query each time.
+	if r.queries != 1+2*2 {
+		t.Errorf("DNS queries = %d, want %d", r.queries, 1+2*2)
+	}
+	if v.resultCache != nil {
+		t.Errorf("WithHostCache() caches results, want only mail hosts")
+	}
+}
+
+func countRcpt(cmds []string) int {
+	n := 0
+	for _, c := range cmds {
+		if strings.HasPrefix(strings.ToUpper(c), "RCPT") {
+			n++
+		}
+	}
+	return n
+}
+
+func TestVerifier_catchAllCache(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		if strings.HasSuffix(addr, "@catchall.com") || addr == "info@example.com" || addr == "sales@example.com" {
+			return "250 OK"
+		}
+		return "550 no such user"
+	}
+	store := newMapStore()
+	v := NewVerifier(WithPort(s.port()), WithCache(time.Minute), WithCacheStore(store))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{
+		"example.com":  {{Host: s.host(), Pref: 10}},
+		"catchall.com": {{Host: s.host(), Pref: 10}},
+	}}
+
+	tests := []struct {
+		name    string
+		address string
+		want    Verdict
+	}{
+		{"1", "info@catchall.com", VerdictRisky},
+		{"2", "sales@catchall.com", VerdictRisky},
+		{"3", "info@example.com", VerdictDeliverable},
+		{"4", "sales@example.com", VerdictDeliverable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := v.Verify(context.Background(), tt.address); got.Verdict != tt.want {
+				t.Errorf("Verifier.Verify() = %v, %v, want %v", got.Verdict, got.Err, tt.want)
+			}
+		})
+	}
+
+	// Every domain is probed with a random address once.
+	rcpts := 0
+	for _, cmd := range s.commands() {
+		if strings.HasPrefix(cmd, "RCPT") {
+			rcpts++
+		}
+	}
+	if rcpts != 6 {
+		t.Errorf("Verifier.Verify() sent %d RCPT commands, want 6", rcpts)
+	}
+	if got := v.CacheStats(); got.CatchAllHits != 2 || got.CatchAllMisses != 2 {
+		t.Errorf("Verifier.CacheStats() = %+v, want 2 catch-all hits and misses", got)
+	}
+	if _, ok := store.Get("catchall:catchall.com"); !ok {
+		t.Errorf("Verifier.Verify() didn't store the catch-all answer in the cache store")
+	}
+}
@@ -0,0 +1,144 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"net"
+	"net/textproto"
+	"strconv"
+	"strings"
+)
+
+// Capabilities are the SMTP extensions a mail host announced in its reply to EHLO. The methods
+// of a nil Capabilities report that no extension is supported.
+type Capabilities struct {
+	// Host is the mail host that was contacted.
+	Host string
+
+	// Extensions maps the keywords of the announced extensions, in upper case, to their
+	// parameters, ie. "SIZE" to "35882577" and "PIPELINING" to "". It is empty if the host only
+	// supports HELO. After STARTTLS they are the extensions announced over TLS.
+	Extensions map[string]string
+
+	// TLS is the connection negotiated with STARTTLS, nil if the host does not offer it or
+	// refused the command.
+	TLS *TLSState
+}
+
+// Has reports whether the host announced the extension, the keyword is case insensitive.
+func (c *Capabilities) Has(ext string) bool {
+	if c == nil {
+		return false
+	}
+	_, ok := c.Extensions[strings.ToUpper(ext)]
+	return ok
+}
+
+// Size returns the maximum message size in bytes announced with the SIZE extension (RFC 1870).
+// It returns 0 if the host announced no limit or does not support the extension.
+func (c *Capabilities) Size() int64 {
+	if c == nil {
+		return 0
+	}
+	size, _ := strconv.ParseInt(c.Extensions["SIZE"], 10, 64)
+	return size
+}
+
+// StartTLS reports whether the host supports STARTTLS (RFC 3207).
+func (c *Capabilities) StartTLS() bool {
+	return c.Has("STARTTLS")
+}
+
+// SMTPUTF8 reports whether the host accepts internationalized email addresses (RFC 6531).
+func (c *Capabilities) SMTPUTF8() bool {
+	return c.Has("SMTPUTF8")
+}
+
+// Pipelining reports whether the host supports command pipelining (RFC 2920).
+func (c *Capabilities) Pipelining() bool {
+	return c.Has("PIPELINING")
+}
+
+// EightBitMIME reports whether the host accepts 8 bit message bodies (RFC 6152).
+func (c *Capabilities) EightBitMIME() bool {
+	return c.Has("8BITMIME")
+}
+
+// InspectMX connects to the mail host of domain and returns the SMTP extensions it announces,
+// without starting a mail transaction. It uses the default Verifier, see Verifier.InspectMX.
+func InspectMX(ctx context.Context, domain string) (*Capabilities, error) {
+	return defaultVerifier.InspectMX(ctx, domain)
+}
+
+// InspectMX connects to the mail host of domain and returns the SMTP extensions it announces,
+// without starting a mail transaction.
+func (v *Verifier) InspectMX(ctx context.Context, domain string) (*Capabilities, error) {
+	host, err := v.lookupHost(ctx, domain)
+	if err != nil {
+		return nil, err
+	}
+	return v.transaction(ctx, host, EmailAddress{Domain: domain}, false)
+}
+
+// captureConn records what is read from the connection until stop is called, so the reply to
+// EHLO can be parsed after net/smtp has consumed it.
+type captureConn struct {
+	net.Conn
+
+	buf     bytes.Buffer
+	stopped bool
+}
+
+func (c *captureConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if !c.stopped {
+		c.buf.Write(b[:n])
+	}
+	return n, err
+}
+
+// stop ends the recording and returns what was read.
+func (c *captureConn) stop() []byte {
+	c.stopped = true
+	return c.buf.Bytes()
+}
+
+// parseCapabilities parses the greeting of host and its reply to EHLO. A host that rejected EHLO
+// has no extensions.
+func parseCapabilities(host string, data []byte) *Capabilities {
+	c := &Capabilities{Host: host, Extensions: make(map[string]string)}
+	r := textproto.NewReader(bufio.NewReader(bytes.NewReader(data)))
+	if _, _, err := r.ReadResponse(220); err != nil {
+		return c
+	}
+	_, msg, err := r.ReadResponse(250)
+	if err != nil {
+		return c
+	}
+	c.Extensions = parseExtensions(msg)
+	return c
+}
+
+// parseExtensions parses the extensions of msg, a reply to EHLO.
+func parseExtensions(msg string) map[string]string {
+	ext := make(map[string]string)
+	lines := strings.Split(msg, "\n")
+	for _, line := range lines[1:] {
+		keyword, params := line, ""
+		if i := strings.IndexByte(line, ' '); i >= 0 {
+			keyword, params = line[:i], strings.TrimSpace(line[i+1:])
+		}
+		if keyword != "" {
+			ext[strings.ToUpper(keyword)] = params
+		}
+	}
+	return ext
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"testing"
+)
+
+func TestVerifier_InspectMX(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.extensions = []string{"SIZE 35882577", "STARTTLS", "smtputf8", "PIPELINING", "8BITMIME", "AUTH PLAIN LOGIN"}
+	v := NewVerifier()
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+	v.port = s.port()
+
+	got, err := v.InspectMX(context.Background(), "example.com")
+	if err != nil {
+		t.Fatalf("Verifier.InspectMX() error = %v", err)
+	}
+	want := map[string]string{
+		"SIZE":       "35882577",
+		"STARTTLS":   "",
+		"SMTPUTF8":   "",
+		"PIPELINING": "",
+		"8BITMIME":   "",
+		"AUTH":       "PLAIN LOGIN",
+	}
+	if got.Host != s.host() || !reflect.DeepEqual(got.Extensions, want) {
+		t.Errorf("Verifier.InspectMX() = %v %v, want %v %v", got.Host, got.Extensions, s.host(), want)
+	}
+	if got.Size() != 35882577 || !got.StartTLS() || !got.SMTPUTF8() || !got.Pipelining() || !got.EightBitMIME() || !got.Has("auth") {
+		t.Errorf("Capabilities methods of %v returned false", got.Extensions)
+	}
+	for _, cmd := range s.commands() {
+		if cmd != "EHLO example.com" && cmd != "STARTTLS" && cmd != "QUIT" {
+			t.Errorf("Verifier.InspectMX() sent %q, want only EHLO, STARTTLS and QUIT", cmd)
+		}
+	}
+
+	if _, err := v.InspectMX(context.Background(), "example.org"); err == nil {
+		t.Errorf("Verifier.InspectMX() error = nil for a domain without mail host")
+	}
+}
+
+func TestProbeRecord_Capabilities(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(string) string { return "550 no such user" }
+	var got ProbeRecord
+	v := NewVerifier(WithProbeAudit(func(r ProbeRecord) { got = r }))
+	v.port = s.port()
+	if _, err := v.detectCatchAll(context.Background(), s.host(), "example.com"); err != nil {
+		t.Fatalf("Verifier.detectCatchAll() error = %v", err)
+	}
+	if !got.Capabilities.Pipelining() || !got.Capabilities.EightBitMIME() || got.Capabilities.StartTLS() {
+		t.Errorf("ProbeRecord.Capabilities = %v, want PIPELINING and 8BITMIME", got.Capabilities)
+	}
+}
+
+func Test_parseCapabilities(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		want map[string]string
+	}{
+		{"1", "220 mx ESMTP\r\n250-mx\r\n250-SIZE\r\n250 STARTTLS\r\n", map[string]string{"SIZE": "", "STARTTLS": ""}},
+		{"2", "220-mx ESMTP\r\n220 welcome\r\n250 mx\r\n", map[string]string{}},
+		{"3", "220 mx SMTP\r\n502 command not implemented\r\n250 OK\r\n", map[string]string{}},
+		{"4", "", map[string]string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := parseCapabilities("mx", []byte(tt.data)); !reflect.DeepEqual(got.Extensions, tt.want) {
+				t.Errorf("parseCapabilities() = %v, want %v", got.Extensions, tt.want)
+			}
+		})
+	}
+	var c *Capabilities
+	if c.Has("SIZE") || c.Size() != 0 || c.StartTLS() {
+		t.Errorf("nil Capabilities reports extensions")
+	}
+}
@@ -0,0 +1,187 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+)
+
+const (
+	// minProbeLength is the minimum number of random characters in a probe local part, which
+	// makes a collision with an existing mailbox practically impossible.
+	minProbeLength = 12
+
+	atext = "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ0123456789!#$%&'*+-/=?^_`{|}~"
+)
+
+// ProbeConfig configures how the random local parts used for catch-all detection are generated.
+// The random part always contains both letters and digits, so it never looks like the name of a
+// real mailbox.
+type ProbeConfig struct {
+	// Prefix is prepended to the random part, ie. "probe-". It must consist of characters that
+	// are valid in a dot-atom.
+	Prefix string
+
+	// Length is the number of random characters, at least 12. Defaults to 20.
+	Length int
+
+	// Charset holds the characters the random part is drawn from. It must contain both letters
+	// and digits. Defaults to lowercase letters and digits.
+	Charset string
+}
+
+// ProbeRecord is an audit record of a probe made to detect a catch-all domain.
+type ProbeRecord struct {
+	// Address is the generated address that was probed.
+	Address EmailAddress
+
+	// Host is the mail host that was contacted.
+	Host string
+
+	// Time is when the probe started.
+	Time time.Time
+
+	// Accepted reports whether the host accepted the recipient.
+	Accepted bool
+
+	// Err is the error returned by the host, if any.
+	Err error
+
+	// Capabilities are the SMTP extensions announced by the host, nil if it could not be greeted.
+	Capabilities *Capabilities
+}
+
+var defaultProbeConfig = ProbeConfig{
+	Length:  20,
+	Charset: "abcdefghijklmnopqrstuvwxyz0123456789",
+}
+
+// WithProbeConfig sets how the local parts of catch-all probes are generated. Zero fields are set
+// to their defaults.
+func WithProbeConfig(c ProbeConfig) Option {
+	return func(v *Verifier) {
+		if c.Length == 0 {
+			c.Length = defaultProbeConfig.Length
+		}
+		if c.Charset == "" {
+			c.Charset = defaultProbeConfig.Charset
+		}
+		v.probeConfig = c
+	}
+}
+
+// WithProbeAudit registers a function that is called with a record of every catch-all probe.
+func WithProbeAudit(fn func(ProbeRecord)) Option {
+	return func(v *Verifier) {
+		v.probeAudit = fn
+	}
+}
+
+// DetectCatchAll reports whether the mail host of domain accepts mail for any local part, by
+// probing it with a randomly generated address. Such hosts can't be used to verify whether a
+// single address exists.
+func (v *Verifier) DetectCatchAll(ctx context.Context, domain string) (bool, error) {
+	host, err := v.lookupHost(ctx, domain)
+	if err != nil {
+		return false, err
+	}
+	return v.detectCatchAll(ctx, host, domain)
+}
+
+// detectCatchAll probes host with a random address at domain, unless the answer for domain is
+// cached. Without RCPT TO the host can't tell, so the error of notProbed is returned and nothing
+// is cached.
+func (v *Verifier) detectCatchAll(ctx context.Context, host, domain string) (bool, error) {
+	key := asciiDomain(domain)
+	if c, ok := v.catchAllCache.get(key); ok {
+		return c.CatchAll, nil
+	}
+	if err := v.notProbed(); err != nil {
+		return false, err
+	}
+	local, err := v.probeConfig.generate()
+	if err != nil {
+		return false, err
+	}
+
+	r := ProbeRecord{
+		Address: EmailAddress{LocalPart: local, Domain: domain},
+		Host:    host,
+		Time:    time.Now(),
+	}
+	r.Capabilities, err = v.transaction(ctx, host, r.Address, true)
+	r.Accepted = err == nil
+	r.Err = err
+	if v.probeAudit != nil {
+		v.probeAudit(r)
+	}
+
+	if err != nil {
+		if ErrorCode(err) == CodeMailboxRejected {
+			v.catchAllCache.put(cacheEntry{Key: key}, nil)
+			return false, nil
+		}
+		return false, err
+	}
+	v.catchAllCache.put(cacheEntry{Key: key, CatchAll: true}, nil)
+	return true, nil
+}
+
+// validate checks if the configuration generates valid local parts that can't collide with
+// plausible mailboxes.
+func (c ProbeConfig) validate() error {
+	if c.Length < minProbeLength {
+		return fmt.Errorf("probe length must be at least %d, got %d", minProbeLength, c.Length)
+	}
+	if !strings.ContainsAny(c.Charset, "0123456789") ||
+		!strings.ContainsAny(c.Charset, "abcdefghijklmnopqrstuvwxyzABCDEFGHIJKLMNOPQRSTUVWXYZ") {
+		return fmt.Errorf("probe charset must contain both letters and digits, got %q", c.Charset)
+	}
+	for _, r := range c.Prefix + c.Charset {
+		if !strings.ContainsRune(atext, r) && (r != '.' || strings.ContainsRune(c.Charset, r)) {
+			return fmt.Errorf("probe character %q is not valid in a local part", r)
+		}
+	}
+	if strings.HasPrefix(c.Prefix, ".") || strings.Contains(c.Prefix, "..") {
+		return fmt.Errorf("probe prefix %q is not a valid dot-atom", c.Prefix)
+	}
+	return nil
+}
+
+// generate returns a random local part, the random part contains at least one letter and one
+// digit.
+func (c ProbeConfig) generate() (string, error) {
+	if err := c.validate(); err != nil {
+		return "", err
+	}
+	charset := []rune(c.Charset)
+	max := big.NewInt(int64(len(charset)))
+	random := make([]rune, c.Length)
+	for {
+		for i := range random {
+			n, err := rand.Int(rand.Reader, max)
+			if err != nil {
+				return "", err
+			}
+			random[i] = charset[n.Int64()]
+		}
+		s := string(random)
+		if strings.ContainsAny(s, "0123456789") && strings.IndexFunc(s, isLetter) >= 0 {
+			return c.Prefix + s, nil
+		}
+	}
+}
+
+func isLetter(r rune) bool {
+	return r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z'
+}
@@ -0,0 +1,92 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"strings"
+	"testing"
+)
+
+func TestProbeConfig_generate(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ProbeConfig
+		wantErr bool
+	}{
+		{"1", defaultProbeConfig, false},
+		{"2", ProbeConfig{Prefix: "probe-", Length: 12, Charset: "abc123"}, false},
+		{"3", ProbeConfig{Prefix: "x.", Length: 16, Charset: "ABCDEF0123456789"}, false},
+		{"4", ProbeConfig{Length: 8, Charset: "abc123"}, true},
+		{"5", ProbeConfig{Length: 20, Charset: "abcdef"}, true},
+		{"6", ProbeConfig{Length: 20, Charset: "0123456789"}, true},
+		{"7", ProbeConfig{Length: 20, Charset: "abc 123"}, true},
+		{"8", ProbeConfig{Prefix: "a@b", Length: 20, Charset: "abc123"}, true},
+		{"9", ProbeConfig{Prefix: ".probe", Length: 20, Charset: "abc123"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.generate()
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ProbeConfig.generate() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if tt.wantErr {
+				return
+			}
+			random := strings.TrimPrefix(got, tt.config.Prefix)
+			if len(random) != tt.config.Length || !strings.ContainsAny(random, "0123456789") || strings.IndexFunc(random, isLetter) < 0 {
+				t.Errorf("ProbeConfig.generate() = %v, want %d random letters and digits", got, tt.config.Length)
+			}
+			if _, err := Parse(got + "@example.com"); err != nil {
+				t.Errorf("ProbeConfig.generate() = %v, not a valid local part: %v", got, err)
+			}
+		})
+	}
+}
+
+func TestVerifier_detectCatchAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		reply   string
+		want    bool
+		wantErr bool
+	}{
+		{"1", "250 OK", true, false},
+		{"2", "550 no such user", false, false},
+		{"3", "450 try again later", false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0")
+			s.rcpt = func(string) string { return tt.reply }
+
+			var records []ProbeRecord
+			v := NewVerifier(
+				WithProbeConfig(ProbeConfig{Prefix: "probe-"}),
+				WithProbeAudit(func(r ProbeRecord) { records = append(records, r) }),
+			)
+			v.port = s.port()
+			got, err := v.detectCatchAll(context.Background(), s.host(), "example.com")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.detectCatchAll() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Verifier.detectCatchAll() = %v, want %v", got, tt.want)
+			}
+			if len(records) != 1 {
+				t.Fatalf("got %d probe records, want 1", len(records))
+			}
+			r := records[0]
+			if !strings.HasPrefix(r.Address.LocalPart, "probe-") || r.Address.Domain != "example.com" || r.Host != s.host() || r.Accepted != tt.want {
+				t.Errorf("unexpected probe record %+v", r)
+			}
+		})
+	}
+}
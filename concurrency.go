@@ -0,0 +1,175 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WithMaxConnections limits the number of simultaneous SMTP connections of the Verifier to n.
+// Probes beyond the limit wait for a connection to finish, or for their context to be done. Large
+// mail providers treat many simultaneous connections from one IP address as an attack, so set a
+// limit when verifying in bulk. A limit of zero or less means no limit, which is the default.
+func WithMaxConnections(n int) Option {
+	return func(v *Verifier) {
+		v.connLimit.global = newSemaphore(n)
+	}
+}
+
+// WithMaxConnectionsPerHost limits the number of simultaneous SMTP connections of the Verifier to
+// a single mail host to n, on top of the limit of WithMaxConnections. A limit of zero or less
+// means no limit, which is the default.
+func WithMaxConnectionsPerHost(n int) Option {
+	return func(v *Verifier) {
+		v.connLimit.perHost = n
+	}
+}
+
+// WithMinHostInterval sets the minimum time between the start of two SMTP connections of the
+// Verifier to a single mail host, so hosts aren't probed faster than they allow. Probes wait for
+// their turn, or for their context to be done. Zero or less means no interval, which is the
+// default.
+func WithMinHostInterval(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.connLimit.interval = d
+	}
+}
+
+// semaphore limits the number of concurrent holders, a nil semaphore has no limit.
+type semaphore chan struct{}
+
+func newSemaphore(n int) semaphore {
+	if n <= 0 {
+		return nil
+	}
+	return make(semaphore, n)
+}
+
+func (s semaphore) acquire(ctx context.Context) error {
+	if s == nil {
+		return nil
+	}
+	select {
+	case s <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (s semaphore) release() {
+	if s != nil {
+		<-s
+	}
+}
+
+// connLimiter enforces the connection limits of a Verifier.
+type connLimiter struct {
+	global   semaphore
+	perHost  int
+	interval time.Duration
+
+	mu    sync.Mutex
+	hosts map[string]*hostSemaphore
+	next  map[string]time.Time // the time the next connection to a host may start
+}
+
+// maxIntervalHosts is the number of hosts with a pending interval after which the hosts whose
+// interval has passed are forgotten.
+const maxIntervalHosts = 1024
+
+// hostSemaphore is the semaphore of a host, it is removed once it has no more users.
+type hostSemaphore struct {
+	sem   semaphore
+	users int
+}
+
+// acquire waits until a connection to host is allowed, the returned function must be called when
+// the connection is closed.
+func (l *connLimiter) acquire(ctx context.Context, host string) (func(), error) {
+	host = strings.ToLower(host)
+	if err := l.wait(ctx, host); err != nil {
+		return nil, err
+	}
+	if err := l.global.acquire(ctx); err != nil {
+		return nil, err
+	}
+	if l.perHost <= 0 {
+		return l.global.release, nil
+	}
+
+	l.mu.Lock()
+	if l.hosts == nil {
+		l.hosts = make(map[string]*hostSemaphore)
+	}
+	h, ok := l.hosts[host]
+	if !ok {
+		h = &hostSemaphore{sem: newSemaphore(l.perHost)}
+		l.hosts[host] = h
+	}
+	h.users++
+	l.mu.Unlock()
+
+	done := func() {
+		l.mu.Lock()
+		if h.users--; h.users == 0 {
+			delete(l.hosts, host)
+		}
+		l.mu.Unlock()
+	}
+	if err := h.sem.acquire(ctx); err != nil {
+		done()
+		l.global.release()
+		return nil, err
+	}
+	return func() {
+		h.sem.release()
+		done()
+		l.global.release()
+	}, nil
+}
+
+// wait reserves the next turn of host and waits for it, if connections to hosts are spaced by an
+// interval.
+func (l *connLimiter) wait(ctx context.Context, host string) error {
+	if l.interval <= 0 {
+		return nil
+	}
+	now := time.Now()
+	l.mu.Lock()
+	if l.next == nil {
+		l.next = make(map[string]time.Time)
+	}
+	if len(l.next) >= maxIntervalHosts {
+		for h, t := range l.next {
+			if t.Before(now) {
+				delete(l.next, h)
+			}
+		}
+	}
+	turn := l.next[host]
+	if turn.Before(now) {
+		turn = now
+	}
+	l.next[host] = turn.Add(l.interval)
+	l.mu.Unlock()
+
+	if d := turn.Sub(now); d > 0 {
+		timer := time.NewTimer(d)
+		defer timer.Stop()
+		select {
+		case <-timer.C:
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestWithMaxConnections(t *testing.T) {
+	tests := []struct {
+		name string
+		opts []Option
+		want int
+	}{
+		{"1", []Option{WithMaxConnections(3)}, 3},
+		{"2", []Option{WithMaxConnectionsPerHost(2)}, 2},
+		{"3", []Option{WithMaxConnections(3), WithMaxConnectionsPerHost(1)}, 1},
+		{"4", []Option{WithMaxConnections(0)}, 8},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0")
+			var mu sync.Mutex
+			var active, max int
+			s.rcpt = func(string) string {
+				mu.Lock()
+				if active++; active > max {
+					max = active
+				}
+				mu.Unlock()
+				time.Sleep(100 * time.Millisecond)
+				mu.Lock()
+				active--
+				mu.Unlock()
+				return "250 OK"
+			}
+
+			v := NewVerifier(tt.opts...)
+			v.port = s.port()
+			var wg sync.WaitGroup
+			for i := 0; i < 8; i++ {
+				wg.Add(1)
+				go func() {
+					defer wg.Done()
+					if err := v.TryHost(context.Background(), s.host(), EmailAddress{"info", "example.com"}); err != nil {
+						t.Errorf("Verifier.TryHost() error = %v", err)
+					}
+				}()
+			}
+			wg.Wait()
+			if max != tt.want {
+				t.Errorf("simultaneous connections = %v, want %v", max, tt.want)
+			}
+			if len(v.connLimit.hosts) != 0 {
+				t.Errorf("host semaphores = %v, want none", v.connLimit.hosts)
+			}
+		})
+	}
+}
+
+func TestWithMaxConnectionsPerHost_context(t *testing.T) {
+	v := NewVerifier(WithMaxConnections(2), WithMaxConnectionsPerHost(1))
+	release, err := v.connLimit.acquire(context.Background(), "mx.example.com")
+	if err != nil {
+		t.Fatalf("connLimiter.acquire() error = %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	err = v.TryHost(ctx, "MX.example.com", EmailAddress{"info", "example.com"})
+	if !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("Verifier.TryHost() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+
+	other, err := v.connLimit.acquire(context.Background(), "mx.example.org")
+	if err != nil {
+		t.Fatalf("connLimiter.acquire() other host error = %v", err)
+	}
+	other()
+	release()
+	if len(v.connLimit.hosts) != 0 || len(v.connLimit.global) != 0 {
+		t.Errorf("connLimiter not released: %v hosts, %v connections", len(v.connLimit.hosts), len(v.connLimit.global))
+	}
+}
+
+func TestWithMinHostInterval(t *testing.T) {
+	const interval = 50 * time.Millisecond
+	s := newTestServer(t, "127.0.0.1:0")
+	v := NewVerifier(WithMinHostInterval(interval))
+	v.port = s.port()
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if err := v.TryHost(context.Background(), s.host(), EmailAddress{"info", "example.com"}); err != nil {
+			t.Fatalf("Verifier.TryHost() error = %v", err)
+		}
+	}
+	if d := time.Since(start); d < 2*interval {
+		t.Errorf("3 probes of one host took %v, want at least %v", d, 2*interval)
+	}
+
+	start = time.Now()
+	release, err := v.connLimit.acquire(context.Background(), "mx.example.org")
+	if err != nil {
+		t.Fatalf("connLimiter.acquire() other host error = %v", err)
+	}
+	release()
+	if d := time.Since(start); d >= interval {
+		t.Errorf("probe of another host waited %v, want no wait", d)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	v = NewVerifier(WithMinHostInterval(time.Hour))
+	if release, err = v.connLimit.acquire(ctx, "mx.example.com"); err != nil {
+		t.Fatalf("connLimiter.acquire() error = %v", err)
+	}
+	release()
+	if _, err = v.connLimit.acquire(ctx, "MX.example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("connLimiter.acquire() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
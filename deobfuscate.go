@@ -0,0 +1,316 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+// DeobfuscationRule rewrites an obfuscated notation, such as "[at]", back to the characters of an
+// email address.
+type DeobfuscationRule struct {
+	// Name identifies the rule within a Deobfuscator.
+	Name string
+
+	// Pattern matches the obfuscated notation.
+	Pattern *regexp.Regexp
+
+	// Replacement replaces every match of Pattern, $1 and ${name} are expanded as in
+	// regexp.Regexp.ReplaceAll.
+	Replacement string
+
+	// Priority determines the order in which the rules are applied, higher priorities first.
+	// Rules with the same priority are applied in the order they were added. When the patterns
+	// of two rules overlap the rule applied first wins, as its replacement is what the next rule
+	// sees.
+	Priority int
+
+	// Confidence is how surely the notation is an obfuscated address rather than ordinary text,
+	// defaults to ConfidenceMedium. See FindWithConfidence.
+	Confidence Confidence
+}
+
+// Confidence is how surely a deobfuscated match is an email address.
+type Confidence int
+
+const (
+	// ConfidenceLow indicates a notation that also appears in ordinary text, such as " AT " or
+	// spaces around the @.
+	ConfidenceLow Confidence = iota + 1
+
+	// ConfidenceMedium indicates a notation that is only used to obfuscate addresses, such as
+	// "[at]" or a fullwidth ＠.
+	ConfidenceMedium
+
+	// ConfidenceHigh indicates an address that was not obfuscated.
+	ConfidenceHigh
+)
+
+func (c Confidence) String() string {
+	switch c {
+	case ConfidenceLow:
+		return "low"
+	case ConfidenceMedium:
+		return "medium"
+	case ConfidenceHigh:
+		return "high"
+	default:
+		return fmt.Sprintf("Confidence(%d)", int(c))
+	}
+}
+
+func (r DeobfuscationRule) confidence() Confidence {
+	if r.Confidence == 0 {
+		return ConfidenceMedium
+	}
+	return r.Confidence
+}
+
+// DefaultDeobfuscator holds the built-in rules used by FindObfuscated, register rules to extend
+// it with your own notations. It rewrites bracketed notations such as "[at]" and "(dot)", the
+// fullwidth ＠ and ．, the uppercase words " AT " and " DOT ", and spaces around the @.
+var DefaultDeobfuscator = NewDeobfuscator(
+	DeobfuscationRule{
+		Name:        "bracketed-at",
+		Pattern:     regexp.MustCompile(`(?i)\s*[\[({<]\s*at\s*[\])}>]\s*`),
+		Replacement: "@",
+		Priority:    100,
+	},
+	DeobfuscationRule{
+		Name:        "bracketed-dot",
+		Pattern:     regexp.MustCompile(`(?i)\s*[\[({<]\s*dot\s*[\])}>]\s*`),
+		Replacement: ".",
+		Priority:    100,
+	},
+	DeobfuscationRule{
+		Name:        "fullwidth-at",
+		Pattern:     regexp.MustCompile(`[＠﹫]`),
+		Replacement: "@",
+		Priority:    100,
+	},
+	DeobfuscationRule{
+		Name:        "fullwidth-dot",
+		Pattern:     regexp.MustCompile(`．`),
+		Replacement: ".",
+		Priority:    100,
+	},
+	DeobfuscationRule{
+		Name:        "word-at",
+		Pattern:     regexp.MustCompile(`\s+AT\s+`),
+		Replacement: "@",
+		Priority:    50,
+		Confidence:  ConfidenceLow,
+	},
+	DeobfuscationRule{
+		Name:        "word-dot",
+		Pattern:     regexp.MustCompile(`\s+DOT\s+`),
+		Replacement: ".",
+		Priority:    50,
+		Confidence:  ConfidenceLow,
+	},
+	DeobfuscationRule{
+		Name:        "spaced-at",
+		Pattern:     regexp.MustCompile(`[ \t]+@[ \t]*|@[ \t]+`),
+		Replacement: "@",
+		Priority:    40,
+		Confidence:  ConfidenceLow,
+	},
+)
+
+// Deobfuscator rewrites obfuscated email addresses using a set of rules. A Deobfuscator is safe
+// for concurrent use by multiple goroutines.
+type Deobfuscator struct {
+	mu    sync.RWMutex
+	rules []DeobfuscationRule
+}
+
+// NewDeobfuscator returns a Deobfuscator with the given rules, a rule replaces an earlier rule
+// with the same name.
+func NewDeobfuscator(rules ...DeobfuscationRule) *Deobfuscator {
+	d := &Deobfuscator{}
+	for _, r := range rules {
+		d.Replace(r)
+	}
+	return d
+}
+
+// Register adds a rule. It returns an error if a rule with the same name is already registered,
+// use Replace to overwrite it.
+func (d *Deobfuscator) Register(r DeobfuscationRule) error {
+	if r.Pattern == nil {
+		return fmt.Errorf("deobfuscation rule %q has no pattern", r.Name)
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if d.index(r.Name) >= 0 {
+		return fmt.Errorf("deobfuscation rule %q is already registered", r.Name)
+	}
+	d.insert(r)
+	return nil
+}
+
+// Replace adds a rule or replaces the rule with the same name.
+func (d *Deobfuscator) Replace(r DeobfuscationRule) {
+	if r.Pattern == nil {
+		return
+	}
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.remove(r.Name)
+	d.insert(r)
+}
+
+// Remove removes the rule with the given name and reports whether it was registered.
+func (d *Deobfuscator) Remove(name string) bool {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.remove(name)
+}
+
+// Rules returns the registered rules in the order they are applied.
+func (d *Deobfuscator) Rules() []DeobfuscationRule {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	return append([]DeobfuscationRule(nil), d.rules...)
+}
+
+// Deobfuscate applies every rule to haystack.
+func (d *Deobfuscator) Deobfuscate(haystack []byte) []byte {
+	b, _ := d.deobfuscate(haystack)
+	return b
+}
+
+// deobfuscate applies every rule to b like Deobfuscate, and returns the confidence of every byte
+// of the result: the lowest confidence of the rules that replaced it, or ConfidenceHigh.
+func (d *Deobfuscator) deobfuscate(b []byte) ([]byte, []Confidence) {
+	d.mu.RLock()
+	defer d.mu.RUnlock()
+	conf := make([]Confidence, len(b))
+	for i := range conf {
+		conf[i] = ConfidenceHigh
+	}
+	for _, r := range d.rules {
+		matches := r.Pattern.FindAllSubmatchIndex(b, -1)
+		if matches == nil {
+			continue
+		}
+		out, outConf := make([]byte, 0, len(b)), make([]Confidence, 0, len(conf))
+		last := 0
+		for _, m := range matches {
+			out, outConf = append(out, b[last:m[0]]...), append(outConf, conf[last:m[0]]...)
+			c := r.confidence()
+			for _, mc := range conf[m[0]:m[1]] {
+				if mc < c {
+					c = mc
+				}
+			}
+			n := len(out)
+			out = r.Pattern.Expand(out, []byte(r.Replacement), b, m)
+			for len(outConf) < len(out) {
+				outConf = append(outConf, c)
+			}
+			// A replacement that removes the match lowers the confidence of the byte before it.
+			if len(out) == n && n > 0 && c < outConf[n-1] {
+				outConf[n-1] = c
+			}
+			last = m[1]
+		}
+		b, conf = append(out, b[last:]...), append(outConf, conf[last:]...)
+	}
+	return b, conf
+}
+
+// Find deobfuscates haystack and returns the email addresses found, see Find.
+func (d *Deobfuscator) Find(haystack []byte, validateHost bool, opts ...ParseOption) []*EmailAddress {
+	return Find(d.Deobfuscate(newParseOptions(opts).prepare(haystack)), validateHost)
+}
+
+// FindObfuscated finds email addresses like Find, but first rewrites obfuscated notations such as
+// "foo [at] bar [dot] com" using the rules of DefaultDeobfuscator.
+func FindObfuscated(haystack []byte, validateHost bool, opts ...ParseOption) []*EmailAddress {
+	return DefaultDeobfuscator.Find(haystack, validateHost, opts...)
+}
+
+// DeobfuscatedAddress is an email address found by FindWithConfidence.
+type DeobfuscatedAddress struct {
+	Address *EmailAddress
+
+	// Confidence is the lowest confidence of the rules that rewrote the address, ConfidenceHigh if
+	// it was not obfuscated.
+	Confidence Confidence
+}
+
+// FindWithConfidence deobfuscates haystack and returns the email addresses found, like Find, with
+// the confidence of the rules that rewrote them, so addresses reconstructed from notations that
+// also appear in ordinary text can be told apart.
+func (d *Deobfuscator) FindWithConfidence(haystack []byte, opts ...ParseOption) []DeobfuscatedAddress {
+	o := newParseOptions(opts)
+	b, conf := d.deobfuscate(o.prepare(haystack))
+	var found []DeobfuscatedAddress
+	for start := 0; start < len(b); {
+		loc := findCommonRegexp.FindIndex(b[start:])
+		if loc == nil {
+			break
+		}
+		i, j := start+loc[0], start+loc[1]
+		start = j
+		if o.lengthLimits() && j-i > maxAddressLength {
+			continue
+		}
+		e, err := o.parse(string(b[i:j]))
+		if err != nil || o.skip(e) {
+			continue
+		}
+		c := ConfidenceHigh
+		for _, bc := range conf[i:j] {
+			if bc < c {
+				c = bc
+			}
+		}
+		found = append(found, DeobfuscatedAddress{Address: e, Confidence: c})
+		if o.hardened && len(found) >= maxHardenedResults {
+			break
+		}
+	}
+	return found
+}
+
+// FindObfuscatedWithConfidence finds email addresses like FindObfuscated, with the confidence of
+// the rules of DefaultDeobfuscator that rewrote them. See Deobfuscator.FindWithConfidence.
+func FindObfuscatedWithConfidence(haystack []byte, opts ...ParseOption) []DeobfuscatedAddress {
+	return DefaultDeobfuscator.FindWithConfidence(haystack, opts...)
+}
+
+func (d *Deobfuscator) index(name string) int {
+	for i, r := range d.rules {
+		if r.Name == name {
+			return i
+		}
+	}
+	return -1
+}
+
+// insert adds r after the rules with a higher or equal priority.
+func (d *Deobfuscator) insert(r DeobfuscationRule) {
+	i := sort.Search(len(d.rules), func(i int) bool {
+		return d.rules[i].Priority < r.Priority
+	})
+	d.rules = append(d.rules, DeobfuscationRule{})
+	copy(d.rules[i+1:], d.rules[i:])
+	d.rules[i] = r
+}
+
+func (d *Deobfuscator) remove(name string) bool {
+	i := d.index(name)
+	if i < 0 {
+		return false
+	}
+	d.rules = append(d.rules[:i], d.rules[i+1:]...)
+	return true
+}
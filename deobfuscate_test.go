@@ -0,0 +1,130 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"regexp"
+	"testing"
+)
+
+func TestFindObfuscated(t *testing.T) {
+	tests := []struct {
+		name       string
+		haystack   []byte
+		wantEmails []*EmailAddress
+	}{
+		{"1", []byte(`Mail foo [at] bar [dot] com.`), []*EmailAddress{{"foo", "bar.com"}}},
+		{"2", []byte(`Mail foo(AT)bar(DOT)co(dot)uk or baz{at}bar{dot}com`), []*EmailAddress{{"foo", "bar.co.uk"}, {"baz", "bar.com"}}},
+		{"3", []byte(`Mail foo@bar.com`), []*EmailAddress{{"foo", "bar.com"}}},
+		{"4", []byte(`Look at that, a dot.`), nil},
+		{"5", []byte(`Mail foo AT bar DOT com or baz ＠ bar．com`), []*EmailAddress{{"foo", "bar.com"}, {"baz", "bar.com"}}},
+		{"6", []byte(`Mail foo @ bar.com, meet me @ noon.`), []*EmailAddress{{"foo", "bar.com"}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if gotEmails := FindObfuscated(tt.haystack, false); !reflect.DeepEqual(gotEmails, tt.wantEmails) {
+				t.Errorf("FindObfuscated() = %v, want %v", gotEmails, tt.wantEmails)
+			}
+		})
+	}
+}
+
+func TestDeobfuscator_Register(t *testing.T) {
+	defaults := len(DefaultDeobfuscator.Rules())
+	d := NewDeobfuscator(DefaultDeobfuscator.Rules()...)
+	rules := []DeobfuscationRule{
+		{Name: "chez", Pattern: regexp.MustCompile(`(?i)\s+chez\s+`), Replacement: "@"},
+		{Name: "arroba", Pattern: regexp.MustCompile(`(?i)\s+arroba\s+`), Replacement: "@"},
+		{Name: "punkt", Pattern: regexp.MustCompile(`(?i)\s+punkt\s+`), Replacement: "."},
+	}
+	for _, r := range rules {
+		if err := d.Register(r); err != nil {
+			t.Fatalf("Deobfuscator.Register() error = %v", err)
+		}
+	}
+	if err := d.Register(rules[0]); err == nil {
+		t.Errorf("Deobfuscator.Register() duplicate rule error = nil, want error")
+	}
+	if err := d.Register(DeobfuscationRule{Name: "nil"}); err == nil {
+		t.Errorf("Deobfuscator.Register() rule without pattern error = nil, want error")
+	}
+
+	got := d.Find([]byte(`jean chez exemple punkt fr, juan arroba ejemplo [dot] es`), false)
+	want := []*EmailAddress{{"jean", "exemple.fr"}, {"juan", "ejemplo.es"}}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("Deobfuscator.Find() = %v, want %v", got, want)
+	}
+	if len(DefaultDeobfuscator.Rules()) != defaults {
+		t.Errorf("registering rules on a copy modified DefaultDeobfuscator")
+	}
+}
+
+func TestDeobfuscator_priority(t *testing.T) {
+	// "(at) " is seen as "@" by the specific rule if that is applied first, the generic rule
+	// would turn it into "at " otherwise.
+	specific := DeobfuscationRule{Name: "specific", Pattern: regexp.MustCompile(`\(at\) `), Replacement: "@"}
+	generic := DeobfuscationRule{Name: "generic", Pattern: regexp.MustCompile(`[()]`), Replacement: ""}
+	tests := []struct {
+		name     string
+		specific int
+		generic  int
+		want     string
+	}{
+		{"1", 10, 0, "foo@bar.com"},
+		{"2", 0, 10, "fooat bar.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			specific.Priority, generic.Priority = tt.specific, tt.generic
+			d := NewDeobfuscator(generic, specific)
+			if got := string(d.Deobfuscate([]byte("foo(at) bar.com"))); got != tt.want {
+				t.Errorf("Deobfuscator.Deobfuscate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDeobfuscator_Replace(t *testing.T) {
+	d := NewDeobfuscator(DefaultDeobfuscator.Rules()...)
+	d.Remove("spaced-at") // keeps the spaces the replaced rule leaves
+	d.Replace(DeobfuscationRule{Name: "bracketed-at", Pattern: regexp.MustCompile(`\[at\]`), Replacement: "@", Priority: 100})
+	if got := string(d.Deobfuscate([]byte("foo [at] bar [dot] com"))); got != "foo @ bar.com" {
+		t.Errorf("Deobfuscator.Deobfuscate() = %q, want %q", got, "foo @ bar.com")
+	}
+	if !d.Remove("bracketed-at") || d.Remove("bracketed-at") {
+		t.Errorf("Deobfuscator.Remove() did not remove the rule once")
+	}
+	if got, want := len(d.Rules()), len(DefaultDeobfuscator.Rules())-2; got != want {
+		t.Errorf("len(Deobfuscator.Rules()) = %d, want %d", got, want)
+	}
+}
+
+func TestFindObfuscatedWithConfidence(t *testing.T) {
+	tests := []struct {
+		name     string
+		haystack string
+		want     []Confidence
+	}{
+		{"1", "foo@bar.com", []Confidence{ConfidenceHigh}},
+		{"2", "foo [at] bar [dot] com and baz＠bar.com", []Confidence{ConfidenceMedium, ConfidenceMedium}},
+		{"3", "foo AT bar DOT com", []Confidence{ConfidenceLow}},
+		{"4", "foo @ bar.com", []Confidence{ConfidenceLow}},
+		{"5", "foo [at] bar DOT com, baz@bar.com", []Confidence{ConfidenceLow, ConfidenceHigh}},
+		{"6", "Look at that, a dot.", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []Confidence
+			for _, a := range FindObfuscatedWithConfidence([]byte(tt.haystack)) {
+				got = append(got, a.Confidence)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindObfuscatedWithConfidence() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
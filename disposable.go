@@ -0,0 +1,67 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	_ "embed" // for the disposable domains
+	"strings"
+	"sync"
+)
+
+//go:generate sh -c "{ echo '# Disposable email domains, one per line. Regenerate with go generate.'; curl -fsSL https://raw.githubusercontent.com/disposable-email-domains/disposable-email-domains/master/disposable_email_blocklist.conf; } > disposable_domains.txt"
+
+//go:embed disposable_domains.txt
+var disposableDomains string
+
+// DisposableChecker decides whether a domain belongs to a disposable email provider, such as
+// mailinator.com. IsDisposable is called with lowercased domains without a trailing dot.
+// Implementations must be safe for concurrent use.
+type DisposableChecker interface {
+	IsDisposable(domain string) bool
+}
+
+// DefaultDisposableChecker is used by EmailAddress.IsDisposable. It checks the domain and its
+// parent domains against the list of DisposableDomains. Replace it to use a list of your own or
+// one that is updated remotely.
+var DefaultDisposableChecker DisposableChecker = NewDisposableChecker(DisposableDomains)
+
+// NewDisposableChecker returns a DisposableChecker reporting the domains of the list returned by
+// list as disposable, and their subdomains. list is called once, when the first domain is checked.
+func NewDisposableChecker(list func() DomainList) DisposableChecker {
+	return &listChecker{list: list}
+}
+
+type listChecker struct {
+	list func() DomainList
+
+	once    sync.Once
+	domains DomainList
+}
+
+func (c *listChecker) IsDisposable(domain string) bool {
+	c.once.Do(func() { c.domains = c.list() })
+	return listed(c.domains, domain)
+}
+
+var (
+	disposableOnce     sync.Once
+	embeddedDisposable DomainList
+)
+
+// DisposableDomains returns the list of disposable email domains that is embedded in the package,
+// ie. to reject them with WithDisposableList.
+func DisposableDomains() DomainList {
+	disposableOnce.Do(func() {
+		embeddedDisposable = NewCompactDomainList(domainLines(disposableDomains))
+	})
+	return embeddedDisposable
+}
+
+// IsDisposable reports whether the domain of the email address belongs to a disposable email
+// provider according to DefaultDisposableChecker.
+func (e EmailAddress) IsDisposable() bool {
+	return DefaultDisposableChecker.IsDisposable(strings.TrimSuffix(strings.ToLower(asciiDomain(e.Domain)), "."))
+}
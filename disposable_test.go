@@ -0,0 +1,69 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"testing"
+)
+
+func TestEmailAddress_IsDisposable(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want bool
+	}{
+		{"1", EmailAddress{"foo", "mailinator.com"}, true},
+		{"2", EmailAddress{"foo", "GuerrillaMail.com"}, true},
+		{"3", EmailAddress{"foo", "eu.mailinator.com"}, true},
+		{"4", EmailAddress{"foo", "yopmail.fr."}, true},
+		{"5", EmailAddress{"foo", "gmail.com"}, false},
+		{"6", EmailAddress{"foo", "notmailinator.org"}, false},
+		{"7", EmailAddress{"foo", "[192.0.2.1]"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsDisposable(); got != tt.want {
+				t.Errorf("EmailAddress.IsDisposable() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+type disposableFunc func(string) bool
+
+func (f disposableFunc) IsDisposable(domain string) bool { return f(domain) }
+
+func TestDefaultDisposableChecker(t *testing.T) {
+	defer func(c DisposableChecker) { DefaultDisposableChecker = c }(DefaultDisposableChecker)
+	var checked string
+	DefaultDisposableChecker = disposableFunc(func(domain string) bool {
+		checked = domain
+		return domain == "xn--bcher-kva.example"
+	})
+	if !(EmailAddress{"foo", "Bücher.example"}).IsDisposable() || checked != "xn--bcher-kva.example" {
+		t.Errorf("EmailAddress.IsDisposable() checked %q, want the lowercased ASCII domain", checked)
+	}
+	if (EmailAddress{"foo", "mailinator.com"}).IsDisposable() {
+		t.Errorf("EmailAddress.IsDisposable() = true, want the result of DefaultDisposableChecker")
+	}
+}
+
+func TestNewDisposableChecker(t *testing.T) {
+	calls := 0
+	c := NewDisposableChecker(func() DomainList {
+		calls++
+		return NewDomainList([]string{"burner.example"})
+	})
+	if calls != 0 {
+		t.Errorf("NewDisposableChecker() loaded the list %d times, want it loaded on first use", calls)
+	}
+	if !c.IsDisposable("mail.burner.example") || c.IsDisposable("example") || calls != 1 {
+		t.Errorf("DisposableChecker.IsDisposable() with %d loads, want subdomains listed and 1 load", calls)
+	}
+	if n := DisposableDomains().(*compactList).offsets; len(n) < 100 {
+		t.Errorf("DisposableDomains() has %d domains, want the embedded list", len(n)-1)
+	}
+}
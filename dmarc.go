@@ -0,0 +1,161 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// DMARCRecord is a parsed DMARC record (RFC 7489), as published at _dmarc followed by the domain.
+type DMARCRecord struct {
+	// Domain is the domain the record was published for, the organizational domain when the
+	// domain itself has none. It is empty if no record was found.
+	Domain string
+
+	// Record is the TXT record, it is empty if no record was found.
+	Record string
+
+	// Policy is the requested handling of mail that fails DMARC: none, quarantine or reject.
+	Policy string
+
+	// SubdomainPolicy is the policy for subdomains, it defaults to Policy.
+	SubdomainPolicy string
+
+	// AlignDKIM and AlignSPF are the alignment modes of DKIM and SPF, r for relaxed or s for
+	// strict. They default to r.
+	AlignDKIM string
+	AlignSPF  string
+
+	// Percent is the percentage of failing mail the policy applies to, it defaults to 100.
+	Percent int
+
+	// AggregateReports and FailureReports are the URIs of the rua and ruf tags, ie.
+	// mailto:dmarc@example.com.
+	AggregateReports []string
+	FailureReports   []string
+}
+
+// Exists reports whether a DMARC record was found.
+func (r DMARCRecord) Exists() bool {
+	return r.Record != ""
+}
+
+// Enforced reports whether failing mail is quarantined or rejected.
+func (r DMARCRecord) Enforced() bool {
+	return (r.Policy == "quarantine" || r.Policy == "reject") && r.Percent > 0
+}
+
+// ParseDMARC parses a DMARC record, ie. v=DMARC1; p=reject; rua=mailto:dmarc@example.com.
+// A record without a valid policy but with aggregate report URIs has policy none, as per RFC 7489
+// section 6.6.3.
+func ParseDMARC(record string) (*DMARCRecord, error) {
+	if !isDMARC(record) {
+		return nil, fmt.Errorf("invalid DMARC record, missing v=DMARC1: %q", record)
+	}
+	r := &DMARCRecord{Record: record, AlignDKIM: "r", AlignSPF: "r", Percent: 100}
+	for _, tag := range strings.Split(record, ";")[1:] {
+		i := strings.IndexByte(tag, '=')
+		if i < 0 {
+			if strings.TrimSpace(tag) != "" {
+				return nil, fmt.Errorf("invalid DMARC record, malformed tag %q", tag)
+			}
+			continue
+		}
+		name, value := strings.ToLower(strings.TrimSpace(tag[:i])), strings.TrimSpace(tag[i+1:])
+		switch name {
+		case "p":
+			r.Policy = dmarcPolicy(value)
+		case "sp":
+			r.SubdomainPolicy = dmarcPolicy(value)
+		case "adkim":
+			r.AlignDKIM = dmarcAlignment(value)
+		case "aspf":
+			r.AlignSPF = dmarcAlignment(value)
+		case "pct":
+			n, err := strconv.Atoi(value)
+			if err != nil || n < 0 || n > 100 {
+				return nil, fmt.Errorf("invalid DMARC record, pct %q is not a percentage", value)
+			}
+			r.Percent = n
+		case "rua":
+			r.AggregateReports = uriList(value)
+		case "ruf":
+			r.FailureReports = uriList(value)
+		}
+	}
+	if r.Policy == "" {
+		if len(r.AggregateReports) == 0 {
+			return nil, fmt.Errorf("invalid DMARC record, missing policy: %q", record)
+		}
+		r.Policy = "none"
+	}
+	if r.SubdomainPolicy == "" {
+		r.SubdomainPolicy = r.Policy
+	}
+	return r, nil
+}
+
+// isDMARC reports whether the TXT record is a DMARC record.
+func isDMARC(txt string) bool {
+	v := strings.SplitN(txt, ";", 2)[0]
+	i := strings.IndexByte(v, '=')
+	return i >= 0 && strings.TrimSpace(v[:i]) == "v" && strings.TrimSpace(v[i+1:]) == "DMARC1"
+}
+
+// dmarcPolicy returns the lowercased policy, or an empty string if it isn't valid.
+func dmarcPolicy(s string) string {
+	switch s = strings.ToLower(s); s {
+	case "none", "quarantine", "reject":
+		return s
+	default:
+		return ""
+	}
+}
+
+// dmarcAlignment returns the lowercased alignment mode, relaxed unless it is strict.
+func dmarcAlignment(s string) string {
+	if strings.EqualFold(s, "s") {
+		return "s"
+	}
+	return "r"
+}
+
+// uriList splits a comma separated list of URIs, dropping empty ones.
+func uriList(s string) []string {
+	var uris []string
+	for _, u := range strings.Split(s, ",") {
+		if u = strings.TrimSpace(u); u != "" {
+			uris = append(uris, u)
+		}
+	}
+	return uris
+}
+
+// parseDMARCRecords returns the DMARC record among the TXT records of _dmarc.domain, or nil if
+// there is none. A domain must have at most one.
+func parseDMARCRecords(domain string, txt []string) (*DMARCRecord, error) {
+	var record string
+	for _, t := range txt {
+		if !isDMARC(t) {
+			continue
+		}
+		if record != "" {
+			return nil, fmt.Errorf("domain %s has multiple DMARC records", domain)
+		}
+		record = t
+	}
+	if record == "" {
+		return nil, nil
+	}
+	r, err := ParseDMARC(record)
+	if err != nil {
+		return nil, err
+	}
+	r.Domain = domain
+	return r, nil
+}
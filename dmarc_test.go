@@ -0,0 +1,53 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseDMARC(t *testing.T) {
+	tests := []struct {
+		name     string
+		record   string
+		want     *DMARCRecord
+		enforced bool
+		wantErr  bool
+	}{
+		{"1", "v=DMARC1; p=reject; rua=mailto:dmarc@example.com", &DMARCRecord{
+			Record: "v=DMARC1; p=reject; rua=mailto:dmarc@example.com", Policy: "reject", SubdomainPolicy: "reject",
+			AlignDKIM: "r", AlignSPF: "r", Percent: 100, AggregateReports: []string{"mailto:dmarc@example.com"},
+		}, true, false},
+		{"2", "v=DMARC1;p=Quarantine;sp=none;adkim=s;aspf=r;pct=0;ruf=mailto:a@example.com, mailto:b@example.com;", &DMARCRecord{
+			Record: "v=DMARC1;p=Quarantine;sp=none;adkim=s;aspf=r;pct=0;ruf=mailto:a@example.com, mailto:b@example.com;", Policy: "quarantine", SubdomainPolicy: "none",
+			AlignDKIM: "s", AlignSPF: "r", Percent: 0, FailureReports: []string{"mailto:a@example.com", "mailto:b@example.com"},
+		}, false, false},
+		{"3", "v=DMARC1; p=bogus; rua=mailto:dmarc@example.com", &DMARCRecord{
+			Record: "v=DMARC1; p=bogus; rua=mailto:dmarc@example.com", Policy: "none", SubdomainPolicy: "none",
+			AlignDKIM: "r", AlignSPF: "r", Percent: 100, AggregateReports: []string{"mailto:dmarc@example.com"},
+		}, false, false},
+		{"4", "v=DMARC1; p=bogus", nil, false, true},
+		{"5", "p=reject; v=DMARC1", nil, false, true},
+		{"6", "v=DMARC1; p=reject; pct=150", nil, false, true},
+		{"7", "v=DMARC1; p=reject; bogus", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseDMARC(tt.record)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseDMARC() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseDMARC() = %+v, want %+v", got, tt.want)
+			}
+			if got != nil && got.Enforced() != tt.enforced {
+				t.Errorf("DMARCRecord.Enforced() = %v, want %v", got.Enforced(), tt.enforced)
+			}
+		})
+	}
+}
@@ -0,0 +1,598 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"time"
+)
+
+// defaultHedgeDelay is the time to wait for a resolver to answer before the next one is queried.
+const defaultHedgeDelay = 500 * time.Millisecond
+
+// Resolver looks up DNS records, it is implemented by *net.Resolver. Implement it to resolve
+// through DNS over HTTPS, or to validate without network access in tests. A Resolver that also has
+// the LookupAddr method of *net.Resolver is used for the reverse lookups of WithHeloFromReverseDNS,
+// otherwise those use net.DefaultResolver.
+type Resolver interface {
+	LookupMX(ctx context.Context, name string) ([]*net.MX, error)
+	LookupIP(ctx context.Context, network, host string) ([]net.IP, error)
+	LookupTXT(ctx context.Context, name string) ([]string, error)
+}
+
+// DefaultResolver is used for the lookups of the package level validation functions and of every
+// Verifier created without WithResolver or WithResolvers.
+var DefaultResolver Resolver = net.DefaultResolver
+
+// resolver is a Resolver that also does reverse lookups.
+type resolver interface {
+	Resolver
+	LookupAddr(ctx context.Context, addr string) ([]string, error)
+}
+
+// WithResolver sets the resolver used for lookups, replacing DefaultResolver.
+func WithResolver(r Resolver) Option {
+	return func(v *Verifier) {
+		v.resolver = asResolver(r)
+	}
+}
+
+// asResolver returns r with reverse lookups, by net.DefaultResolver if r can't do them.
+func asResolver(r Resolver) resolver {
+	if r, ok := r.(resolver); ok {
+		return r
+	}
+	return addrResolver{r}
+}
+
+// addrResolver does the reverse lookups of a Resolver with net.DefaultResolver.
+type addrResolver struct {
+	Resolver
+}
+
+func (r addrResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return net.DefaultResolver.LookupAddr(ctx, addr)
+}
+
+// defaultResolver sends every lookup to the DefaultResolver at the time of the lookup.
+type defaultResolver struct{}
+
+func (defaultResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	return DefaultResolver.LookupMX(ctx, name)
+}
+
+func (defaultResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	return DefaultResolver.LookupIP(ctx, network, host)
+}
+
+func (defaultResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return DefaultResolver.LookupTXT(ctx, name)
+}
+
+func (defaultResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return asResolver(DefaultResolver).LookupAddr(ctx, addr)
+}
+
+// WithResolvers sets the DNS servers used for lookups, ie. "1.1.1.1", "8.8.8.8:53" or
+// "[2606:4700:4700::1111]:53". The name "system" refers to the resolver of the operating system.
+// The servers are queried in order: when a server fails the next one is queried immediately and
+// when a server is slow to answer the next one is queried in parallel (see WithHedgeDelay). The
+// first answer is used, so a single flaky resolver doesn't turn valid domains into unresolvable
+// ones.
+func WithResolvers(servers ...string) Option {
+	return func(v *Verifier) {
+		h := &hedgedResolver{delay: defaultHedgeDelay, servers: servers}
+		for _, s := range servers {
+			h.resolvers = append(h.resolvers, newResolver(s))
+		}
+		if v.hedgeDelay > 0 {
+			h.delay = v.hedgeDelay
+		}
+		v.resolver = h
+	}
+}
+
+// WithHedgeDelay sets how long to wait for a resolver configured with WithResolvers before the
+// next resolver is queried in parallel. Defaults to 500ms.
+func WithHedgeDelay(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.hedgeDelay = d
+		if h, ok := v.resolver.(*hedgedResolver); ok {
+			h.delay = d
+		}
+	}
+}
+
+// WithDNSTimeout sets the timeout of a single DNS query attempt, independent from any deadline of
+// the SMTP connection.
+func WithDNSTimeout(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.dnsTimeout = d
+	}
+}
+
+// WithDNSAttempts sets how many times a DNS query is attempted when it fails with a temporary
+// error or times out. A domain that doesn't exist is never retried. Defaults to 1.
+func WithDNSAttempts(n int) Option {
+	return func(v *Verifier) {
+		v.dnsAttempts = n
+	}
+}
+
+// WithDNSBackoff sets the time to wait before retrying a failed DNS query, this time is doubled
+// after every attempt.
+func WithDNSBackoff(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.dnsBackoff = d
+	}
+}
+
+// WithDNSSoftFail makes temporary DNS failures, such as SERVFAIL responses and timeouts, return an
+// error wrapping ErrUnverifiable instead of failing validation outright. A domain that doesn't
+// exist still fails validation.
+func WithDNSSoftFail() Option {
+	return func(v *Verifier) {
+		v.dnsSoftFail = true
+	}
+}
+
+// WithMXOnly requires domains to have MX records. By default a domain without MX records is
+// delivered to its address (RFC 5321 section 5.1), which produces false positives for domains that
+// only host a website.
+func WithMXOnly() Option {
+	return func(v *Verifier) {
+		v.mxOnly = true
+	}
+}
+
+// WithWildcardDetection resolves a random subdomain of every domain to detect wildcard DNS
+// records, as set up by parked domains. A domain fails with an error wrapping ErrWildcardDNS if
+// the subdomain has the same mail hosts, or, for a domain without MX records, any address. This
+// costs one extra query per domain.
+func WithWildcardDetection() Option {
+	return func(v *Verifier) {
+		v.wildcardDNS = true
+	}
+}
+
+// newResolver returns a resolver that sends its queries to server.
+func newResolver(server string) resolver {
+	if server == "system" {
+		return net.DefaultResolver
+	}
+	if _, _, err := net.SplitHostPort(server); err != nil {
+		server = net.JoinHostPort(unbracketHost(server), "53")
+	}
+	return &net.Resolver{
+		PreferGo: true,
+		Dial: func(ctx context.Context, network, _ string) (net.Conn, error) {
+			var d net.Dialer
+			return d.DialContext(ctx, network, server)
+		},
+	}
+}
+
+// lookupHost first checks if any MX records are available and if not, it will check
+// if A records are available.
+func (v *Verifier) lookupHost(ctx context.Context, domain string) (string, error) {
+	hosts, err := v.lookupHosts(ctx, domain)
+	if err != nil {
+		return "", err
+	}
+	return hosts[0], nil
+}
+
+// LookupHosts returns the mail hosts of domain in order of preference, from its MX records or, if
+// it has none, its address records. Answers are cached if the Verifier was created with WithCache
+// or WithHostCache.
+func (v *Verifier) LookupHosts(ctx context.Context, domain string) ([]string, error) {
+	return v.lookupHosts(ctx, domain)
+}
+
+// IdentifyProvider looks up the mail hosts of domain and returns the provider hosting its
+// mailboxes, ie. ProviderGoogle for a domain on Google Workspace, with the mail hosts in order of
+// preference. See ProviderForHost.
+func (v *Verifier) IdentifyProvider(ctx context.Context, domain string) (Provider, []string, error) {
+	hosts, err := v.lookupHosts(ctx, domain)
+	if err != nil {
+		return ProviderUnknown, nil, err
+	}
+	return identifyProvider(domain, hosts), hosts, nil
+}
+
+// lookupHosts is like lookupHost, but returns all mail hosts in order of preference.
+func (v *Verifier) lookupHosts(ctx context.Context, domain string) ([]string, error) {
+	return v.resolveHosts(ctx, domain, v.dnsSoftFail)
+}
+
+// LookupMXRecords looks up every mail host of domain, in order of preference, so callers can
+// probe them in their own way. When the domain has no MX records its addresses are returned as
+// fallback records, unless WithMXOnly is set. Unlike LookupHosts the answer is not cached.
+func (v *Verifier) LookupMXRecords(ctx context.Context, domain string) ([]MXRecord, error) {
+	domain = asciiDomain(domain)
+	records, _, err := v.lookupMXRecords(ctx, domain, v.dnsSoftFail)
+	if err == nil {
+		err = v.checkWildcard(ctx, domain, records)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return records, nil
+}
+
+// CheckSPF looks up the SPF record of domain, a domain without a record returns an empty
+// SPFRecord. Domains that send mail publish one, which together with the mail hosts tells whether
+// a domain is actually used for mail.
+func (v *Verifier) CheckSPF(ctx context.Context, domain string) (*SPFRecord, error) {
+	domain = asciiDomain(domain)
+	txt, err := v.resolver.LookupTXT(ctx, domain)
+	v.logf(ctx, "dns: TXT %s: %d records, error %v", domain, len(txt), err)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	return parseSPFRecords(domain, txt)
+}
+
+// CheckDMARC looks up the DMARC record of domain, or when it has none the record of its
+// organizational domain, ie. example.com for mail.example.com. A domain without a record returns
+// an empty DMARCRecord.
+func (v *Verifier) CheckDMARC(ctx context.Context, domain string) (*DMARCRecord, error) {
+	domain = strings.TrimSuffix(strings.ToLower(asciiDomain(domain)), ".")
+	domains := []string{domain}
+	if org, err := effectiveTLDPlusOne(domain); err == nil && org != domain {
+		domains = append(domains, org)
+	}
+	for _, d := range domains {
+		txt, err := v.resolver.LookupTXT(ctx, "_dmarc."+d)
+		v.logf(ctx, "dns: TXT _dmarc.%s: %d records, error %v", d, len(txt), err)
+		if err != nil && !isNotFound(err) {
+			return nil, err
+		}
+		r, err := parseDMARCRecords(d, txt)
+		if r != nil || err != nil {
+			return r, err
+		}
+	}
+	return &DMARCRecord{}, nil
+}
+
+// resolveHosts looks up the mail hosts of domain, in order of preference. If softFail is true
+// temporary failures return an error wrapping ErrUnverifiable.
+func (v *Verifier) resolveHosts(ctx context.Context, domain string, softFail bool) ([]string, error) {
+	domain = asciiDomain(domain)
+	if c, ok := v.hostCache.get(domain); ok {
+		v.logf(ctx, "dns: %s: cached hosts %q, error %v", domain, c.hosts(), c.err())
+		return c.hosts(), c.err()
+	}
+	records, final, err := v.lookupMXRecords(ctx, domain, softFail)
+	if err == nil {
+		err, final = v.checkWildcard(ctx, domain, records), true // wildcard records are an answer
+	}
+	if err != nil {
+		if final {
+			v.hostCache.put(cacheEntry{Key: domain}, err)
+		}
+		return nil, err
+	}
+	if records[0].Fallback {
+		v.hostCache.put(cacheEntry{Key: domain, Host: records[0].Host}, nil)
+		return []string{records[0].Host}, nil // randomly returns IPv4 or IPv6 (when available)
+	}
+	hosts := make([]string, len(records))
+	for i, r := range records {
+		hosts[i] = r.Host
+	}
+	v.hostCache.put(cacheEntry{Key: domain, Host: hosts[0], Hosts: hosts}, nil)
+	return hosts, nil
+}
+
+// lookupMXRecords queries the MX records of domain, and its addresses if it has none. final is
+// true if the error is an answer that can be cached, such as a domain that doesn't exist.
+func (v *Verifier) lookupMXRecords(ctx context.Context, domain string, softFail bool) (records []MXRecord, final bool, err error) {
+	mx, mxErr := v.resolver.LookupMX(ctx, domain)
+	v.logf(ctx, "dns: MX %s: %d records, error %v", domain, len(mx), mxErr)
+	if errors.Is(mxErr, ErrBudgetExhausted) {
+		return nil, false, newError(CodeBudgetExhausted, mxErr)
+	}
+	if ctxErr := ctx.Err(); mxErr != nil && ctxErr != nil {
+		return nil, false, ctxErr
+	}
+	if mxErr == nil && len(mx) > 0 {
+		sort.SliceStable(mx, func(i, j int) bool { return mx[i].Pref < mx[j].Pref })
+		records = make([]MXRecord, len(mx))
+		for i, r := range mx {
+			records[i] = MXRecord{Host: r.Host, Pref: r.Pref}
+		}
+		return records, false, nil
+	}
+	if mxErr != nil && !isNotFound(mxErr) {
+		// Only a domain without MX records falls back to its address records, a failed query
+		// doesn't tell whether it has any.
+		if softFail && isTemporary(mxErr) {
+			return nil, false, temporaryDNSError(domain, mxErr)
+		}
+		return nil, false, newError(CodeNoMX, fmt.Errorf("failed finding MX records for domain %s", domain))
+	}
+	if v.mxOnly {
+		err = newError(CodeNoMX, fmt.Errorf("failed finding MX records for domain %s", domain))
+		return nil, mxErr == nil || isNotFound(mxErr), err
+	}
+	ips, ipErr := v.resolver.LookupIP(ctx, "ip", domain)
+	v.logf(ctx, "dns: A/AAAA %s: %d records, error %v", domain, len(ips), ipErr)
+	if errors.Is(ipErr, ErrBudgetExhausted) {
+		return nil, false, newError(CodeBudgetExhausted, ipErr)
+	}
+	if ctxErr := ctx.Err(); ipErr != nil && ctxErr != nil {
+		return nil, false, ctxErr
+	}
+	if ipErr == nil && len(ips) > 0 {
+		records = make([]MXRecord, len(ips))
+		for i, ip := range ips {
+			records[i] = MXRecord{Host: ip.String(), Fallback: true}
+		}
+		return records, false, nil
+	}
+	err = newError(CodeNoMX, fmt.Errorf("failed finding MX and A records for domain %s", domain))
+	if (mxErr == nil || isNotFound(mxErr)) && (ipErr == nil || isNotFound(ipErr)) {
+		return nil, true, err
+	}
+	if softFail && mxErr == nil && isTemporary(ipErr) {
+		return nil, false, temporaryDNSError(domain, ipErr)
+	}
+	return nil, false, err
+}
+
+// temporaryDNSError returns the error of a lookup of domain that failed temporarily with err.
+func temporaryDNSError(domain string, err error) error {
+	code := CodeDNSFailure
+	if isTimeout(err) {
+		code = CodeDNSTimeout
+	}
+	return newError(code, fmt.Errorf("%w: temporary DNS failure for domain %s", ErrUnverifiable, domain))
+}
+
+// checkWildcard returns an error wrapping ErrWildcardDNS if WithWildcardDetection is set and a
+// random subdomain of domain has one of the mail hosts of records, or, if they are fallback
+// records, any address.
+func (v *Verifier) checkWildcard(ctx context.Context, domain string, records []MXRecord) error {
+	if !v.wildcardDNS {
+		return nil
+	}
+	b := make([]byte, 8)
+	if _, err := rand.Read(b); err != nil {
+		return err
+	}
+	name := "wildcard-" + hex.EncodeToString(b) + "." + domain
+	wildcard := false
+	if records[0].Fallback {
+		ips, err := v.resolver.LookupIP(ctx, "ip", name)
+		wildcard = err == nil && len(ips) > 0
+	} else if mx, err := v.resolver.LookupMX(ctx, name); err == nil {
+		for _, r := range mx {
+			for _, h := range records {
+				wildcard = wildcard || strings.EqualFold(r.Host, h.Host)
+			}
+		}
+	}
+	v.logf(ctx, "dns: %s: wildcard %v", domain, wildcard)
+	if !wildcard {
+		return nil
+	}
+	return newError(CodeWildcardDNS, fmt.Errorf("%w: %s resolves %s", ErrWildcardDNS, domain, name))
+}
+
+// hedgedResolver queries multiple resolvers and returns the first successful answer.
+type hedgedResolver struct {
+	resolvers []resolver
+	servers   []string
+	delay     time.Duration
+}
+
+func (h *hedgedResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	v, err := h.do(ctx, func(ctx context.Context, r resolver) (interface{}, error) {
+		return r.LookupMX(ctx, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*net.MX), nil
+}
+
+func (h *hedgedResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	v, err := h.do(ctx, func(ctx context.Context, r resolver) (interface{}, error) {
+		return r.LookupIP(ctx, network, host)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]net.IP), nil
+}
+
+func (h *hedgedResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	v, err := h.do(ctx, func(ctx context.Context, r resolver) (interface{}, error) {
+		return r.LookupTXT(ctx, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+func (h *hedgedResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	v, err := h.do(ctx, func(ctx context.Context, r resolver) (interface{}, error) {
+		return r.LookupAddr(ctx, addr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+// do runs query against the resolvers. The next resolver is started when the previous one failed
+// or didn't answer within the hedge delay. If all resolvers fail, a not found error is preferred
+// over other errors as it is the most definitive answer.
+func (h *hedgedResolver) do(ctx context.Context, query func(context.Context, resolver) (interface{}, error)) (interface{}, error) {
+	if len(h.resolvers) == 0 {
+		return nil, fmt.Errorf("no resolvers configured")
+	}
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type result struct {
+		v   interface{}
+		err error
+	}
+	results := make(chan result, len(h.resolvers))
+	started, failed := 0, 0
+	start := func() {
+		r := h.resolvers[started]
+		started++
+		go func() {
+			v, err := query(ctx, r)
+			results <- result{v, err}
+		}()
+	}
+
+	var err error
+	start()
+	for {
+		var hedge <-chan time.Time
+		var timer *time.Timer
+		if started < len(h.resolvers) {
+			timer = time.NewTimer(h.delay)
+			hedge = timer.C
+		}
+		select {
+		case r := <-results:
+			if timer != nil {
+				timer.Stop()
+			}
+			if r.err == nil {
+				return r.v, nil
+			}
+			failed++
+			if err == nil || !isNotFound(err) {
+				err = r.err
+			}
+			if failed == len(h.resolvers) {
+				return nil, err
+			}
+			if failed == started {
+				start()
+			}
+		case <-hedge:
+			start()
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// retryResolver limits the duration of queries and retries them on temporary failures.
+type retryResolver struct {
+	resolver resolver
+	timeout  time.Duration
+	attempts int
+	backoff  time.Duration
+}
+
+func (r *retryResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	v, err := r.do(ctx, func(ctx context.Context) (interface{}, error) {
+		return r.resolver.LookupMX(ctx, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]*net.MX), nil
+}
+
+func (r *retryResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	v, err := r.do(ctx, func(ctx context.Context) (interface{}, error) {
+		return r.resolver.LookupIP(ctx, network, host)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]net.IP), nil
+}
+
+func (r *retryResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	v, err := r.do(ctx, func(ctx context.Context) (interface{}, error) {
+		return r.resolver.LookupTXT(ctx, name)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+func (r *retryResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	v, err := r.do(ctx, func(ctx context.Context) (interface{}, error) {
+		return r.resolver.LookupAddr(ctx, addr)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return v.([]string), nil
+}
+
+func (r *retryResolver) do(ctx context.Context, query func(context.Context) (interface{}, error)) (interface{}, error) {
+	backoff := r.backoff
+	for attempt := 1; ; attempt++ {
+		qctx, cancel := ctx, context.CancelFunc(func() {})
+		if r.timeout > 0 {
+			qctx, cancel = context.WithTimeout(ctx, r.timeout)
+		}
+		v, err := query(qctx)
+		cancel()
+		if err == nil || attempt >= r.attempts || !isTemporary(err) || ctx.Err() != nil {
+			return v, err
+		}
+
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+		backoff *= 2
+	}
+}
+
+// isTemporary reports whether err is a temporary DNS failure that is worth retrying.
+func isTemporary(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && !dnsErr.IsNotFound && (dnsErr.IsTemporary || dnsErr.IsTimeout)
+}
+
+// isTimeout reports whether err is a DNS query that timed out.
+func isTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsTimeout
+}
+
+// isNotFound reports whether err indicates the name doesn't exist.
+func isNotFound(err error) bool {
+	var dnsErr *net.DNSError
+	return errors.As(err, &dnsErr) && dnsErr.IsNotFound
+}
@@ -0,0 +1,620 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// testResolver is a resolver serving records from memory.
+type testResolver struct {
+	mx    map[string][]*net.MX
+	ips   map[string][]net.IP
+	addrs map[string][]string
+	txt   map[string][]string
+
+	// wildcard holds the domains whose subdomains have the records of the domain, as with wildcard
+	// DNS records.
+	wildcard map[string]bool
+
+	// err is returned by every lookup when set, mxErr by MX lookups.
+	err, mxErr error
+
+	// delay is the time it takes to answer a query.
+	delay time.Duration
+
+	mu      sync.Mutex
+	queries int
+}
+
+func (r *testResolver) query(ctx context.Context, name string, found bool) error {
+	r.mu.Lock()
+	r.queries++
+	r.mu.Unlock()
+	if r.delay > 0 {
+		select {
+		case <-time.After(r.delay):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	if r.err != nil {
+		return r.err
+	}
+	if !found {
+		return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+	}
+	return nil
+}
+
+// record returns the name whose records answer a query for name.
+func (r *testResolver) record(name string) string {
+	if i := strings.IndexByte(name, '.'); i >= 0 && r.wildcard[name[i+1:]] {
+		return name[i+1:]
+	}
+	return name
+}
+
+func (r *testResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	mx, ok := r.mx[r.record(name)]
+	if err := r.query(ctx, name, ok); err != nil {
+		return nil, err
+	}
+	if r.mxErr != nil {
+		return nil, r.mxErr
+	}
+	return mx, nil
+}
+
+func (r *testResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	ips, ok := r.ips[r.record(host)]
+	if err := r.query(ctx, host, ok); err != nil {
+		return nil, err
+	}
+	return ips, nil
+}
+
+func (r *testResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	txt, ok := r.txt[name]
+	if err := r.query(ctx, name, ok); err != nil {
+		return nil, err
+	}
+	return txt, nil
+}
+
+func (r *testResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	names, ok := r.addrs[addr]
+	if err := r.query(ctx, addr, ok); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+func TestVerifier_lookupHost(t *testing.T) {
+	r := &testResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "mx1.example.com.", Pref: 10}},
+		},
+		ips: map[string][]net.IP{
+			"example.com": {net.ParseIP("192.0.2.1")},
+			"example.org": {net.ParseIP("2001:db8::1")},
+		},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{"1", "example.com", "mx1.example.com.", false},
+		{"2", "example.org", "2001:db8::1", false},
+		{"3", "example.net", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier()
+			v.resolver = r
+			got, err := v.lookupHost(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.lookupHost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Verifier.lookupHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+// mxResolver is a Resolver without reverse lookups.
+type mxResolver map[string][]*net.MX
+
+func (r mxResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if mx, ok := r[name]; ok {
+		return mx, nil
+	}
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func (r mxResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: host, IsNotFound: true}
+}
+
+func (r mxResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return nil, &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
+
+func TestWithResolver(t *testing.T) {
+	r := mxResolver{"example.com": {{Host: "mx.example.com.", Pref: 10}}}
+	tests := []struct {
+		name    string
+		opts    []Option
+		domain  string
+		want    string
+		wantErr bool
+	}{
+		{"1", []Option{WithResolver(r)}, "example.com", "mx.example.com.", false},
+		{"2", []Option{WithResolver(r)}, "example.net", "", true},
+		{"3", []Option{WithResolver(r), WithDNSAttempts(2), WithDNSBudget(Budget{Limit: 10, Period: time.Hour})}, "example.com", "mx.example.com.", false},
+		{"4", []Option{WithResolvers("192.0.2.1"), WithResolver(r)}, "example.com", "mx.example.com.", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := NewVerifier(tt.opts...).lookupHost(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.lookupHost() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("Verifier.lookupHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDefaultResolver(t *testing.T) {
+	def := DefaultResolver
+	defer func() { DefaultResolver = def }()
+	v := NewVerifier()
+	DefaultResolver = &testResolver{txt: map[string][]string{"example.com": {"v=spf1 -all"}}}
+
+	got, err := v.resolver.LookupTXT(context.Background(), "example.com")
+	if err != nil || len(got) != 1 || got[0] != "v=spf1 -all" {
+		t.Errorf("Verifier.resolver.LookupTXT() = %v, %v, want the records of DefaultResolver", got, err)
+	}
+	if _, err = LookupHost("example.com"); err == nil {
+		t.Errorf("LookupHost() error = nil, want the error of DefaultResolver")
+	}
+}
+
+func TestVerifier_lookupHostSoftFail(t *testing.T) {
+	servfail := &testResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}
+	timeout := &testResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+	nxdomain := &testResolver{}
+	tests := []struct {
+		name             string
+		resolver         resolver
+		opts             []Option
+		wantUnverifiable bool
+	}{
+		{"1", servfail, nil, false},
+		{"2", servfail, []Option{WithDNSSoftFail()}, true},
+		{"3", timeout, []Option{WithDNSSoftFail()}, true},
+		{"4", nxdomain, []Option{WithDNSSoftFail()}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(tt.opts...)
+			v.resolver = tt.resolver
+			_, err := v.lookupHost(context.Background(), "example.com")
+			if err == nil {
+				t.Fatal("Verifier.lookupHost() error = nil, want error")
+			}
+			if got := errors.Is(err, ErrUnverifiable); got != tt.wantUnverifiable {
+				t.Errorf("Verifier.lookupHost() error = %v, want unverifiable %v", err, tt.wantUnverifiable)
+			}
+		})
+	}
+}
+
+func TestVerifier_lookupMXRecords_fallback(t *testing.T) {
+	ips := map[string][]net.IP{"example.com": {net.ParseIP("192.0.2.1")}}
+	servfail := &net.DNSError{Err: "server misbehaving", IsTemporary: true}
+	tests := []struct {
+		name             string
+		mxErr            error
+		softFail         bool
+		wantFallback     bool
+		wantUnverifiable bool
+	}{
+		{"1", nil, false, true, false},
+		{"2", &net.DNSError{Err: "no such host", IsNotFound: true}, false, true, false},
+		{"3", fmt.Errorf("retry: %w", &net.DNSError{Err: "no such host", IsNotFound: true}), false, true, false},
+		{"4", servfail, false, false, false},
+		{"5", servfail, true, false, true},
+		{"6", newError(CodeDNSFailure, fmt.Errorf("retry: %w", servfail)), true, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier()
+			v.resolver = &testResolver{ips: ips, mx: map[string][]*net.MX{"example.com": nil}, mxErr: tt.mxErr}
+			records, _, err := v.lookupMXRecords(context.Background(), "example.com", tt.softFail)
+			if got := len(records) == 1 && records[0].Fallback; got != tt.wantFallback || (err == nil) != tt.wantFallback {
+				t.Errorf("Verifier.lookupMXRecords() = %v, %v, want fallback %v", records, err, tt.wantFallback)
+			}
+			if got := errors.Is(err, ErrUnverifiable); got != tt.wantUnverifiable {
+				t.Errorf("Verifier.lookupMXRecords() error = %v, want unverifiable %v", err, tt.wantUnverifiable)
+			}
+		})
+	}
+}
+
+func TestHedgedResolver_LookupMX(t *testing.T) {
+	mx := map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}}
+	ok := func() *testResolver { return &testResolver{mx: mx} }
+	slow := func() *testResolver { return &testResolver{mx: mx, delay: time.Second} }
+	servfail := func() *testResolver {
+		return &testResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}
+	}
+	nxdomain := func() *testResolver { return &testResolver{} }
+
+	tests := []struct {
+		name         string
+		resolvers    []*testResolver
+		want         []*net.MX
+		wantNotFound bool
+		wantErr      bool
+	}{
+		{"1", []*testResolver{ok()}, mx["example.com"], false, false},
+		{"2", []*testResolver{servfail(), ok()}, mx["example.com"], false, false},
+		{"3", []*testResolver{slow(), ok()}, mx["example.com"], false, false},
+		{"4", []*testResolver{servfail(), servfail()}, nil, false, true},
+		{"5", []*testResolver{nxdomain(), servfail()}, nil, true, true},
+		{"6", []*testResolver{servfail(), nxdomain()}, nil, true, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &hedgedResolver{delay: 10 * time.Millisecond}
+			for _, r := range tt.resolvers {
+				h.resolvers = append(h.resolvers, r)
+			}
+			start := time.Now()
+			got, err := h.LookupMX(context.Background(), "example.com")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("hedgedResolver.LookupMX() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if isNotFound(err) != tt.wantNotFound {
+				t.Errorf("hedgedResolver.LookupMX() error = %v, want not found %v", err, tt.wantNotFound)
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("hedgedResolver.LookupMX() = %v, want %v", got, tt.want)
+			}
+			if d := time.Since(start); d > 500*time.Millisecond {
+				t.Errorf("hedgedResolver.LookupMX() took %v, want the fastest answer", d)
+			}
+		})
+	}
+}
+
+func TestHedgedResolver_canceled(t *testing.T) {
+	h := &hedgedResolver{
+		resolvers: []resolver{&testResolver{delay: time.Second}},
+		delay:     10 * time.Millisecond,
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+	if _, err := h.LookupIP(ctx, "ip", "example.com"); !errors.Is(err, context.DeadlineExceeded) {
+		t.Errorf("hedgedResolver.LookupIP() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func Test_newResolver(t *testing.T) {
+	if r := newResolver("system"); r != net.DefaultResolver {
+		t.Errorf("newResolver(system) = %v, want net.DefaultResolver", r)
+	}
+	v := NewVerifier(WithHedgeDelay(time.Second), WithResolvers("system", "192.0.2.1", "[2001:db8::1]:5353"))
+	h, ok := v.resolver.(*hedgedResolver)
+	if !ok || len(h.resolvers) != 3 || h.delay != time.Second {
+		t.Errorf("WithResolvers() resolver = %#v", v.resolver)
+	}
+}
+
+// flakyResolver fails the first queries with a temporary error.
+type flakyResolver struct {
+	testResolver
+	failures int
+}
+
+func (r *flakyResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	fail := r.queries < r.failures
+	r.mu.Unlock()
+	if fail {
+		r.query(ctx, name, true)
+		return nil, &net.DNSError{Err: "server misbehaving", Name: name, IsTemporary: true}
+	}
+	return r.testResolver.LookupMX(ctx, name)
+}
+
+func TestRetryResolver_LookupMX(t *testing.T) {
+	mx := map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}}
+	tests := []struct {
+		name        string
+		resolver    resolver
+		timeout     time.Duration
+		attempts    int
+		wantErr     bool
+		wantQueries int
+	}{
+		{"1", &flakyResolver{testResolver{mx: mx}, 0}, 0, 3, false, 1},
+		{"2", &flakyResolver{testResolver{mx: mx}, 2}, 0, 3, false, 3},
+		{"3", &flakyResolver{testResolver{mx: mx}, 3}, 0, 3, true, 3},
+		{"4", &flakyResolver{testResolver{mx: mx}, 2}, 0, 1, true, 1},
+		{"5", &flakyResolver{testResolver{}, 0}, 0, 3, true, 1},
+		{"6", &flakyResolver{testResolver{mx: mx, delay: time.Second}, 0}, 10 * time.Millisecond, 2, true, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &retryResolver{resolver: tt.resolver, timeout: tt.timeout, attempts: tt.attempts, backoff: time.Millisecond}
+			_, err := r.LookupMX(context.Background(), "example.com")
+			if (err != nil) != tt.wantErr {
+				t.Errorf("retryResolver.LookupMX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got := tt.resolver.(*flakyResolver).queries; got != tt.wantQueries {
+				t.Errorf("retryResolver.LookupMX() made %d queries, want %d", got, tt.wantQueries)
+			}
+		})
+	}
+}
+
+func TestVerifier_lookupHosts(t *testing.T) {
+	r := &testResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "mx3.example.com.", Pref: 30}, {Host: "mx1.example.com.", Pref: 10}, {Host: "mx2.example.com.", Pref: 20}},
+		},
+		ips: map[string][]net.IP{"example.org": {net.ParseIP("192.0.2.1")}},
+	}
+	tests := []struct {
+		name   string
+		domain string
+		want   []string
+	}{
+		{"1", "example.com", []string{"mx1.example.com.", "mx2.example.com.", "mx3.example.com."}},
+		{"2", "example.org", []string{"192.0.2.1"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithCache(time.Hour))
+			v.resolver = r
+			for i := 0; i < 2; i++ {
+				got, err := v.lookupHosts(context.Background(), tt.domain)
+				if err != nil || !reflect.DeepEqual(got, tt.want) {
+					t.Errorf("Verifier.lookupHosts() = %v, %v, want %v", got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifier_IdentifyProvider(t *testing.T) {
+	r := &testResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "aspmx.l.google.com.", Pref: 1}, {Host: "alt1.aspmx.l.google.com.", Pref: 5}},
+			"example.net": {{Host: "example-net.mail.protection.outlook.com.", Pref: 0}},
+			"example.org": {{Host: "mx.corp.example.org.", Pref: 10}},
+			"example.io":  {{Host: "mx.hosting.test.", Pref: 10}},
+		},
+		ips: map[string][]net.IP{"example.co.uk": {net.ParseIP("192.0.2.1")}},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		want    Provider
+		hosts   []string
+		wantErr bool
+	}{
+		{"1", "example.com", ProviderGoogle, []string{"aspmx.l.google.com.", "alt1.aspmx.l.google.com."}, false},
+		{"2", "example.net", ProviderMicrosoft, []string{"example-net.mail.protection.outlook.com."}, false},
+		{"3", "example.org", ProviderSelfHosted, []string{"mx.corp.example.org."}, false},
+		{"4", "example.co.uk", ProviderSelfHosted, []string{"192.0.2.1"}, false},
+		{"5", "example.io", ProviderUnknown, []string{"mx.hosting.test."}, false},
+		{"6", "missing.com", ProviderUnknown, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithResolver(r))
+			got, hosts, err := v.IdentifyProvider(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.IdentifyProvider() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want || !reflect.DeepEqual(hosts, tt.hosts) {
+				t.Errorf("Verifier.IdentifyProvider() = %v, %v, want %v, %v", got, hosts, tt.want, tt.hosts)
+			}
+		})
+	}
+}
+
+func TestWithMXOnly(t *testing.T) {
+	r := &testResolver{
+		mx:  map[string][]*net.MX{"example.com": {{Host: "mx.example.com.", Pref: 10}}},
+		ips: map[string][]net.IP{"example.org": {net.ParseIP("192.0.2.1")}},
+	}
+	tests := []struct {
+		name   string
+		domain string
+		opts   []Option
+		want   []string
+		code   Code
+	}{
+		{"1", "example.com", []Option{WithMXOnly()}, []string{"mx.example.com."}, ""},
+		{"2", "example.org", nil, []string{"192.0.2.1"}, ""},
+		{"3", "example.org", []Option{WithMXOnly()}, nil, CodeNoMX},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append(tt.opts, WithResolver(r))...)
+			got, err := v.LookupHosts(context.Background(), tt.domain)
+			if !reflect.DeepEqual(got, tt.want) || ErrorCode(err) != tt.code {
+				t.Errorf("Verifier.LookupHosts() = %v, %v, want %v, %v", got, err, tt.want, tt.code)
+			}
+		})
+	}
+}
+
+func TestWithWildcardDetection(t *testing.T) {
+	r := &testResolver{
+		mx: map[string][]*net.MX{
+			"example.com":    {{Host: "mx.example.com.", Pref: 10}},
+			"parked.example": {{Host: "mx.parking.test.", Pref: 10}},
+			"web.example":    {{Host: "mx.web.example.", Pref: 10}},
+		},
+		ips: map[string][]net.IP{
+			"example.org": {net.ParseIP("192.0.2.1")},
+			"example.net": {net.ParseIP("192.0.2.2")},
+			"web.example": {net.ParseIP("192.0.2.3")},
+		},
+		wildcard: map[string]bool{"parked.example": true, "example.net": true, "web.example": true},
+	}
+	tests := []struct {
+		name   string
+		domain string
+		opts   []Option
+		want   []string
+	}{
+		{"1", "example.com", []Option{WithWildcardDetection()}, []string{"mx.example.com."}},
+		{"2", "parked.example", nil, []string{"mx.parking.test."}},
+		{"3", "parked.example", []Option{WithWildcardDetection()}, nil},
+		{"4", "example.org", []Option{WithWildcardDetection()}, []string{"192.0.2.1"}},
+		{"5", "example.net", []Option{WithWildcardDetection()}, nil},
+		{"6", "web.example", []Option{WithWildcardDetection()}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append(tt.opts, WithResolver(r), WithCache(time.Hour))...)
+			for i := 0; i < 2; i++ {
+				got, err := v.LookupHosts(context.Background(), tt.domain)
+				if !reflect.DeepEqual(got, tt.want) || (tt.want == nil) != errors.Is(err, ErrWildcardDNS) {
+					t.Errorf("Verifier.LookupHosts() = %v, %v, want %v", got, err, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func TestVerifier_LookupMXRecords(t *testing.T) {
+	r := &testResolver{
+		mx: map[string][]*net.MX{
+			"example.com": {{Host: "mx2.example.com.", Pref: 20}, {Host: "mx1.example.com.", Pref: 10}},
+		},
+		ips: map[string][]net.IP{"example.org": {net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")}},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		opts    []Option
+		want    []MXRecord
+		wantErr bool
+	}{
+		{"1", "example.com", nil, []MXRecord{{"mx1.example.com.", 10, false}, {"mx2.example.com.", 20, false}}, false},
+		{"2", "example.org", nil, []MXRecord{{"192.0.2.1", 0, true}, {"2001:db8::1", 0, true}}, false},
+		{"3", "example.org", []Option{WithMXOnly()}, nil, true},
+		{"4", "missing.com", nil, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append(tt.opts, WithResolver(r))...)
+			got, err := v.LookupMXRecords(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.LookupMXRecords() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Verifier.LookupMXRecords() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_CheckSPF(t *testing.T) {
+	r := &testResolver{
+		txt: map[string][]string{
+			"example.com": {"google-site-verification=abc", "v=spf1 include:_spf.google.com -all"},
+			"example.org": {"google-site-verification=abc"},
+			"example.net": {"v=spf1 mx -all", "v=spf1 a -all"},
+		},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		exists  bool
+		all     string
+		wantErr bool
+	}{
+		{"1", "example.com", true, "-all", false},
+		{"2", "example.org", false, "", false},
+		{"3", "missing.com", false, "", false},
+		{"4", "example.net", false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithResolver(r))
+			got, err := v.CheckSPF(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.CheckSPF() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && (got.Exists() != tt.exists || got.All != tt.all) {
+				t.Errorf("Verifier.CheckSPF() = %+v, want exists %v, all %v", got, tt.exists, tt.all)
+			}
+		})
+	}
+}
+
+func TestVerifier_CheckDMARC(t *testing.T) {
+	r := &testResolver{
+		txt: map[string][]string{
+			"_dmarc.example.com": {"v=DMARC1; p=reject; sp=quarantine"},
+			"_dmarc.example.net": {"v=DMARC1; p=none", "v=DMARC1; p=reject"},
+		},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		found   string
+		policy  string
+		wantErr bool
+	}{
+		{"1", "example.com", "example.com", "reject", false},
+		{"2", "Mail.Example.com.", "example.com", "reject", false},
+		{"3", "example.org", "", "", false},
+		{"4", "example.net", "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithResolver(r))
+			got, err := v.CheckDMARC(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.CheckDMARC() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && (got.Domain != tt.found || got.Policy != tt.policy) {
+				t.Errorf("Verifier.CheckDMARC() = %+v, want domain %v, policy %v", got, tt.found, tt.policy)
+			}
+		})
+	}
+}
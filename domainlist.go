@@ -0,0 +1,210 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"errors"
+	"hash/fnv"
+	"math"
+	"sort"
+	"strings"
+)
+
+var (
+	// ErrDenied is returned when the domain of an email address is on the deny list of a
+	// Verifier.
+	ErrDenied = errors.New("domain is denied")
+
+	// ErrDisposable is returned when the domain of an email address is on the disposable list
+	// of a Verifier.
+	ErrDisposable = errors.New("domain is a disposable email provider")
+)
+
+// DomainList is a set of domains, such as a list of disposable email providers. Implementations
+// must be safe for concurrent use, so a single list can be shared by many Verifiers. Contains is
+// called with lowercased domains without a trailing dot.
+type DomainList interface {
+	Contains(domain string) bool
+}
+
+// listed reports whether the domain or one of its parent domains is in list. A DomainMatcher
+// decides itself.
+func listed(list DomainList, domain string) bool {
+	if list == nil {
+		return false
+	}
+	if m, ok := list.(DomainMatcher); ok {
+		return m.Match(domain)
+	}
+	for {
+		if list.Contains(domain) {
+			return true
+		}
+		i := strings.IndexByte(domain, '.')
+		if i < 0 {
+			return false
+		}
+		domain = domain[i+1:]
+	}
+}
+
+// normalizeDomains lowercases the domains, strips trailing dots and empty entries and returns
+// them sorted without duplicates.
+func normalizeDomains(domains []string) []string {
+	out := make([]string, 0, len(domains))
+	for _, d := range domains {
+		if d = strings.TrimSuffix(strings.ToLower(strings.TrimSpace(d)), "."); d != "" {
+			out = append(out, d)
+		}
+	}
+	sort.Strings(out)
+	n := 0
+	for i, d := range out {
+		if i == 0 || d != out[n-1] {
+			out[n] = d
+			n++
+		}
+	}
+	return out[:n]
+}
+
+// domainLines returns the domains of a list with one domain per line, skipping empty lines and
+// comments starting with #.
+func domainLines(s string) []string {
+	var domains []string
+	for _, line := range strings.Split(s, "\n") {
+		if line = strings.TrimSpace(line); line != "" && !strings.HasPrefix(line, "#") {
+			domains = append(domains, line)
+		}
+	}
+	return domains
+}
+
+// NewDomainList returns a DomainList backed by a map. It is the fastest representation but
+// costs the most memory, use NewCompactDomainList for large lists.
+func NewDomainList(domains []string) DomainList {
+	m := make(mapList, len(domains))
+	for _, d := range normalizeDomains(domains) {
+		m[d] = struct{}{}
+	}
+	return m
+}
+
+type mapList map[string]struct{}
+
+func (m mapList) Contains(domain string) bool {
+	_, ok := m[domain]
+	return ok
+}
+
+// NewCompactDomainList returns a memory efficient DomainList for large lists. The domains are
+// stored in a single sorted string that is searched only when a bloom filter reports the domain
+// may be in the list, so lookups of domains that are not in the list rarely touch it. A list of
+// 100k domains costs about 2MB, several times less than NewDomainList.
+func NewCompactDomainList(domains []string) DomainList {
+	domains = normalizeDomains(domains)
+	l := &compactList{
+		filter:  newBloomFilter(len(domains), 0.01),
+		offsets: make([]uint32, 0, len(domains)+1),
+	}
+	var b strings.Builder
+	for _, d := range domains {
+		l.offsets = append(l.offsets, uint32(b.Len()))
+		l.filter.add(d)
+		b.WriteString(d)
+	}
+	l.offsets = append(l.offsets, uint32(b.Len()))
+	l.data = b.String()
+	return l
+}
+
+type compactList struct {
+	filter  *bloomFilter
+	data    string
+	offsets []uint32
+}
+
+func (l *compactList) Contains(domain string) bool {
+	if !l.filter.mayContain(domain) {
+		return false
+	}
+	n := len(l.offsets) - 1
+	i := sort.Search(n, func(i int) bool {
+		return l.at(i) >= domain
+	})
+	return i < n && l.at(i) == domain
+}
+
+func (l *compactList) at(i int) string {
+	return l.data[l.offsets[i]:l.offsets[i+1]]
+}
+
+// NewBloomDomainList returns a DomainList that only keeps a bloom filter of the domains, using
+// about 1.2 bytes per domain at a false positive rate of 1%. Contains never misses a domain in
+// the list, but reports a domain that is not in the list as present with the given false
+// positive rate. Use it where an occasional false positive is acceptable, otherwise use
+// NewCompactDomainList.
+func NewBloomDomainList(domains []string, falsePositiveRate float64) DomainList {
+	domains = normalizeDomains(domains)
+	f := newBloomFilter(len(domains), falsePositiveRate)
+	for _, d := range domains {
+		f.add(d)
+	}
+	return f
+}
+
+// bloomFilter is a bloom filter using double hashing to derive its hash functions.
+type bloomFilter struct {
+	bits []uint64
+	m    uint64
+	k    uint64
+}
+
+// newBloomFilter returns a bloom filter sized for n entries at false positive rate p.
+func newBloomFilter(n int, p float64) *bloomFilter {
+	if n < 1 {
+		n = 1
+	}
+	if p <= 0 || p >= 1 {
+		p = 0.01
+	}
+	m := uint64(math.Ceil(-float64(n) * math.Log(p) / (math.Ln2 * math.Ln2)))
+	k := uint64(math.Round(float64(m) / float64(n) * math.Ln2))
+	if k < 1 {
+		k = 1
+	}
+	return &bloomFilter{bits: make([]uint64, (m+63)/64), m: m, k: k}
+}
+
+func (f *bloomFilter) hashes(s string) (uint64, uint64) {
+	h := fnv.New64a()
+	h.Write([]byte(s)) // #nosec
+	sum := h.Sum64()
+	return sum & 0xffffffff, sum>>32 | 1
+}
+
+func (f *bloomFilter) add(s string) {
+	h1, h2 := f.hashes(s)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		f.bits[bit/64] |= 1 << (bit % 64)
+	}
+}
+
+func (f *bloomFilter) mayContain(s string) bool {
+	h1, h2 := f.hashes(s)
+	for i := uint64(0); i < f.k; i++ {
+		bit := (h1 + i*h2) % f.m
+		if f.bits[bit/64]&(1<<(bit%64)) == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+func (f *bloomFilter) Contains(domain string) bool {
+	return f.mayContain(domain)
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestDomainList_Contains(t *testing.T) {
+	domains := []string{"Mailinator.com", "tempmail.net.", " 10minutemail.com ", "tempmail.net", ""}
+	lists := map[string]DomainList{
+		"map":     NewDomainList(domains),
+		"compact": NewCompactDomainList(domains),
+		"bloom":   NewBloomDomainList(domains, 0.01),
+	}
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{"1", "mailinator.com", true},
+		{"2", "tempmail.net", true},
+		{"3", "10minutemail.com", true},
+		{"4", "gmail.com", false},
+		{"5", "mailinator.co", false},
+		{"6", "", false},
+	}
+	for list, l := range lists {
+		for _, tt := range tests {
+			t.Run(list+"/"+tt.name, func(t *testing.T) {
+				if got := l.Contains(tt.domain); got != tt.want {
+					t.Errorf("DomainList.Contains(%q) = %v, want %v", tt.domain, got, tt.want)
+				}
+			})
+		}
+	}
+}
+
+func TestNewCompactDomainList_large(t *testing.T) {
+	var domains []string
+	for i := 0; i < 100000; i++ {
+		domains = append(domains, fmt.Sprintf("disposable%d.com", i))
+	}
+	compact := NewCompactDomainList(domains)
+	bloom := NewBloomDomainList(domains, 0.01)
+	for _, d := range domains {
+		if !compact.Contains(d) || !bloom.Contains(d) {
+			t.Fatalf("DomainList.Contains(%q) = false, want true", d)
+		}
+	}
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		d := fmt.Sprintf("legit%d.com", i)
+		if compact.Contains(d) {
+			t.Fatalf("compact DomainList.Contains(%q) = true, want false", d)
+		}
+		if bloom.Contains(d) {
+			falsePositives++
+		}
+	}
+	if falsePositives > 200 {
+		t.Errorf("bloom DomainList false positives = %d of 10000, want about 100", falsePositives)
+	}
+}
@@ -5,14 +5,14 @@
 
 /*
 Package emailaddress provides a tiny library for finding, parsing and validation of email
-addresses. This library is tested for Go v1.9 and above.
+addresses. This library is tested for Go v1.18 and above.
 
 	go get -u github.com/mcnijman/go-emailaddress
 
 # Local validation
 
-Parse and validate the email locally using RFC 5322 regex, note that when err == nil it doesn't
-necessarily mean the email address actually exists.
+Parse and validate the email locally using an RFC 5322 parser, note that when err == nil it
+doesn't necessarily mean the email address actually exists.
 
 	import "github.com/mcnijman/go-emailaddress"
 
@@ -74,17 +74,37 @@ the '@' character (ie. !--logo@2x.png). For more reliable results, you can use t
 		fmt.Println(e)
 	}
 	// foo@bar.com
+
+# Error codes
+
+Every error returned for a rejected email address carries a stable Code, such as EA1001 for an
+invalid local part or EA2003 for a domain without mail hosts, that can be mapped to a localized
+message.
+
+	if _, err := emailaddress.Parse(input); err != nil {
+		fmt.Println(emailaddress.ErrorCode(err), emailaddress.ErrorCode(err).Reason())
+	}
+	// EA1001 invalid-local-part
+
+# Syntax-only builds
+
+Building with the nonet tag, or with TinyGo, leaves out the remote validation code (the Verifier,
+DNS lookups and SMTP probes), so the package no longer imports net and net/smtp. Parsing, finding, normalization and the
+other local checks behave exactly the same, so the validation of a form can run in the browser
+with WebAssembly and on the server. The remote validation functions of EmailAddress and the
+package level LookupHost and TryHost return ErrNoNetwork.
+
+	GOOS=js GOARCH=wasm go build -tags nonet
+	tinygo build -target wasm
 */
 package emailaddress
 
 import (
+	"context"
 	"fmt"
-	"net"
-	"net/smtp"
+	"html"
 	"regexp"
 	"strings"
-
-	"golang.org/x/net/publicsuffix"
 )
 
 var (
@@ -98,8 +118,64 @@ var (
 	// findCommonRegexp is a stricter regex than the RFC 5322 and matches emails that
 	// are more likely to be real.
 	findCommonRegexp = regexp.MustCompile("(?i)([A-Z0-9._%+-]+@[A-Z0-9.-]+\\.[A-Z]{2,24})")
+
+	// validLocalPartRegexp matches the part of the RFC 5322 regex before the @, it is used to
+	// report which part of an invalid address is wrong.
+	validLocalPartRegexp = regexp.MustCompile(fmt.Sprintf("(?i)^%s$", rfc5322[len("(?i)"):strings.Index(rfc5322, ")@(")+1]))
 )
 
+// ParseOption configures Parse and the Find functions.
+type ParseOption func(*parseOptions)
+
+type parseOptions struct {
+	htmlEntities   bool
+	hardened       bool
+	smtputf8       bool
+	noLengthLimits bool
+	regexParser    bool
+	noRoleAccounts bool
+	hostValidator  hostValidator
+}
+
+// hostValidator validates the host of the addresses found when validateHost is true, see
+// WithVerifier.
+type hostValidator interface {
+	validateHost(ctx context.Context, e EmailAddress) error
+}
+
+// validateHost validates the host of e with the hostValidator of o, or
+// EmailAddress.ValidateHostContext.
+func (o parseOptions) validateHost(ctx context.Context, e *EmailAddress) error {
+	if o.hostValidator != nil {
+		return o.hostValidator.validateHost(ctx, *e)
+	}
+	return e.ValidateHostContext(ctx)
+}
+
+// WithHTMLEntityDecoding decodes HTML entities in the input before matching, so scraped input
+// such as foo&#64;bar.com or foo&commat;bar.com is recognized as foo@bar.com.
+func WithHTMLEntityDecoding() ParseOption {
+	return func(o *parseOptions) {
+		o.htmlEntities = true
+	}
+}
+
+func newParseOptions(opts []ParseOption) parseOptions {
+	var o parseOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// prepare applies the options that transform the input before it is matched.
+func (o parseOptions) prepare(haystack []byte) []byte {
+	if o.htmlEntities {
+		return []byte(html.UnescapeString(string(haystack)))
+	}
+	return haystack
+}
+
 // EmailAddress is a structure that stores the address local-part@domain parts.
 type EmailAddress struct {
 	// LocalPart usually the username of an email address.
@@ -117,63 +193,74 @@ func (e EmailAddress) String() string {
 	return fmt.Sprintf("%s@%s", e.LocalPart, e.Domain)
 }
 
-// ValidateHost will test if the email address is actually reachable. It will first try to resolve
-// the host and then start a mail transaction.
-func (e EmailAddress) ValidateHost() error {
-	host, err := LookupHost(e.Domain)
-	if err != nil {
-		return err
-	}
-	return TryHost(host, e)
-}
-
 // ValidateIcanSuffix will test if the public suffix of the domain is managed by ICANN using
 // the golang.org/x/net/publicsuffix package. If not it will return an error. Note that if this
 // method returns an error it does not necessarily mean that the email address is invalid. Also the
-// suffix list in the standard package is embedded and thereby not up to date.
+// suffix list in the standard package is embedded and thereby not up to date, use
+// SetSuffixProvider to replace it.
 func (e EmailAddress) ValidateIcanSuffix() error {
 	d := strings.ToLower(e.Domain)
-	if s, icann := publicsuffix.PublicSuffix(d); !icann {
-		return fmt.Errorf("public suffix is not managed by ICANN, got %s", s)
+	if s, icann := publicSuffix(d); !icann {
+		return newError(CodeNotICANN, fmt.Errorf("public suffix is not managed by ICANN, got %s", s))
 	}
 	return nil
 }
 
+// TLD returns the public suffix of the domain, ie. co.uk for user@mail.example.co.uk, using the
+// public suffix list of SetSuffixProvider. It is lowercased and empty for address literals.
+func (e EmailAddress) TLD() string {
+	d := suffixDomain(e.Domain)
+	if d == "" {
+		return ""
+	}
+	s, _ := publicSuffix(d)
+	return s
+}
+
+// RegistrableDomain returns the domain directly below the public suffix, also known as eTLD+1,
+// ie. example.co.uk for user@mail.example.co.uk. It is lowercased and empty if the domain is a
+// public suffix itself or an address literal.
+func (e EmailAddress) RegistrableDomain() string {
+	d, err := effectiveTLDPlusOne(suffixDomain(e.Domain))
+	if err != nil {
+		return ""
+	}
+	return d
+}
+
+// Subdomain returns the labels of the domain below the registrable domain, ie. mail.corp for
+// user@mail.corp.example.co.uk. It is lowercased and empty if the domain has no subdomain.
+func (e EmailAddress) Subdomain() string {
+	r := e.RegistrableDomain()
+	if r == "" {
+		return ""
+	}
+	return strings.TrimSuffix(strings.TrimSuffix(suffixDomain(e.Domain), r), ".")
+}
+
+// suffixDomain returns domain in the form of the public suffix list, lowercased without a trailing
+// dot, or an empty string for address literals.
+func suffixDomain(domain string) string {
+	if strings.HasPrefix(domain, "[") {
+		return ""
+	}
+	return strings.TrimSuffix(strings.ToLower(domain), ".")
+}
+
 // Find uses the a stricter regex than the RFC 5322 and matches emails that are more likely to be
 // real. Since the RFC 5322 spec is looser, it can miss emails that are real, but will more likely
-// have better results. See examples in the tests.
-func Find(haystack []byte, validateHost bool) (emails []*EmailAddress) {
-	results := findCommonRegexp.FindAll(haystack, -1)
-	for _, r := range results {
-		if e, err := Parse(string(r)); err == nil {
-			if validateHost {
-				if err := e.ValidateHost(); err != nil {
-					continue
-				}
-			}
-			emails = append(emails, e)
-		}
-	}
-	return emails
+// have better results. See examples in the tests. With validateHost the hosts of the addresses are
+// validated concurrently, see FindWithOptions.
+func Find(haystack []byte, validateHost bool, opts ...ParseOption) (emails []*EmailAddress) {
+	return FindWithOptions(haystack, findOptionsOf(validateHost, opts)...)
 }
 
 // FindWithRFC5322 uses the RFC 5322 regex to match, parse and validate any email addresses found in a string.
 // If the validateHost boolean is true it will call the validate host for every email address
 // encountered. As RFC 5322 is really broad this method will likely match images and urls that
 // contain the '@' character.
-func FindWithRFC5322(haystack []byte, validateHost bool) (emails []*EmailAddress) {
-	results := findRfc5322Regexp.FindAll(haystack, -1)
-	for _, r := range results {
-		if e, err := Parse(string(r)); err == nil {
-			if validateHost {
-				if err := e.ValidateHost(); err != nil {
-					continue
-				}
-			}
-			emails = append(emails, e)
-		}
-	}
-	return emails
+func FindWithRFC5322(haystack []byte, validateHost bool, opts ...ParseOption) (emails []*EmailAddress) {
+	return FindWithOptions(haystack, findOptionsOf(validateHost, opts, WithRFC5322())...)
 }
 
 // FindWithIcannSuffix uses the RFC 5322 regex to match, parse and validate any email addresses
@@ -181,26 +268,37 @@ func FindWithRFC5322(haystack []byte, validateHost bool) (emails []*EmailAddress
 // If the validateHost boolean is true it will call the validate host for every email address
 // encountered. As RFC 5322 is really broad this method will likely match images and urls that
 // contain the '@' character.
-func FindWithIcannSuffix(haystack []byte, validateHost bool) (emails []*EmailAddress) {
-	results := Find(haystack, false)
-	for _, e := range results {
-		if err := e.ValidateIcanSuffix(); err == nil {
-			if validateHost {
-				if err := e.ValidateHost(); err != nil {
-					continue
-				}
-			}
-			emails = append(emails, e)
-		}
-	}
-	return emails
+func FindWithIcannSuffix(haystack []byte, validateHost bool, opts ...ParseOption) (emails []*EmailAddress) {
+	return FindWithSuffix(haystack, ICANNSuffixes, validateHost, opts...)
 }
 
 // Parse will parse the input and validate the email locally. If you want to validate the host of
 // this email address remotely call the ValidateHost method.
-func Parse(email string) (*EmailAddress, error) {
-	if !validRfc5322Regexp.MatchString(email) {
-		return nil, fmt.Errorf("format is incorrect for %s", email)
+func Parse(email string, opts ...ParseOption) (*EmailAddress, error) {
+	o := newParseOptions(opts)
+	if o.htmlEntities {
+		email = html.UnescapeString(email)
+	}
+	return o.parse(email)
+}
+
+// parse validates email, which has already been prepared.
+func (o parseOptions) parse(email string) (*EmailAddress, error) {
+	if o.hardened {
+		if err := checkHardened(email); err != nil {
+			return nil, err
+		}
+	} else if o.lengthLimits() {
+		if err := checkLength(email); err != nil {
+			return nil, err
+		}
+	}
+	if o.smtputf8 && !isASCII(email) {
+		if err := checkInternational(email, o.validate); err != nil {
+			return nil, err
+		}
+	} else if err := o.validate(email); err != nil {
+		return nil, err
 	}
 
 	i := strings.LastIndexByte(email, '@')
@@ -210,41 +308,34 @@ func Parse(email string) (*EmailAddress, error) {
 		Domain:    email[i+1:],
 	}
 	if e.Domain == "" {
-		return nil, fmt.Errorf("format is incorrect for %s", email)
+		return nil, formatError(email)
 	}
 	return e, nil
 }
 
-// LookupHost first checks if any MX records are available and if not, it will check
-// if A records are available as they can resolve email server hosts. An error indicates
-// that non of the A or MX records are available.
-func LookupHost(domain string) (string, error) {
-	if mx, err := net.LookupMX(domain); err == nil {
-		return mx[0].Host, nil
-	}
-	if ips, err := net.LookupIP(domain); err == nil {
-		return ips[0].String(), nil // randomly returns IPv4 or IPv6 (when available)
+// formatError returns the error for an email address that doesn't match the RFC 5322 regex, with
+// a code telling which part is invalid.
+func formatError(email string) error {
+	err := fmt.Errorf("format is incorrect for %s", email)
+	i := strings.LastIndexByte(email, '@')
+	switch {
+	case i < 0:
+		return newError(CodeInvalidFormat, err)
+	case !validLocalPartRegexp.MatchString(email[:i]):
+		return newError(CodeInvalidLocalPart, err)
+	default:
+		return newError(CodeInvalidDomain, err)
 	}
-	return "", fmt.Errorf("failed finding MX and A records for domain %s", domain)
 }
 
-// TryHost will verify if we can start a mail transaction with the host. A lot of
-// hosts block this method so don't expect much from it.
-func TryHost(host string, e EmailAddress) error {
-	client, err := smtp.Dial(fmt.Sprintf("%s:%d", host, 587))
-	if err != nil {
-		return err
-	}
-	defer client.Close()
-
-	if err = client.Hello(e.Domain); err == nil {
-		if err = client.Mail(fmt.Sprintf("hello@%s", e.Domain)); err == nil {
-			if err = client.Rcpt(e.String()); err == nil {
-				client.Reset() // #nosec
-				client.Quit()  // #nosec
-				return nil
-			}
+// unbracketHost strips the brackets of an address literal, such as [192.0.2.1], [2001:db8::1] or
+// [IPv6:2001:db8::1], so the host can be joined with a port. IPv6 zone identifiers are kept.
+func unbracketHost(host string) string {
+	if strings.HasPrefix(host, "[") && strings.HasSuffix(host, "]") {
+		host = host[1 : len(host)-1]
+		if len(host) > 5 && strings.EqualFold(host[:5], "IPv6:") {
+			host = host[5:]
 		}
 	}
-	return err
+	return host
 }
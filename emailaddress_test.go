@@ -38,33 +38,6 @@ func TestEmailAddress_String(t *testing.T) {
 	}
 }
 
-func TestEmailAddress_ValidateHost(t *testing.T) {
-	type fields struct {
-		LocalPart string
-		Domain    string
-	}
-	tests := []struct {
-		name    string
-		fields  fields
-		wantErr bool
-	}{
-		{"1", fields{"fake", "example.com"}, true},
-		{"2", fields{"fake", "foo.foobar"}, true},
-		{"3", fields{"infos", "google.com"}, false},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			e := EmailAddress{
-				LocalPart: tt.fields.LocalPart,
-				Domain:    tt.fields.Domain,
-			}
-			if err := e.ValidateHost(); (err != nil) != tt.wantErr {
-				t.Errorf("EmailAddress.ValidateHost() error = %v, wantErr %v", err, tt.wantErr)
-			}
-		})
-	}
-}
-
 func TestEmailAddress_ValidateIcanSuffix(t *testing.T) {
 	type fields struct {
 		LocalPart string
@@ -92,79 +65,51 @@ func TestEmailAddress_ValidateIcanSuffix(t *testing.T) {
 	}
 }
 
-func TestFind(t *testing.T) {
-	type args struct {
-		haystack       []byte
-		validateRemote bool
-	}
+func TestEmailAddress_RegistrableDomain(t *testing.T) {
 	tests := []struct {
-		name       string
-		args       args
-		wantEmails []*EmailAddress
+		name        string
+		email       EmailAddress
+		tld         string
+		registrable string
+		subdomain   string
 	}{
-		{"1", args{[]byte(`test@example.com`), false}, []*EmailAddress{{"test", "example.com"}}},
-		{"2", args{[]byte(`Sample text test@example.com.`), false}, []*EmailAddress{{"test", "example.com"}}},
-		{"3", args{[]byte(`Sample text TestEmail@Example.com.`), false}, []*EmailAddress{{"TestEmail", "Example.com"}}},
-		{"4", args{[]byte(`Send me an email at this@domain.com or info@domain.com or not.`), false}, []*EmailAddress{{"this", "domain.com"}, {"info", "domain.com"}}},
-		{"5", args{[]byte(`Send me an email at fake@example.com.`), true}, nil},
-		{"6", args{[]byte(`<ul><li>Joe Smith has moved on to<a href="http://www.Google.com/">Google</a>, 1600 Amphitheatre Parkway,Mountain View, CA 94043</li><li>info9@google.com</li></ul>`), true}, []*EmailAddress{{"info9", "google.com"}}},
-		{"7", args{[]byte(`test@example.co.uk`), false}, []*EmailAddress{{"test", "example.co.uk"}}},
+		{"1", EmailAddress{"user", "mail.corp.example.co.uk"}, "co.uk", "example.co.uk", "mail.corp"},
+		{"2", EmailAddress{"user", "Example.COM"}, "com", "example.com", ""},
+		{"3", EmailAddress{"user", "mail.example.com."}, "com", "example.com", "mail"},
+		{"4", EmailAddress{"user", "co.uk"}, "co.uk", "", ""},
+		{"5", EmailAddress{"user", "foo.blogspot.com"}, "blogspot.com", "foo.blogspot.com", ""},
+		{"6", EmailAddress{"user", "[192.0.2.1]"}, "", "", ""},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotEmails := Find(tt.args.haystack, tt.args.validateRemote); !reflect.DeepEqual(gotEmails, tt.wantEmails) {
-				t.Errorf("Find() = %v, want %v", gotEmails, tt.wantEmails)
+			if got := tt.email.TLD(); got != tt.tld {
+				t.Errorf("EmailAddress.TLD() = %v, want %v", got, tt.tld)
 			}
-		})
-	}
-}
-
-func TestFindWithRFC5322(t *testing.T) {
-	type args struct {
-		haystack       []byte
-		validateRemote bool
-	}
-	tests := []struct {
-		name       string
-		args       args
-		wantEmails []*EmailAddress
-	}{
-		{"1", args{[]byte(`test@example.com`), false}, []*EmailAddress{{"test", "example.com"}}},
-		{"2", args{[]byte(`Sample text test@example.com.`), false}, []*EmailAddress{{"test", "example.com"}}},
-		{"3", args{[]byte(`Sample text TestEmail@Example.com.`), false}, []*EmailAddress{{"TestEmail", "Example.com"}}},
-		{"4", args{[]byte(`Send me an email at this@domain.com or info@domain.com or not.`), false}, []*EmailAddress{{"this", "domain.com"}, {"info", "domain.com"}}},
-		{"5", args{[]byte(`Send me an email at fake@example.com.`), true}, nil},
-		{"6", args{[]byte(`<ul><li>Joe Smith has moved on to<a href="http://www.Google.com/">Google</a>, 1600 Amphitheatre Parkway,Mountain View, CA 94043</li><li>info9@google.com</li></ul>`), true}, []*EmailAddress{{"info9", "google.com"}}},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if gotEmails := FindWithRFC5322(tt.args.haystack, tt.args.validateRemote); !reflect.DeepEqual(gotEmails, tt.wantEmails) {
-				t.Errorf("FindWithRFC5322() = %v, want %v", gotEmails, tt.wantEmails)
+			if got := tt.email.RegistrableDomain(); got != tt.registrable {
+				t.Errorf("EmailAddress.RegistrableDomain() = %v, want %v", got, tt.registrable)
+			}
+			if got := tt.email.Subdomain(); got != tt.subdomain {
+				t.Errorf("EmailAddress.Subdomain() = %v, want %v", got, tt.subdomain)
 			}
 		})
 	}
 }
 
-func TestFindWithIcannSuffix(t *testing.T) {
-	type args struct {
-		haystack     []byte
-		validateHost bool
-	}
+func TestFind_htmlEntities(t *testing.T) {
 	tests := []struct {
 		name       string
-		args       args
+		haystack   []byte
+		opts       []ParseOption
 		wantEmails []*EmailAddress
 	}{
-		{"1", args{[]byte(`Sample text test@example.com.`), false}, []*EmailAddress{{"test", "example.com"}}},
-		{"2", args{[]byte(`Sample text test@example.foobar.`), false}, nil},
-		{"3", args{[]byte(`Send me an email at fake@example.foobar.`), true}, nil},
-		{"4", args{[]byte(`<ul><li>Joe Smith has moved on to<a href="http://www.Google.com/">Google</a>, 1600 Amphitheatre Parkway,Mountain View, CA 94043</li><li>info10@google.com</li></ul>`), true}, []*EmailAddress{{"info10", "google.com"}}},
-		{"5", args{[]byte(`Sample text test@25c95f9e-b0d4-4d67-a159-56f360b48273.museum.`), true}, nil},
+		{"1", []byte(`Mail foo&#64;bar.com today`), nil, nil},
+		{"2", []byte(`Mail foo&#64;bar.com today`), []ParseOption{WithHTMLEntityDecoding()}, []*EmailAddress{{"foo", "bar.com"}}},
+		{"3", []byte(`<a>foo&commat;bar&period;com</a>, baz&#x40;bar.com`), []ParseOption{WithHTMLEntityDecoding()}, []*EmailAddress{{"foo", "bar.com"}, {"baz", "bar.com"}}},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			if gotEmails := FindWithIcannSuffix(tt.args.haystack, tt.args.validateHost); !reflect.DeepEqual(gotEmails, tt.wantEmails) {
-				t.Errorf("FindWithIcannSuffix() = %v, want %v", gotEmails, tt.wantEmails)
+			if gotEmails := Find(tt.haystack, false, tt.opts...); !reflect.DeepEqual(gotEmails, tt.wantEmails) {
+				t.Errorf("Find() = %v, want %v", gotEmails, tt.wantEmails)
 			}
 		})
 	}
@@ -222,51 +167,29 @@ func TestParse(t *testing.T) {
 	}
 }
 
-func Test_LookupHost(t *testing.T) {
-	type args struct {
-		domain string
-	}
+func TestParse_htmlEntities(t *testing.T) {
 	tests := []struct {
 		name    string
-		args    args
+		email   string
+		opts    []ParseOption
+		want    *EmailAddress
 		wantErr bool
 	}{
-		{"1", args{"google.com"}, false},
-		{"2", args{"example.com"}, false},
-		{"3", args{"fake.foobar"}, true},
+		{"1", "foo&#64;bar.com", nil, nil, true},
+		{"2", "foo&#64;bar.com", []ParseOption{WithHTMLEntityDecoding()}, &EmailAddress{"foo", "bar.com"}, false},
+		{"3", "foo&commat;bar.com", []ParseOption{WithHTMLEntityDecoding()}, &EmailAddress{"foo", "bar.com"}, false},
+		{"4", "foo&amp;bar@bar.com", []ParseOption{WithHTMLEntityDecoding()}, &EmailAddress{"foo&bar", "bar.com"}, false},
+		{"5", "foo@bar.com", []ParseOption{WithHTMLEntityDecoding()}, &EmailAddress{"foo", "bar.com"}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			got, err := LookupHost(tt.args.domain)
+			got, err := Parse(tt.email, tt.opts...)
 			if (err != nil) != tt.wantErr {
-				t.Errorf("LookupHost() error = %v, wantErr %v", err, tt.wantErr)
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
 				return
 			}
-			if got == "" && !tt.wantErr {
-				t.Errorf("LookupHost() = %v, want non empty", got)
-			}
-		})
-	}
-}
-
-func Test_TryHost(t *testing.T) {
-	type args struct {
-		host string
-		e    EmailAddress
-	}
-	tests := []struct {
-		name    string
-		args    args
-		wantErr bool
-	}{
-		{"1", args{"aspmx.l.google.com.", EmailAddress{"info1", "google.com"}}, false},
-		{"2", args{"173.194.68.27", EmailAddress{"info2", "google.com"}}, false},
-		{"3", args{"non valid host", EmailAddress{"fake", "example.com"}}, true},
-	}
-	for _, tt := range tests {
-		t.Run(tt.name, func(t *testing.T) {
-			if err := TryHost(tt.args.host, tt.args.e); (err != nil) != tt.wantErr {
-				t.Errorf("TryHost() error = %v, wantErr %v", err, tt.wantErr)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
 			}
 		})
 	}
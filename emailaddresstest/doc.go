@@ -0,0 +1,16 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+// Package emailaddresstest provides an in-process SMTP server and a stub resolver to test code
+// validating addresses with an emailaddress.Verifier without network access.
+//
+//	s := emailaddresstest.NewServer()
+//	defer s.Close()
+//	s.SetMailbox("info@example.com", emailaddresstest.Accept)
+//	s.SetMailbox("later@example.com", emailaddresstest.Greylist)
+//
+//	v := emailaddress.NewVerifier(s.Options("example.com")...)
+//	status, err := v.CheckHost(ctx, emailaddress.EmailAddress{LocalPart: "info", Domain: "example.com"})
+package emailaddresstest
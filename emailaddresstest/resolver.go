@@ -0,0 +1,115 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddresstest
+
+import (
+	"context"
+	"net"
+	"strings"
+	"sync"
+)
+
+// Resolver is a stub emailaddress.Resolver answering from its records. Names without records
+// don't exist. It is safe for concurrent use.
+type Resolver struct {
+	mu  sync.Mutex
+	mx  map[string][]*net.MX
+	ip  map[string][]net.IP
+	txt map[string][]string
+	ptr map[string][]string
+}
+
+// NewResolver returns a Resolver without records.
+func NewResolver() *Resolver {
+	return &Resolver{
+		mx:  make(map[string][]*net.MX),
+		ip:  make(map[string][]net.IP),
+		txt: make(map[string][]string),
+		ptr: make(map[string][]string),
+	}
+}
+
+// AddMX adds a mail host of domain with the preference pref.
+func (r *Resolver) AddMX(domain, host string, pref uint16) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	domain = canonical(domain)
+	r.mx[domain] = append(r.mx[domain], &net.MX{Host: host, Pref: pref})
+}
+
+// AddIP adds an address record of host, and its reverse record.
+func (r *Resolver) AddIP(host string, ip net.IP) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	host = canonical(host)
+	r.ip[host] = append(r.ip[host], ip)
+	r.ptr[ip.String()] = append(r.ptr[ip.String()], host+".")
+}
+
+// AddTXT adds a TXT record of name, ie. the SPF policy of a domain or the DMARC policy at
+// _dmarc.domain.
+func (r *Resolver) AddTXT(name, txt string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	name = canonical(name)
+	r.txt[name] = append(r.txt[name], txt)
+}
+
+// LookupMX returns the mail hosts of name.
+func (r *Resolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if mx, ok := r.mx[canonical(name)]; ok {
+		return append([]*net.MX(nil), mx...), nil
+	}
+	return nil, notFound(name)
+}
+
+// LookupIP returns the addresses of host of network, which is ip, ip4 or ip6.
+func (r *Resolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var ips []net.IP
+	for _, ip := range r.ip[canonical(host)] {
+		is4 := ip.To4() != nil
+		if network == "ip" || network == "ip4" && is4 || network == "ip6" && !is4 {
+			ips = append(ips, ip)
+		}
+	}
+	if len(ips) == 0 {
+		return nil, notFound(host)
+	}
+	return ips, nil
+}
+
+// LookupTXT returns the TXT records of name.
+func (r *Resolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if txt, ok := r.txt[canonical(name)]; ok {
+		return append([]string(nil), txt...), nil
+	}
+	return nil, notFound(name)
+}
+
+// LookupAddr returns the host names of addr, from the address records added with AddIP.
+func (r *Resolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if names, ok := r.ptr[addr]; ok {
+		return append([]string(nil), names...), nil
+	}
+	return nil, notFound(addr)
+}
+
+// canonical returns name in lower case without the trailing dot of a fully qualified name.
+func canonical(name string) string {
+	return strings.TrimSuffix(strings.ToLower(name), ".")
+}
+
+func notFound(name string) error {
+	return &net.DNSError{Err: "no such host", Name: name, IsNotFound: true}
+}
@@ -0,0 +1,230 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddresstest
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/mcnijman/go-emailaddress"
+)
+
+// Behavior is the way the Server answers a recipient.
+type Behavior int
+
+const (
+	// Reject rejects the recipient permanently with 550 5.1.1, the recipient is invalid.
+	Reject Behavior = iota
+
+	// Accept accepts the recipient with 250.
+	Accept
+
+	// Greylist rejects the recipient temporarily with 451 4.7.1, the recipient is unverifiable.
+	Greylist
+
+	// Timeout never answers, so the Verifier gives up when its command timeout or the deadline
+	// of its context passes.
+	Timeout
+)
+
+// Server is an in-process SMTP server on the loopback interface. It answers every recipient with
+// the Behavior of its mailbox, or its default Behavior, which rejects the recipient unless set
+// otherwise with SetDefault. It is safe for concurrent use.
+type Server struct {
+	ln net.Listener
+	wg sync.WaitGroup
+
+	mu        sync.Mutex
+	def       Behavior
+	mailboxes map[string]Behavior
+	conns     map[net.Conn]struct{}
+	cmds      []string
+}
+
+// NewServer starts a Server listening on a random port of 127.0.0.1. The caller should call
+// Close when finished, to shut it down.
+func NewServer() *Server {
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		panic(fmt.Sprintf("emailaddresstest: failed to listen on a port: %v", err))
+	}
+	s := &Server{
+		ln:        ln,
+		mailboxes: make(map[string]Behavior),
+		conns:     make(map[net.Conn]struct{}),
+	}
+	s.wg.Add(1)
+	go s.serve()
+	return s
+}
+
+// Close shuts down the server, closing its open connections, and waits for them to finish.
+func (s *Server) Close() {
+	s.ln.Close() // #nosec
+	s.mu.Lock()
+	for conn := range s.conns {
+		conn.Close() // #nosec
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+}
+
+// Host returns the address the server listens on, 127.0.0.1.
+func (s *Server) Host() string {
+	host, _, _ := net.SplitHostPort(s.ln.Addr().String())
+	return host
+}
+
+// Port returns the port the server listens on.
+func (s *Server) Port() int {
+	_, p, _ := net.SplitHostPort(s.ln.Addr().String())
+	port, _ := strconv.Atoi(p)
+	return port
+}
+
+// SetMailbox sets the Behavior for the recipient address.
+func (s *Server) SetMailbox(address string, b Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.mailboxes[strings.ToLower(address)] = b
+}
+
+// SetDefault sets the Behavior for the recipients without a mailbox. Accept makes the server a
+// catch-all host, see CatchAll.
+func (s *Server) SetDefault(b Behavior) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.def = b
+}
+
+// CatchAll makes the server accept every recipient without a mailbox, like a host accepting any
+// recipient of its domains. Verify reports the addresses of a catch-all host as risky.
+func (s *Server) CatchAll() {
+	s.SetDefault(Accept)
+}
+
+// Commands returns the commands the server received, in order.
+func (s *Server) Commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.cmds...)
+}
+
+// Resolver returns a Resolver with the server as the mail host of domains. The host of the MX
+// records is the address of the server, since the Verifier dials mail hosts with the system
+// resolver.
+func (s *Server) Resolver(domains ...string) *Resolver {
+	r := NewResolver()
+	for _, d := range domains {
+		r.AddMX(d, s.Host(), 10)
+	}
+	return r
+}
+
+// Options returns the options of a Verifier probing the server for the addresses of domains,
+// with the Resolver of the server and its port.
+func (s *Server) Options(domains ...string) []emailaddress.Option {
+	return []emailaddress.Option{
+		emailaddress.WithResolver(s.Resolver(domains...)),
+		emailaddress.WithPort(s.Port()),
+	}
+}
+
+func (s *Server) behavior(address string) Behavior {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if b, ok := s.mailboxes[strings.ToLower(address)]; ok {
+		return b
+	}
+	return s.def
+}
+
+func (s *Server) serve() {
+	defer s.wg.Done()
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.conns[conn] = struct{}{}
+		s.mu.Unlock()
+		s.wg.Add(1)
+		go s.handle(conn)
+	}
+}
+
+func (s *Server) handle(conn net.Conn) {
+	defer s.wg.Done()
+	defer func() {
+		s.mu.Lock()
+		delete(s.conns, conn)
+		s.mu.Unlock()
+		conn.Close() // #nosec
+	}()
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n")) // #nosec
+	}
+	reply("220 mx.test ESMTP emailaddresstest")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		s.mu.Lock()
+		s.cmds = append(s.cmds, line)
+		s.mu.Unlock()
+
+		verb := strings.ToUpper(line)
+		if i := strings.IndexAny(verb, " :"); i >= 0 {
+			verb = verb[:i]
+		}
+		switch verb {
+		case "EHLO":
+			reply("250-mx.test greets you")
+			reply("250-PIPELINING")
+			reply("250-8BITMIME")
+			reply("250 SMTPUTF8")
+		case "HELO", "MAIL", "RSET", "NOOP":
+			reply("250 OK")
+		case "RCPT":
+			switch s.behavior(commandAddr(line)) {
+			case Accept:
+				reply("250 2.1.5 OK")
+			case Greylist:
+				reply("451 4.7.1 greylisted, try again later")
+			case Timeout:
+				io.Copy(io.Discard, r) // #nosec
+				return
+			default:
+				reply("550 5.1.1 no such user")
+			}
+		case "QUIT":
+			reply("221 2.0.0 bye")
+			return
+		default:
+			reply("502 5.5.2 command not implemented")
+		}
+	}
+}
+
+// commandAddr returns the address of a RCPT TO command.
+func commandAddr(line string) string {
+	addr := line[strings.IndexByte(line, ':')+1:]
+	if i := strings.IndexByte(addr, '>'); i >= 0 {
+		addr = addr[:i]
+	}
+	return strings.Trim(addr, "< ")
+}
@@ -0,0 +1,78 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddresstest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mcnijman/go-emailaddress"
+)
+
+func TestServer(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.SetMailbox("info@example.com", Accept)
+	s.SetMailbox("later@example.com", Greylist)
+	s.SetMailbox("slow@example.com", Timeout)
+
+	opts := append(s.Options("example.com"), emailaddress.WithCommandTimeout(200*time.Millisecond))
+	v := emailaddress.NewVerifier(opts...)
+
+	tests := []struct {
+		name  string
+		local string
+		want  emailaddress.HostStatus
+	}{
+		{name: "1", local: "info", want: emailaddress.HostVerified},
+		{name: "2", local: "nobody", want: emailaddress.HostInvalid},
+		{name: "3", local: "later", want: emailaddress.HostUnverifiable},
+		{name: "4", local: "slow", want: emailaddress.HostUnverifiable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := v.CheckHost(context.Background(), emailaddress.EmailAddress{LocalPart: tt.local, Domain: "example.com"})
+			if got != tt.want {
+				t.Errorf("Verifier.CheckHost() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+	if got := s.Commands(); len(got) == 0 || got[0] == "" {
+		t.Errorf("Server.Commands() = %q, want the commands of the probes", got)
+	}
+}
+
+func TestServer_CatchAll(t *testing.T) {
+	s := NewServer()
+	defer s.Close()
+	s.CatchAll()
+
+	v := emailaddress.NewVerifier(s.Options("example.com")...)
+	r := v.Verify(context.Background(), "info@example.com")
+	if r.Verdict != emailaddress.VerdictRisky || !r.CatchAll {
+		t.Errorf("Verifier.Verify() = %v, catch-all %v, want %v", r.Verdict, r.CatchAll, emailaddress.VerdictRisky)
+	}
+}
+
+func TestResolver(t *testing.T) {
+	r := NewResolver()
+	r.AddMX("Example.com.", "mx.example.com.", 10)
+	r.AddTXT("example.com", "v=spf1 -all")
+
+	ctx := context.Background()
+	if mx, err := r.LookupMX(ctx, "example.com"); err != nil || len(mx) != 1 || mx[0].Host != "mx.example.com." {
+		t.Errorf("Resolver.LookupMX() = %v, %v, want mx.example.com.", mx, err)
+	}
+	if txt, err := r.LookupTXT(ctx, "EXAMPLE.com"); err != nil || len(txt) != 1 {
+		t.Errorf("Resolver.LookupTXT() = %v, %v, want 1 record", txt, err)
+	}
+	if _, err := r.LookupMX(ctx, "example.org"); err == nil {
+		t.Errorf("Resolver.LookupMX() error = nil, want not found")
+	}
+}
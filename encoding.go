@@ -0,0 +1,82 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+// MarshalYAML implements the Marshaler interface of gopkg.in/yaml.v2 and gopkg.in/yaml.v3, an
+// email address is represented as a string.
+func (e EmailAddress) MarshalYAML() (interface{}, error) {
+	return e.String(), nil
+}
+
+// UnmarshalYAML implements the Unmarshaler interface of gopkg.in/yaml.v2, which is also supported
+// by gopkg.in/yaml.v3. The value is parsed and validated locally, an empty value results in the
+// zero EmailAddress.
+func (e *EmailAddress) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+	return e.set(s)
+}
+
+// EmailAddressFields is an EmailAddress that is marshalled to JSON as an object with the LocalPart
+// and Domain fields, as EmailAddress was before it implemented json.Marshaler. Convert to it to
+// keep the old form, ie. EmailAddressFields(e).
+type EmailAddressFields EmailAddress
+
+// MarshalJSON implements json.Marshaler, an email address is represented as a string.
+func (e EmailAddress) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.String())
+}
+
+// UnmarshalJSON implements json.Unmarshaler. The string is parsed and validated locally, an empty
+// string results in the zero EmailAddress and null is ignored. Objects with the LocalPart and
+// Domain fields, as marshalled by EmailAddressFields, are accepted without validation.
+func (e *EmailAddress) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if bytes.Equal(data, []byte("null")) {
+		return nil
+	}
+	if bytes.HasPrefix(data, []byte("{")) {
+		return json.Unmarshal(data, (*EmailAddressFields)(e))
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	return e.set(s)
+}
+
+// MarshalText implements encoding.TextMarshaler, so email addresses can be used as keys of JSON
+// objects and in text based formats such as TOML.
+func (e EmailAddress) MarshalText() ([]byte, error) {
+	return []byte(e.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler, ie. for flag.TextVar. The text is parsed and
+// validated locally, empty text results in the zero EmailAddress.
+func (e *EmailAddress) UnmarshalText(text []byte) error {
+	return e.set(string(text))
+}
+
+// set parses s into e, an empty s results in the zero EmailAddress.
+func (e *EmailAddress) set(s string) error {
+	if s == "" {
+		*e = EmailAddress{}
+		return nil
+	}
+	p, err := Parse(s)
+	if err != nil {
+		return err
+	}
+	*e = *p
+	return nil
+}
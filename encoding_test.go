@@ -0,0 +1,173 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+)
+
+func TestEmailAddress_MarshalYAML(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want interface{}
+	}{
+		{"1", EmailAddress{"foo", "bar.com"}, "foo@bar.com"},
+		{"2", EmailAddress{}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.e.MarshalYAML()
+			if err != nil {
+				t.Errorf("EmailAddress.MarshalYAML() error = %v", err)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EmailAddress.MarshalYAML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_UnmarshalYAML(t *testing.T) {
+	// value returns an unmarshal function as passed by the yaml packages for a scalar node.
+	value := func(v interface{}) func(interface{}) error {
+		return func(out interface{}) error {
+			s, ok := v.(string)
+			if !ok {
+				return errors.New("cannot unmarshal into string")
+			}
+			*out.(*string) = s
+			return nil
+		}
+	}
+	tests := []struct {
+		name      string
+		unmarshal func(interface{}) error
+		want      EmailAddress
+		wantErr   bool
+	}{
+		{"1", value("foo@bar.com"), EmailAddress{"foo", "bar.com"}, false},
+		{"2", value(""), EmailAddress{}, false},
+		{"3", value("foo"), EmailAddress{}, true},
+		{"4", value(42), EmailAddress{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got EmailAddress
+			if err := got.UnmarshalYAML(tt.unmarshal); (err != nil) != tt.wantErr {
+				t.Errorf("EmailAddress.UnmarshalYAML() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EmailAddress.UnmarshalYAML() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_MarshalJSON(t *testing.T) {
+	tests := []struct {
+		name string
+		v    interface{}
+		want string
+	}{
+		{"1", EmailAddress{"foo", "bar.com"}, `"foo@bar.com"`},
+		{"2", EmailAddress{}, `""`},
+		{"3", &EmailAddress{"foo", "bar.com"}, `"foo@bar.com"`},
+		{"4", struct{ Email EmailAddress }{EmailAddress{"foo", "bar.com"}}, `{"Email":"foo@bar.com"}`},
+		{"5", EmailAddressFields{"foo", "bar.com"}, `{"LocalPart":"foo","Domain":"bar.com"}`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := json.Marshal(tt.v)
+			if err != nil {
+				t.Errorf("json.Marshal() error = %v", err)
+				return
+			}
+			if string(got) != tt.want {
+				t.Errorf("json.Marshal() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_UnmarshalJSON(t *testing.T) {
+	tests := []struct {
+		name    string
+		data    string
+		want    EmailAddress
+		wantErr bool
+	}{
+		{"1", `"foo@bar.com"`, EmailAddress{"foo", "bar.com"}, false},
+		{"2", `""`, EmailAddress{}, false},
+		{"3", `null`, EmailAddress{"old", "bar.com"}, false},
+		{"4", `"foo"`, EmailAddress{"old", "bar.com"}, true},
+		{"5", `42`, EmailAddress{"old", "bar.com"}, true},
+		{"6", `{"LocalPart":"foo","Domain":"bar.com"}`, EmailAddress{"foo", "bar.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EmailAddress{"old", "bar.com"}
+			if err := json.Unmarshal([]byte(tt.data), &got); (err != nil) != tt.wantErr {
+				t.Errorf("EmailAddress.UnmarshalJSON() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EmailAddress.UnmarshalJSON() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_UnmarshalText(t *testing.T) {
+	tests := []struct {
+		name    string
+		text    string
+		want    EmailAddress
+		wantErr bool
+	}{
+		{"1", "foo@bar.com", EmailAddress{"foo", "bar.com"}, false},
+		{"2", "", EmailAddress{}, false},
+		{"3", "foo", EmailAddress{"old", "bar.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EmailAddress{"old", "bar.com"}
+			if err := got.UnmarshalText([]byte(tt.text)); (err != nil) != tt.wantErr {
+				t.Errorf("EmailAddress.UnmarshalText() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EmailAddress.UnmarshalText() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_MarshalText_mapKey(t *testing.T) {
+	m := map[EmailAddress]int{{"foo", "bar.com"}: 1}
+	b, err := json.Marshal(m)
+	if err != nil {
+		t.Fatalf("json.Marshal() error = %v", err)
+	}
+	if want := `{"foo@bar.com":1}`; string(b) != want {
+		t.Errorf("json.Marshal() = %s, want %s", b, want)
+	}
+
+	var got map[EmailAddress]int
+	if err := json.Unmarshal(b, &got); err != nil {
+		t.Fatalf("json.Unmarshal() error = %v", err)
+	}
+	if got[EmailAddress{"foo", "bar.com"}] != 1 {
+		t.Errorf("json.Unmarshal() = %v, want %v", got, m)
+	}
+	if err := json.Unmarshal([]byte(`{"foo":1}`), &got); err == nil {
+		t.Error("json.Unmarshal() of an invalid key error = nil, want an error")
+	}
+}
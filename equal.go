@@ -0,0 +1,82 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+)
+
+// EqualOption configures Equal.
+type EqualOption func(*equalOptions)
+
+type equalOptions struct {
+	foldLocalPart bool
+	normalize     bool
+	form          NormalizationForm
+	canonical     bool
+}
+
+// WithCaseInsensitiveLocalPart compares the local parts case insensitively. The local part is case
+// sensitive as per RFC 5321, but most systems treat it as case insensitive.
+func WithCaseInsensitiveLocalPart() EqualOption {
+	return func(o *equalOptions) {
+		o.foldLocalPart = true
+	}
+}
+
+// WithNormalization compares both parts in the Unicode normalization form, so precomposed and
+// decomposed characters compare equal. See Normalize.
+func WithNormalization(form NormalizationForm) EqualOption {
+	return func(o *equalOptions) {
+		o.normalize, o.form = true, form
+	}
+}
+
+// WithCanonicalization compares the mailboxes the addresses deliver to according to the rules of
+// their providers, so foo.bar+news@gmail.com equals foobar@gmail.com. See Canonical.
+func WithCanonicalization() EqualOption {
+	return func(o *equalOptions) {
+		o.canonical = true
+	}
+}
+
+// Equal reports whether the email address equals other. Domains are compared case insensitively,
+// local parts case sensitively unless WithCaseInsensitiveLocalPart is passed.
+func (e EmailAddress) Equal(other EmailAddress, opts ...EqualOption) bool {
+	var o equalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o.key(e) == o.key(other)
+}
+
+// key returns the form of e that is compared.
+func (o equalOptions) key(e EmailAddress) EmailAddress {
+	if o.normalize {
+		e = e.Normalize(NormalizeOptions{Form: o.form, FoldLocalPart: o.foldLocalPart})
+	} else if o.foldLocalPart {
+		e.LocalPart = cases.Fold().String(e.LocalPart)
+	}
+	if o.canonical {
+		return e.Canonical()
+	}
+	e.Domain = strings.TrimSuffix(strings.ToLower(e.Domain), ".")
+	return e
+}
+
+// Compare returns an integer comparing the email address to other, ie. to sort addresses. The
+// result is 0 if they are Equal, -1 if the address sorts before other and +1 otherwise.
+// Addresses are ordered by domain, compared case insensitively, and then by local part.
+func (e EmailAddress) Compare(other EmailAddress) int {
+	var o equalOptions
+	a, b := o.key(e), o.key(other)
+	if c := strings.Compare(a.Domain, b.Domain); c != 0 {
+		return c
+	}
+	return strings.Compare(a.LocalPart, b.LocalPart)
+}
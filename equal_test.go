@@ -0,0 +1,75 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"sort"
+	"testing"
+)
+
+func TestEmailAddress_Equal(t *testing.T) {
+	tests := []struct {
+		name string
+		a    EmailAddress
+		b    EmailAddress
+		opts []EqualOption
+		want bool
+	}{
+		{"1", EmailAddress{"foo", "bar.com"}, EmailAddress{"foo", "BAR.com"}, nil, true},
+		{"2", EmailAddress{"foo", "bar.com"}, EmailAddress{"foo", "bar.com."}, nil, true},
+		{"3", EmailAddress{"foo", "bar.com"}, EmailAddress{"Foo", "bar.com"}, nil, false},
+		{"4", EmailAddress{"foo", "bar.com"}, EmailAddress{"Foo", "bar.com"}, []EqualOption{WithCaseInsensitiveLocalPart()}, true},
+		{"5", EmailAddress{"foo", "bar.com"}, EmailAddress{"foo", "baz.com"}, []EqualOption{WithCaseInsensitiveLocalPart()}, false},
+		{"6", EmailAddress{"café", "bar.com"}, EmailAddress{"café", "bar.com"}, nil, false},
+		{"7", EmailAddress{"café", "bar.com"}, EmailAddress{"café", "bar.com"}, []EqualOption{WithNormalization(NFC)}, true},
+		{"8", EmailAddress{"café", "bar.com"}, EmailAddress{"CAFÉ", "bar.com"}, []EqualOption{WithNormalization(NFC), WithCaseInsensitiveLocalPart()}, true},
+		{"9", EmailAddress{"foo.bar+news", "gmail.com"}, EmailAddress{"foobar", "gmail.com"}, nil, false},
+		{"10", EmailAddress{"foo.bar+news", "gmail.com"}, EmailAddress{"FooBar", "googlemail.com"}, []EqualOption{WithCanonicalization()}, true},
+		{"11", EmailAddress{"foo.bar", "example.com"}, EmailAddress{"foobar", "example.com"}, []EqualOption{WithCanonicalization()}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Equal(tt.b, tt.opts...); got != tt.want {
+				t.Errorf("EmailAddress.Equal() = %v, want %v", got, tt.want)
+			}
+			if got := tt.b.Equal(tt.a, tt.opts...); got != tt.want {
+				t.Errorf("EmailAddress.Equal() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_Compare(t *testing.T) {
+	tests := []struct {
+		name string
+		a    EmailAddress
+		b    EmailAddress
+		want int
+	}{
+		{"1", EmailAddress{"foo", "bar.com"}, EmailAddress{"foo", "BAR.com"}, 0},
+		{"2", EmailAddress{"foo", "a.com"}, EmailAddress{"bar", "b.com"}, -1},
+		{"3", EmailAddress{"foo", "b.com"}, EmailAddress{"bar", "b.com"}, 1},
+		{"4", EmailAddress{"Foo", "b.com"}, EmailAddress{"foo", "b.com"}, -1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.a.Compare(tt.b); got != tt.want {
+				t.Errorf("EmailAddress.Compare() = %v, want %v", got, tt.want)
+			}
+			if got := tt.b.Compare(tt.a); got != -tt.want {
+				t.Errorf("EmailAddress.Compare() reversed = %v, want %v", got, -tt.want)
+			}
+		})
+	}
+
+	emails := []EmailAddress{{"b", "y.com"}, {"a", "Z.com"}, {"c", "x.com"}, {"a", "y.com"}}
+	sort.Slice(emails, func(i, j int) bool { return emails[i].Compare(emails[j]) < 0 })
+	want := []EmailAddress{{"c", "x.com"}, {"a", "y.com"}, {"b", "y.com"}, {"a", "Z.com"}}
+	if !reflect.DeepEqual(emails, want) {
+		t.Errorf("sorted = %v, want %v", emails, want)
+	}
+}
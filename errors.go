@@ -0,0 +1,196 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"errors"
+	"strings"
+)
+
+// Code is a stable, machine-readable identifier of why an email address was rejected, such as
+// EA2003 for a domain without mail hosts. Codes never change meaning, so frontends can map them to
+// localized messages instead of relying on the English error strings. Codes starting with EA1 are
+// syntax errors, EA2 DNS errors, EA3 SMTP errors and EA4 policy errors.
+type Code string
+
+const (
+	// CodeInvalidFormat indicates the input is not of the form local-part@domain.
+	CodeInvalidFormat Code = "EA1000"
+	// CodeInvalidLocalPart indicates the local part is not valid.
+	CodeInvalidLocalPart Code = "EA1001"
+	// CodeInvalidDomain indicates the domain is not valid.
+	CodeInvalidDomain Code = "EA1002"
+	// CodeAddressTooLong indicates the address exceeds 254 characters.
+	CodeAddressTooLong Code = "EA1003"
+	// CodeLocalPartTooLong indicates the local part exceeds 64 characters.
+	CodeLocalPartTooLong Code = "EA1004"
+	// CodeDomainTooLong indicates the domain exceeds 253 characters.
+	CodeDomainTooLong Code = "EA1005"
+	// CodeLabelTooLong indicates a label of the domain exceeds 63 characters.
+	CodeLabelTooLong Code = "EA1006"
+	// CodeInvalidMailto indicates the input is not a valid mailto URI.
+	CodeInvalidMailto Code = "EA1007"
+	// CodeInvalidMailbox indicates the input is not a valid mailbox, such as Name <foo@bar.com>.
+	CodeInvalidMailbox Code = "EA1008"
+
+	// CodeDNSFailure indicates a temporary DNS failure.
+	CodeDNSFailure Code = "EA2001"
+	// CodeDNSTimeout indicates the DNS queries timed out.
+	CodeDNSTimeout Code = "EA2002"
+	// CodeNoMX indicates the domain has no MX, A or AAAA records.
+	CodeNoMX Code = "EA2003"
+
+	// CodeSMTPUnreachable indicates no connection could be made to the mail host.
+	CodeSMTPUnreachable Code = "EA3001"
+	// CodeSMTPRejected indicates the mail host rejected the connection or the sender.
+	CodeSMTPRejected Code = "EA3002"
+	// CodeMailboxRejected indicates the mail host permanently rejected the recipient.
+	CodeMailboxRejected Code = "EA3003"
+	// CodeSMTPTemporary indicates the mail host replied with a temporary failure.
+	CodeSMTPTemporary Code = "EA3004"
+	// CodeSMTPFailure indicates the conversation with the mail host failed.
+	CodeSMTPFailure Code = "EA3005"
+	// CodeTLSRequired indicates the mail host offers no TLS or a certificate that is not valid,
+	// while WithRequireTLS is used.
+	CodeTLSRequired Code = "EA3006"
+	// CodeSMTPUTF8Unsupported indicates the address is internationalized and the mail host does
+	// not support SMTPUTF8, so mail to it can't be delivered.
+	CodeSMTPUTF8Unsupported Code = "EA3007"
+
+	// CodeDenied indicates the domain is on the deny list.
+	CodeDenied Code = "EA4001"
+	// CodeDisposable indicates the domain is a disposable email provider.
+	CodeDisposable Code = "EA4002"
+	// CodeNotICANN indicates the public suffix of the domain is not managed by ICANN.
+	CodeNotICANN Code = "EA4003"
+	// CodeBidiControl indicates the address contains a bidirectional control character.
+	CodeBidiControl Code = "EA4004"
+	// CodeInvisibleCharacter indicates the address contains an invisible character.
+	CodeInvisibleCharacter Code = "EA4005"
+	// CodeMixedScript indicates a label of the domain mixes scripts.
+	CodeMixedScript Code = "EA4006"
+	// CodeParked indicates the mail host of the domain belongs to a domain parking service.
+	CodeParked Code = "EA4007"
+	// CodeBudgetExhausted indicates a query budget of the Verifier is used up.
+	CodeBudgetExhausted Code = "EA4008"
+	// CodeWildcardDNS indicates the domain resolves any subdomain, so its mail hosts are not
+	// evidence that it receives mail.
+	CodeWildcardDNS Code = "EA4009"
+)
+
+// Sentinel errors for the kinds of failures, match them with errors.Is. Every *Error matches the
+// sentinel of its Code, ie. an error with CodeNoMX matches ErrNoMXRecords.
+var (
+	// ErrInvalidFormat is matched by every syntax error, the codes starting with EA1.
+	ErrInvalidFormat = errors.New("invalid format")
+
+	// ErrNoMXRecords is matched when the domain has no mail host.
+	ErrNoMXRecords = errors.New("no MX records")
+
+	// ErrSMTPConnection is matched when the mail host could not be reached or the conversation with
+	// it failed.
+	ErrSMTPConnection = errors.New("SMTP connection failed")
+
+	// ErrRecipientRejected is matched when the mail host permanently rejected the recipient.
+	ErrRecipientRejected = errors.New("recipient rejected")
+
+	// ErrWildcardDNS is matched when the domain resolves any subdomain, see WithWildcardDetection.
+	ErrWildcardDNS = errors.New("domain has wildcard DNS records")
+
+	// ErrTemporaryFailure is matched by temporary DNS failures and temporary replies of the mail
+	// host, the validation may succeed when retried later.
+	ErrTemporaryFailure = errors.New("temporary failure")
+)
+
+var codeSentinels = map[Code]error{
+	CodeNoMX:            ErrNoMXRecords,
+	CodeSMTPUnreachable: ErrSMTPConnection,
+	CodeSMTPFailure:     ErrSMTPConnection,
+	CodeTLSRequired:     ErrSMTPConnection,
+	CodeMailboxRejected: ErrRecipientRejected,
+	CodeSMTPTemporary:   ErrTemporaryFailure,
+	CodeDNSFailure:      ErrTemporaryFailure,
+	CodeDNSTimeout:      ErrTemporaryFailure,
+	CodeWildcardDNS:     ErrWildcardDNS,
+}
+
+var codeReasons = map[Code]string{
+	CodeInvalidFormat:       "invalid-format",
+	CodeInvalidLocalPart:    "invalid-local-part",
+	CodeInvalidDomain:       "invalid-domain",
+	CodeAddressTooLong:      "address-too-long",
+	CodeLocalPartTooLong:    "local-part-too-long",
+	CodeDomainTooLong:       "domain-too-long",
+	CodeLabelTooLong:        "label-too-long",
+	CodeInvalidMailto:       "invalid-mailto",
+	CodeInvalidMailbox:      "invalid-mailbox",
+	CodeDNSFailure:          "dns-failure",
+	CodeDNSTimeout:          "dns-timeout",
+	CodeNoMX:                "no-mx",
+	CodeSMTPUnreachable:     "smtp-unreachable",
+	CodeSMTPRejected:        "smtp-rejected",
+	CodeMailboxRejected:     "mailbox-rejected",
+	CodeSMTPTemporary:       "smtp-temporary",
+	CodeSMTPFailure:         "smtp-failure",
+	CodeTLSRequired:         "tls-required",
+	CodeSMTPUTF8Unsupported: "smtputf8-unsupported",
+	CodeDenied:              "denied",
+	CodeDisposable:          "disposable",
+	CodeNotICANN:            "not-icann",
+	CodeBidiControl:         "bidi-control",
+	CodeInvisibleCharacter:  "invisible-character",
+	CodeMixedScript:         "mixed-script",
+	CodeParked:              "parked",
+	CodeBudgetExhausted:     "budget-exhausted",
+	CodeWildcardDNS:         "wildcard-dns",
+}
+
+// Reason returns the short name of the code, such as no-mx for EA2003.
+func (c Code) Reason() string {
+	return codeReasons[c]
+}
+
+// Error is the error returned when an email address is rejected. Use errors.As or ErrorCode to
+// retrieve its Code, the wrapped error describes the cause in English and can be matched with
+// errors.Is and errors.As as before, ie. against ErrUnverifiable or an *SMTPError.
+type Error struct {
+	Code Code
+	Err  error
+}
+
+func (e *Error) Error() string {
+	if e.Err == nil {
+		return e.Code.Reason()
+	}
+	return e.Err.Error()
+}
+
+// Unwrap returns the cause of the error.
+func (e *Error) Unwrap() error {
+	return e.Err
+}
+
+// Is reports whether target is the sentinel error of the code of e, such as ErrNoMXRecords.
+func (e *Error) Is(target error) bool {
+	if target == ErrInvalidFormat {
+		return strings.HasPrefix(string(e.Code), "EA1")
+	}
+	return target != nil && codeSentinels[e.Code] == target
+}
+
+// ErrorCode returns the Code of the first *Error in the chain of err, or an empty Code.
+func ErrorCode(err error) Code {
+	var e *Error
+	if errors.As(err, &e) {
+		return e.Code
+	}
+	return ""
+}
+
+// newError returns err with the given code.
+func newError(code Code, err error) error {
+	return &Error{Code: code, Err: err}
+}
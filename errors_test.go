@@ -0,0 +1,100 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestErrorCode_Parse(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		opts  []ParseOption
+		want  Code
+	}{
+		{"1", "foo@bar.com", nil, ""},
+		{"2", "foobar.com", nil, CodeInvalidFormat},
+		{"3", "foo bar@bar.com", nil, CodeInvalidLocalPart},
+		{"4", "foo@bar..com", nil, CodeInvalidDomain},
+		{"5", "foo@", nil, CodeInvalidDomain},
+		{"6", strings.Repeat("a", 65) + "@bar.com", []ParseOption{WithHardening()}, CodeLocalPartTooLong},
+		{"7", "foo@" + strings.Repeat("a", 64) + ".com", []ParseOption{WithHardening()}, CodeLabelTooLong},
+		{"8", "foo@" + strings.Repeat("a.", 126) + "com", []ParseOption{WithHardening()}, CodeDomainTooLong},
+		{"9", strings.Repeat("a", 64) + "@" + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 63) + ".com", []ParseOption{WithHardening()}, CodeAddressTooLong},
+		{"10", `"a""b"@bar.com`, []ParseOption{WithHardening()}, CodeInvalidLocalPart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.email, tt.opts...)
+			if got := ErrorCode(err); got != tt.want {
+				t.Errorf("ErrorCode(Parse()) = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+}
+
+func TestCode_Reason(t *testing.T) {
+	tests := []struct {
+		name string
+		code Code
+		want string
+	}{
+		{"1", CodeInvalidLocalPart, "invalid-local-part"},
+		{"2", CodeNoMX, "no-mx"},
+		{"3", Code("EA9999"), ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.code.Reason(); got != tt.want {
+				t.Errorf("Code.Reason() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+	if got := ErrorCode(nil); got != "" {
+		t.Errorf("ErrorCode(nil) = %v, want empty", got)
+	}
+	if got := (&Error{Code: CodeNoMX}).Error(); got != "no-mx" {
+		t.Errorf("Error.Error() = %v, want no-mx", got)
+	}
+}
+
+func TestError_Is(t *testing.T) {
+	_, parseErr := Parse("foo@bar..com")
+	_, tooLong := Parse(strings.Repeat("a", 65) + "@bar.com")
+	tests := []struct {
+		name   string
+		err    error
+		target error
+		want   bool
+	}{
+		{"1", parseErr, ErrInvalidFormat, true},
+		{"2", tooLong, ErrInvalidFormat, true},
+		{"3", tooLong, ErrLocalPartTooLong, true},
+		{"4", newError(CodeNoMX, errors.New("no hosts")), ErrNoMXRecords, true},
+		{"5", newError(CodeSMTPUnreachable, errors.New("refused")), ErrSMTPConnection, true},
+		{"6", newError(CodeSMTPFailure, errors.New("EOF")), ErrSMTPConnection, true},
+		{"7", newError(CodeMailboxRejected, errors.New("550 no such user")), ErrRecipientRejected, true},
+		{"8", newError(CodeSMTPTemporary, errors.New("450 try again")), ErrTemporaryFailure, true},
+		{"9", newError(CodeDNSTimeout, ErrUnverifiable), ErrTemporaryFailure, true},
+		{"10", newError(CodeDNSTimeout, ErrUnverifiable), ErrUnverifiable, true},
+		{"11", fmt.Errorf("validating: %w", newError(CodeNoMX, nil)), ErrNoMXRecords, true},
+		{"12", newError(CodeNoMX, nil), ErrInvalidFormat, false},
+		{"13", newError(CodeMailboxRejected, nil), ErrTemporaryFailure, false},
+		{"14", newError(CodeDenied, ErrDenied), ErrRecipientRejected, false},
+		{"15", parseErr, ErrNoMXRecords, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := errors.Is(tt.err, tt.target); got != tt.want {
+				t.Errorf("errors.Is(%v, %v) = %v, want %v", tt.err, tt.target, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,76 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+)
+
+// ExportFormat is a CSV layout that can be imported by an email service provider.
+type ExportFormat int
+
+const (
+	// SendGridContacts is the contact import format of SendGrid Marketing Campaigns.
+	SendGridContacts ExportFormat = iota
+
+	// MailchimpContacts is the audience import format of Mailchimp.
+	MailchimpContacts
+
+	// SESSuppressionList is the bulk import format of the Amazon SES account-level suppression
+	// list, addresses are suppressed with the BOUNCE reason.
+	SESSuppressionList
+)
+
+// HostResult is the outcome of checking the host of an email address, as returned by CheckHost
+// and ValidateBatch.
+type HostResult struct {
+	Address *EmailAddress
+	Status  HostStatus
+	Err     error
+}
+
+// WriteCSV writes the email addresses to w as CSV in the given format. Nil addresses are skipped.
+func WriteCSV(w io.Writer, format ExportFormat, emails []*EmailAddress) error {
+	cw := csv.NewWriter(w)
+	switch format {
+	case SendGridContacts:
+		cw.Write([]string{"email"}) // #nosec
+	case MailchimpContacts:
+		cw.Write([]string{"Email Address"}) // #nosec
+	case SESSuppressionList:
+	default:
+		return fmt.Errorf("unknown export format %d", format)
+	}
+	for _, e := range emails {
+		if e == nil {
+			continue
+		}
+		record := []string{e.String()}
+		if format == SESSuppressionList {
+			record = append(record, "BOUNCE")
+		}
+		if err := cw.Write(record); err != nil {
+			return err
+		}
+	}
+	cw.Flush()
+	return cw.Error()
+}
+
+// WriteResultsCSV writes the addresses of host check results to w as CSV in the given format.
+// The contact formats contain every address that is not invalid, the suppression list format
+// contains only the invalid addresses. Results without address are skipped.
+func WriteResultsCSV(w io.Writer, format ExportFormat, results []HostResult) error {
+	var emails []*EmailAddress
+	for _, r := range results {
+		if r.Address != nil && (r.Status == HostInvalid) == (format == SESSuppressionList) {
+			emails = append(emails, r.Address)
+		}
+	}
+	return WriteCSV(w, format, emails)
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bytes"
+	"testing"
+)
+
+func TestWriteCSV(t *testing.T) {
+	emails := []*EmailAddress{{"foo", "bar.com"}, nil, {"\"foo,bar\"", "bar.com"}}
+	tests := []struct {
+		name    string
+		format  ExportFormat
+		want    string
+		wantErr bool
+	}{
+		{"1", SendGridContacts, "email\nfoo@bar.com\n\"\"\"foo,bar\"\"@bar.com\"\n", false},
+		{"2", MailchimpContacts, "Email Address\nfoo@bar.com\n\"\"\"foo,bar\"\"@bar.com\"\n", false},
+		{"3", SESSuppressionList, "foo@bar.com,BOUNCE\n\"\"\"foo,bar\"\"@bar.com\",BOUNCE\n", false},
+		{"4", ExportFormat(42), "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteCSV(&buf, tt.format, emails); (err != nil) != tt.wantErr {
+				t.Errorf("WriteCSV() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWriteResultsCSV(t *testing.T) {
+	results := []HostResult{
+		{Address: &EmailAddress{"valid", "bar.com"}, Status: HostVerified},
+		{Address: &EmailAddress{"unknown", "bar.com"}, Status: HostUnverifiable},
+		{Address: &EmailAddress{"invalid", "bar.com"}, Status: HostInvalid},
+		{Status: HostVerified},
+		{Status: HostInvalid},
+	}
+	tests := []struct {
+		name   string
+		format ExportFormat
+		want   string
+	}{
+		{"1", SendGridContacts, "email\nvalid@bar.com\nunknown@bar.com\n"},
+		{"2", MailchimpContacts, "Email Address\nvalid@bar.com\nunknown@bar.com\n"},
+		{"3", SESSuppressionList, "invalid@bar.com,BOUNCE\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var buf bytes.Buffer
+			if err := WriteResultsCSV(&buf, tt.format, results); err != nil {
+				t.Errorf("WriteResultsCSV() error = %v", err)
+				return
+			}
+			if got := buf.String(); got != tt.want {
+				t.Errorf("WriteResultsCSV() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
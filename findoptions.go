@@ -0,0 +1,321 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// defaultFindWorkers is the number of domains whose hosts FindWithOptions validates at the same
+// time.
+const defaultFindWorkers = 8
+
+// FindOption configures FindWithOptions.
+type FindOption func(*findOptions)
+
+type findOptions struct {
+	ctx          context.Context
+	parse        parseOptions
+	re           *regexp.Regexp
+	policy       SuffixPolicy
+	validateHost bool
+	dedupe       bool
+	equal        equalOptions
+	normalize    *NormalizeOptions
+	maxResults   int
+	filters      []func(*EmailAddress) bool
+	workers      int
+}
+
+// WithParseOptions parses the addresses found with opts, ie. WithHardening or
+// WithoutRoleAccounts.
+func WithParseOptions(opts ...ParseOption) FindOption {
+	return func(o *findOptions) {
+		for _, opt := range opts {
+			opt(&o.parse)
+		}
+	}
+}
+
+// WithRFC5322 matches addresses with the RFC 5322 regular expression of FindWithRFC5322 instead of
+// the stricter one of Find.
+func WithRFC5322() FindOption {
+	return func(o *findOptions) {
+		o.re = findRfc5322Regexp
+	}
+}
+
+// WithSuffixPolicy only returns the addresses whose domain is accepted by policy, like
+// FindWithSuffix.
+func WithSuffixPolicy(policy SuffixPolicy) FindOption {
+	return func(o *findOptions) {
+		o.policy = policy
+	}
+}
+
+// WithICANNSuffix only returns the addresses with a public suffix managed by ICANN, like
+// FindWithIcannSuffix.
+func WithICANNSuffix() FindOption {
+	return WithSuffixPolicy(ICANNSuffixes)
+}
+
+// WithHostValidation only returns the addresses whose host is validated by ValidateHost, or by
+// the Verifier of WithVerifier. Hosts are validated after every other option, so addresses that
+// are filtered out don't cause network traffic.
+func WithHostValidation() FindOption {
+	return func(o *findOptions) {
+		o.validateHost = true
+	}
+}
+
+// WithValidationWorkers validates the hosts of n domains at the same time with
+// WithHostValidation, defaults to 8.
+func WithValidationWorkers(n int) FindOption {
+	return func(o *findOptions) {
+		o.workers = n
+	}
+}
+
+// WithDedupe returns an address once, at its first occurrence, comparing addresses like Equal with
+// opts.
+func WithDedupe(opts ...EqualOption) FindOption {
+	return func(o *findOptions) {
+		o.dedupe = true
+		for _, opt := range opts {
+			opt(&o.equal)
+		}
+	}
+}
+
+// WithNormalizedResults returns the addresses normalized with opts, see Normalize. The other
+// options see the normalized addresses.
+func WithNormalizedResults(opts NormalizeOptions) FindOption {
+	return func(o *findOptions) {
+		o.normalize = &opts
+	}
+}
+
+// WithMaxResults stops after n addresses are found. With WithHostValidation the validations stop
+// once n addresses are valid, which are returned in order but may not be the first n valid
+// addresses of haystack, since domains are validated concurrently.
+func WithMaxResults(n int) FindOption {
+	return func(o *findOptions) {
+		o.maxResults = n
+	}
+}
+
+// WithContext stops the search and the host validations in flight when ctx is done, the
+// addresses found until then are returned. The host validations use ctx, so its deadline bounds
+// them. Check ctx.Err() to tell whether the search completed.
+func WithContext(ctx context.Context) FindOption {
+	return func(o *findOptions) {
+		o.ctx = ctx
+	}
+}
+
+// WithFilter only returns the addresses for which keep returns true. Filters are applied in the
+// order they are given.
+func WithFilter(keep func(*EmailAddress) bool) FindOption {
+	return func(o *findOptions) {
+		o.filters = append(o.filters, keep)
+	}
+}
+
+// FindWithOptions finds the email addresses in haystack like Find, configured by opts instead of
+// the parameters of the Find variants. Every address goes through the options in a fixed order:
+// parsing, normalization, the suffix policy, the filters, deduplication and host validation.
+//
+// With WithHostValidation the whole haystack is searched first and the hosts of the addresses are
+// then validated concurrently, see WithValidationWorkers. The addresses of a domain are validated
+// in order by a single worker, which drops the remaining ones once an error shows the domain can't
+// receive mail. The addresses are returned in the order they appear in.
+//
+//	emails := emailaddress.FindWithOptions(text,
+//		emailaddress.WithICANNSuffix(),
+//		emailaddress.WithDedupe(emailaddress.WithCaseInsensitiveLocalPart()),
+//		emailaddress.WithMaxResults(100),
+//	)
+func FindWithOptions(haystack []byte, opts ...FindOption) []*EmailAddress {
+	o := newFindOptions(opts)
+	if !o.validateHost {
+		return o.find(haystack, o.maxResults)
+	}
+	return o.validateHosts(o.find(haystack, 0))
+}
+
+// find returns the addresses in haystack that pass every option but host validation, at most
+// limit of them if limit is positive.
+func (o findOptions) find(haystack []byte, limit int) (emails []*EmailAddress) {
+	o.each(haystack, func(e *EmailAddress) bool {
+		emails = append(emails, e)
+		return limit <= 0 || len(emails) < limit
+	})
+	return emails
+}
+
+// each calls fn with the addresses in haystack that pass every option but host validation, until
+// fn returns false.
+func (o findOptions) each(haystack []byte, fn func(*EmailAddress) bool) {
+	var seen map[EmailAddress]bool
+	if o.dedupe {
+		seen = make(map[EmailAddress]bool)
+	}
+	found := 0
+	findAll(o.re, o.parse.prepare(haystack), func(r []byte) bool {
+		if o.ctx.Err() != nil {
+			return false
+		}
+		if o.parse.lengthLimits() && len(r) > maxAddressLength {
+			return true
+		}
+		e, err := o.parse.parse(string(r))
+		if err != nil || o.parse.skip(e) {
+			return true
+		}
+		// The hardened limit bounds the work on untrusted input, so every address counts.
+		found++
+		more := !o.parse.hardened || found < maxHardenedResults
+		if o.normalize != nil {
+			n := e.Normalize(*o.normalize)
+			e = &n
+		}
+		if !o.keep(e, seen) {
+			return more
+		}
+		return fn(e) && more
+	})
+}
+
+func newFindOptions(opts []FindOption) findOptions {
+	o := findOptions{ctx: context.Background(), re: findCommonRegexp, workers: defaultFindWorkers}
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+// keep reports whether e passes the suffix policy, the filters and deduplication. seen holds the
+// keys of the addresses seen before when deduplicating.
+func (o findOptions) keep(e *EmailAddress, seen map[EmailAddress]bool) bool {
+	if o.policy != nil && !o.policy(strings.ToLower(e.Domain)) {
+		return false
+	}
+	for _, keep := range o.filters {
+		if !keep(e) {
+			return false
+		}
+	}
+	if seen != nil {
+		key := o.equal.key(*e)
+		if seen[key] {
+			return false
+		}
+		seen[key] = true
+	}
+	return true
+}
+
+// validateHosts returns the addresses of emails whose host is valid, in order, at most
+// maxResults of them if it is positive. The domains are validated by a pool of workers, each
+// validating the addresses of a domain in order, until the context is done or enough addresses
+// are valid.
+func (o findOptions) validateHosts(emails []*EmailAddress) []*EmailAddress {
+	var domains [][]int
+	index := make(map[string]int)
+	for i, e := range emails {
+		d := strings.ToLower(e.Domain)
+		j, ok := index[d]
+		if !ok {
+			j = len(domains)
+			index[d] = j
+			domains = append(domains, nil)
+		}
+		domains[j] = append(domains[j], i)
+	}
+
+	workers := o.workers
+	if workers > len(domains) {
+		workers = len(domains)
+	}
+	if workers < 1 {
+		workers = 1
+	}
+	ctx, cancel := context.WithCancel(o.ctx)
+	defer cancel()
+	var (
+		mu    sync.Mutex
+		count int
+		wg    sync.WaitGroup
+	)
+	valid := make([]bool, len(emails))
+	work := make(chan []int)
+	for w := 0; w < workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for group := range work {
+				for _, i := range group {
+					if ctx.Err() != nil {
+						break
+					}
+					err := o.parse.validateHost(ctx, emails[i])
+					if err == nil && ctx.Err() == nil {
+						mu.Lock()
+						valid[i], count = true, count+1
+						if o.maxResults > 0 && count >= o.maxResults {
+							cancel()
+						}
+						mu.Unlock()
+					}
+					if err != nil && domainError(err) {
+						break
+					}
+				}
+			}
+		}()
+	}
+dispatch:
+	for _, group := range domains {
+		select {
+		case work <- group:
+		case <-ctx.Done():
+			break dispatch
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	var result []*EmailAddress
+	for i, e := range emails {
+		if valid[i] && (o.maxResults <= 0 || len(result) < o.maxResults) {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// domainError reports whether err applies to every address of the domain, such as a domain
+// without mail host, rather than to a single mailbox.
+func domainError(err error) bool {
+	switch ErrorCode(err) {
+	case CodeDNSFailure, CodeDNSTimeout, CodeNoMX, CodeSMTPUnreachable, CodeDenied, CodeDisposable,
+		CodeParked, CodeWildcardDNS, CodeBudgetExhausted:
+		return true
+	}
+	return false
+}
+
+// findOptionsOf returns the options of the Find variants taking a validateHost parameter.
+func findOptionsOf(validateHost bool, opts []ParseOption, more ...FindOption) []FindOption {
+	fo := append([]FindOption{WithParseOptions(opts...)}, more...)
+	if validateHost {
+		fo = append(fo, WithHostValidation())
+	}
+	return fo
+}
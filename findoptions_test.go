@@ -0,0 +1,187 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestFindWithOptions(t *testing.T) {
+	text := []byte("Info@Example.com, info@example.com, jane@foo.blogspot.com, bar@foo.fakesuffix, " +
+		"admin@example.org and o'brien!x@example.ie")
+
+	tests := []struct {
+		name string
+		opts []FindOption
+		want []string
+	}{
+		{
+			name: "1",
+			opts: nil,
+			want: []string{"Info@Example.com", "info@example.com", "jane@foo.blogspot.com", "bar@foo.fakesuffix", "admin@example.org", "x@example.ie"},
+		},
+		{
+			name: "2",
+			opts: []FindOption{WithICANNSuffix()},
+			want: []string{"Info@Example.com", "info@example.com", "admin@example.org", "x@example.ie"},
+		},
+		{
+			name: "3",
+			opts: []FindOption{WithDedupe(WithCaseInsensitiveLocalPart())},
+			want: []string{"Info@Example.com", "jane@foo.blogspot.com", "bar@foo.fakesuffix", "admin@example.org", "x@example.ie"},
+		},
+		{
+			name: "4",
+			opts: []FindOption{WithMaxResults(2)},
+			want: []string{"Info@Example.com", "info@example.com"},
+		},
+		{
+			name: "5",
+			opts: []FindOption{WithNormalizedResults(NormalizeOptions{FoldLocalPart: true}), WithDedupe()},
+			want: []string{"info@example.com", "jane@foo.blogspot.com", "bar@foo.fakesuffix", "admin@example.org", "x@example.ie"},
+		},
+		{
+			name: "6",
+			opts: []FindOption{WithFilter(func(e *EmailAddress) bool { return strings.HasSuffix(e.Domain, ".org") })},
+			want: []string{"admin@example.org"},
+		},
+		{
+			name: "7",
+			opts: []FindOption{WithParseOptions(WithoutRoleAccounts()), WithSuffixPolicy(PublicSuffixes)},
+			want: []string{"jane@foo.blogspot.com", "x@example.ie"},
+		},
+		{
+			name: "8",
+			opts: []FindOption{WithRFC5322(), WithFilter(func(e *EmailAddress) bool { return e.Domain == "example.ie" })},
+			want: []string{"o'brien!x@example.ie"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range FindWithOptions(text, tt.opts...) {
+				got = append(got, e.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindWithOptions() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// testHostValidator validates the hosts of addresses by their local part, with a delay, and
+// records the validations.
+type testHostValidator struct {
+	mu       sync.Mutex
+	calls    []string
+	inFlight int
+	max      int
+}
+
+func (v *testHostValidator) validateHost(ctx context.Context, e EmailAddress) error {
+	v.mu.Lock()
+	v.calls = append(v.calls, e.String())
+	v.inFlight++
+	if v.inFlight > v.max {
+		v.max = v.inFlight
+	}
+	v.mu.Unlock()
+	time.Sleep(10 * time.Millisecond)
+	v.mu.Lock()
+	v.inFlight--
+	v.mu.Unlock()
+
+	switch e.LocalPart {
+	case "dead":
+		return newError(CodeNoMX, fmt.Errorf("no mail host for %s", e.Domain))
+	case "nobody":
+		return newError(CodeMailboxRejected, fmt.Errorf("no mailbox %s", e))
+	}
+	return nil
+}
+
+func TestFindWithOptions_validateHosts(t *testing.T) {
+	text := []byte("a@one.com nobody@one.com dead@two.com b@two.com c@three.com d@four.com e@one.com")
+
+	tests := []struct {
+		name    string
+		workers int
+		max     int
+		want    []string
+	}{
+		{name: "1", workers: 4, want: []string{"a@one.com", "c@three.com", "d@four.com", "e@one.com"}},
+		{name: "2", workers: 1, want: []string{"a@one.com", "c@three.com", "d@four.com", "e@one.com"}},
+		{name: "3", workers: 1, max: 2, want: []string{"a@one.com", "e@one.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &testHostValidator{}
+			emails := FindWithOptions(text,
+				WithParseOptions(func(o *parseOptions) { o.hostValidator = v }),
+				WithHostValidation(),
+				WithValidationWorkers(tt.workers),
+				WithMaxResults(tt.max),
+			)
+			var got []string
+			for _, e := range emails {
+				got = append(got, e.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindWithOptions() = %q, want %q", got, tt.want)
+			}
+			for _, call := range v.calls {
+				if call == "b@two.com" {
+					t.Errorf("validated b@two.com after its domain failed")
+				}
+			}
+			if tt.max > 0 && len(v.calls) != 3 {
+				t.Errorf("validated %q, want to stop after %d valid addresses", v.calls, tt.max)
+			}
+			if v.max > tt.workers {
+				t.Errorf("%d validations at the same time, want at most %d", v.max, tt.workers)
+			}
+			if tt.workers > 1 && v.max < 2 {
+				t.Errorf("%d validations at the same time, want concurrent validations", v.max)
+			}
+		})
+	}
+}
+
+func TestFindWithOptions_context(t *testing.T) {
+	text := []byte("a@one.com b@two.com c@three.com")
+	cancelled, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	tests := []struct {
+		name string
+		ctx  context.Context
+		opts []FindOption
+		want int
+	}{
+		{name: "1", ctx: context.Background(), want: 3},
+		{name: "2", ctx: cancelled, want: 0},
+		{name: "3", ctx: cancelled, opts: []FindOption{WithHostValidation()}, want: 0},
+		{name: "4", ctx: context.Background(), opts: []FindOption{WithMaxResults(1)}, want: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &testHostValidator{}
+			opts := append([]FindOption{WithContext(tt.ctx), WithParseOptions(func(o *parseOptions) { o.hostValidator = v })}, tt.opts...)
+			if got := FindWithOptions(text, opts...); len(got) != tt.want {
+				t.Errorf("FindWithOptions() = %v, want %d addresses", got, tt.want)
+			}
+			if tt.ctx.Err() != nil && len(v.calls) != 0 {
+				t.Errorf("validated %q after the context was done", v.calls)
+			}
+		})
+	}
+}
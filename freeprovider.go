@@ -0,0 +1,49 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	_ "embed" // for the free providers
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+//go:embed free_providers.txt
+var freeProviderDomains string
+
+var (
+	freeProvidersOnce sync.Once
+	freeProviders     atomic.Value // of domainListValue
+)
+
+// domainListValue holds a DomainList in an atomic.Value, which only stores values of one type.
+type domainListValue struct {
+	list DomainList
+}
+
+// FreeProviders returns the list of free mail providers used by IsFreeProvider and Analyze. It is
+// the list embedded in the package until SetFreeProviders is called.
+func FreeProviders() DomainList {
+	freeProvidersOnce.Do(func() {
+		freeProviders.Store(domainListValue{NewDomainList(domainLines(freeProviderDomains))})
+	})
+	return freeProviders.Load().(domainListValue).list
+}
+
+// SetFreeProviders replaces the list of free mail providers, ie. with one that is updated
+// remotely. It is safe to call while other goroutines check addresses.
+func SetFreeProviders(list DomainList) {
+	FreeProviders() // so the embedded list never replaces list
+	freeProviders.Store(domainListValue{list})
+}
+
+// IsFreeProvider reports whether the domain of the email address, or one of its parent domains,
+// belongs to a free mail provider such as gmail.com, so personal addresses can be told apart from
+// corporate ones. See FreeProviders.
+func (e EmailAddress) IsFreeProvider() bool {
+	return listed(FreeProviders(), strings.TrimSuffix(strings.ToLower(asciiDomain(e.Domain)), "."))
+}
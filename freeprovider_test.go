@@ -0,0 +1,47 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"testing"
+)
+
+func TestEmailAddress_IsFreeProvider(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want bool
+	}{
+		{"1", EmailAddress{"foo", "gmail.com"}, true},
+		{"2", EmailAddress{"foo", "Outlook.com"}, true},
+		{"3", EmailAddress{"foo", "yahoo.co.uk"}, true},
+		{"4", EmailAddress{"foo", "yahoo.com."}, true},
+		{"5", EmailAddress{"foo", "example.com"}, false},
+		{"6", EmailAddress{"foo", "gmail.example.com"}, false},
+		{"7", EmailAddress{"foo", "[192.0.2.1]"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsFreeProvider(); got != tt.want {
+				t.Errorf("EmailAddress.IsFreeProvider() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetFreeProviders(t *testing.T) {
+	defer SetFreeProviders(FreeProviders())
+	SetFreeProviders(NewDomainList([]string{"corp.example"}))
+	if !(EmailAddress{"foo", "mail.corp.example"}).IsFreeProvider() {
+		t.Errorf("EmailAddress.IsFreeProvider() = false, want true for the new list")
+	}
+	if (EmailAddress{"foo", "gmail.com"}).IsFreeProvider() {
+		t.Errorf("EmailAddress.IsFreeProvider() = true, want false for a domain of the old list")
+	}
+	if got := Analyze([]*EmailAddress{{"foo", "corp.example"}, {"foo", "gmail.com"}}, AnalyzeOptions{}).Free; got != 0.5 {
+		t.Errorf("Analyze().Free = %v, want 0.5 with the new list", got)
+	}
+}
@@ -1,5 +1,8 @@
 module github.com/mcnijman/go-emailaddress
 
-go 1.15
+go 1.18
 
-require golang.org/x/net v0.0.0-20180911220305-26e67e76b6c3
+require (
+	golang.org/x/net v0.0.0-20220722155237-a158d28d115b
+	golang.org/x/text v0.3.8
+)
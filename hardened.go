@@ -0,0 +1,81 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+const (
+	// maxHardenedEscapes is the maximum number of quoted-pairs in a hardened local part.
+	maxHardenedEscapes = 8
+
+	// maxHardenedResults is the maximum number of email addresses the Find functions return in
+	// hardened mode.
+	maxHardenedResults = 1000
+)
+
+// WithHardening prepares Parse and the Find functions for untrusted input. Addresses must meet the
+// length limits of RFC 5321 and RFC 1035, also with WithoutLengthLimits, a local part may contain
+// a single quoted string with at most 8 escaped characters, and the Find functions return at most
+// 1000 addresses. Parse checks the limits in a single pass before parsing the address. The Find
+// functions search their input with a regular expression first, then skip candidates longer than
+// the limit without allocating and check the others like Parse, so the work and memory spent on
+// crafted input such as long runs of quotes, escapes or repeated addresses stays bounded.
+func WithHardening() ParseOption {
+	return func(o *parseOptions) {
+		o.hardened = true
+	}
+}
+
+// checkHardened validates the structure of email against the hardened limits.
+func checkHardened(email string) error {
+	i := strings.LastIndexByte(email, '@')
+	if i < 0 {
+		return formatError(email)
+	}
+	if err := checkLength(email); err != nil {
+		return err
+	}
+
+	local := email[:i]
+	quotes, escapes := 0, 0
+	for j := 0; j < len(local); j++ {
+		switch local[j] {
+		case '"':
+			quotes++
+			if quotes > 2 {
+				return newError(CodeInvalidLocalPart, fmt.Errorf("local part contains more than one quoted string"))
+			}
+		case '\\':
+			if quotes != 1 {
+				return newError(CodeInvalidLocalPart, fmt.Errorf("local part contains an escape outside a quoted string"))
+			}
+			if escapes++; escapes > maxHardenedEscapes {
+				return newError(CodeInvalidLocalPart, fmt.Errorf("local part contains more than %d escaped characters", maxHardenedEscapes))
+			}
+			j++
+		}
+	}
+	if quotes == 1 {
+		return newError(CodeInvalidLocalPart, fmt.Errorf("local part contains an unterminated quoted string"))
+	}
+	return nil
+}
+
+// findAll calls fn for every match of re in b, in order, until fn returns false. Unlike
+// re.FindAll it doesn't collect the matches up front.
+func findAll(re *regexp.Regexp, b []byte, fn func(match []byte) bool) {
+	for len(b) > 0 {
+		loc := re.FindIndex(b)
+		if loc == nil || !fn(b[loc[0]:loc[1]]) {
+			return
+		}
+		b = b[loc[1]:]
+	}
+}
@@ -0,0 +1,104 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestParse_hardened(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		want    *EmailAddress
+		wantErr bool
+	}{
+		{"1", "foo@bar.com", &EmailAddress{"foo", "bar.com"}, false},
+		{"2", `"foo@bar"@bar.com`, &EmailAddress{`"foo@bar"`, "bar.com"}, false},
+		{"3", `"foo\"bar"@bar.com`, &EmailAddress{`"foo\"bar"`, "bar.com"}, false},
+		{"4", "foo@[192.0.2.1]", &EmailAddress{"foo", "[192.0.2.1]"}, false},
+		{"5", strings.Repeat("a", 64) + "@bar.com", &EmailAddress{strings.Repeat("a", 64), "bar.com"}, false},
+		{"6", strings.Repeat("a", 65) + "@bar.com", nil, true},
+		{"7", "foo@" + strings.Repeat("a", 64) + ".com", nil, true},
+		{"8", "foo@" + strings.Repeat("a.", 125) + "com", nil, true},
+		{"9", `"` + strings.Repeat(`\"`, 9) + `"@bar.com`, nil, true},
+		{"10", `"a""b"@bar.com`, nil, true},
+		{"11", `"foo@bar.com`, nil, true},
+		{"12", "foobar.com", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.email, WithHardening())
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFind_hardened(t *testing.T) {
+	haystack := bytes.Repeat([]byte("foo@bar.com "), 2*maxHardenedResults)
+	if got := len(Find(haystack, false, WithHardening())); got != maxHardenedResults {
+		t.Errorf("len(Find()) = %d, want %d", got, maxHardenedResults)
+	}
+	if got := len(FindWithRFC5322(haystack, false, WithHardening())); got != maxHardenedResults {
+		t.Errorf("len(FindWithRFC5322()) = %d, want %d", got, maxHardenedResults)
+	}
+	if got := len(Find(haystack, false)); got != 2*maxHardenedResults {
+		t.Errorf("len(Find()) without hardening = %d, want %d", got, 2*maxHardenedResults)
+	}
+
+	long := []byte(strings.Repeat("a", 300) + "@bar.com and foo@bar.com")
+	want := []*EmailAddress{{"foo", "bar.com"}}
+	if got := Find(long, false, WithHardening()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+}
+
+func FuzzParse(f *testing.F) {
+	for _, seed := range []string{"foo@bar.com", `"foo\"bar"@bar.com`, "foo@[192.0.2.1]", "foo@[IPv6:2001:db8::1]", "a@b"} {
+		f.Add(seed)
+	}
+	f.Fuzz(func(t *testing.T, email string) {
+		e, err := Parse(email, WithHardening())
+		if err != nil {
+			return
+		}
+		if e.String() != email {
+			t.Errorf("Parse(%q).String() = %q", email, e.String())
+		}
+		if len(e.LocalPart) > maxLocalPartLength || len(e.Domain) > maxDomainLength {
+			t.Errorf("Parse(%q) exceeds the length limits", email)
+		}
+		if _, err := Parse(email); err != nil {
+			t.Errorf("Parse(%q) without hardening error = %v", email, err)
+		}
+	})
+}
+
+func FuzzFind(f *testing.F) {
+	for _, seed := range []string{"Send me an email at foo@bar.com.", `<a href="mailto:foo@bar.com">"a"@b.co</a>`, "foo&#64;bar.com"} {
+		f.Add([]byte(seed))
+	}
+	f.Fuzz(func(t *testing.T, haystack []byte) {
+		emails := FindWithRFC5322(haystack, false, WithHardening(), WithHTMLEntityDecoding())
+		if len(emails) > maxHardenedResults {
+			t.Errorf("FindWithRFC5322() returned %d addresses", len(emails))
+		}
+		for _, e := range emails {
+			if _, err := Parse(e.String(), WithHardening()); err != nil {
+				t.Errorf("FindWithRFC5322() returned %v, which doesn't parse: %v", e, err)
+			}
+		}
+	})
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import "context"
+
+// ValidateHost will test if the email address is actually reachable. It will first try to resolve
+// the host and then start a mail transaction.
+func (e EmailAddress) ValidateHost() error {
+	return e.ValidateHostContext(context.Background())
+}
+
+// ValidateHostContext is like ValidateHost, but the DNS lookups and the SMTP conversation are
+// aborted when ctx is cancelled or its deadline passes, in which case the error of ctx is returned.
+func (e EmailAddress) ValidateHostContext(ctx context.Context) error {
+	return defaultVerifier.ValidateHost(ctx, e)
+}
+
+// ValidateHostWithOptions is like ValidateHost, but uses a Verifier configured with opts, ie. to
+// probe from a sender address of your own domain:
+//
+//	err := e.ValidateHostWithOptions(
+//		emailaddress.WithPort(25),
+//		emailaddress.WithHeloDomain("mail.example.com"),
+//		emailaddress.WithSenderAddress("verify@example.com"),
+//		emailaddress.WithCommandTimeout(10*time.Second),
+//	)
+func (e EmailAddress) ValidateHostWithOptions(opts ...Option) error {
+	return NewVerifier(opts...).ValidateHost(context.Background(), e)
+}
+
+// CheckHost will test if the email address is actually reachable, like ValidateHost, but
+// distinguishes addresses that are definitively invalid from addresses that could not be
+// verified. See Verifier.CheckHost.
+func (e EmailAddress) CheckHost() (HostStatus, error) {
+	return defaultVerifier.CheckHost(context.Background(), e)
+}
+
+// LookupHost first checks if any MX records are available and if not, it will check
+// if A records are available as they can resolve email server hosts. An error indicates
+// that non of the A or MX records are available.
+func LookupHost(domain string) (string, error) {
+	return defaultVerifier.lookupHost(context.Background(), domain)
+}
+
+// LookupMXRecords returns every mail host of domain with its preference, in order of preference.
+// See Verifier.LookupMXRecords.
+func LookupMXRecords(domain string) ([]MXRecord, error) {
+	return defaultVerifier.LookupMXRecords(context.Background(), domain)
+}
+
+// CheckSPF looks up and parses the SPF record of domain. See Verifier.CheckSPF.
+func CheckSPF(domain string) (*SPFRecord, error) {
+	return defaultVerifier.CheckSPF(context.Background(), domain)
+}
+
+// CheckDMARC looks up and parses the DMARC record of domain. See Verifier.CheckDMARC.
+func CheckDMARC(domain string) (*DMARCRecord, error) {
+	return defaultVerifier.CheckDMARC(context.Background(), domain)
+}
+
+// CheckMTASTS looks up and fetches the MTA-STS policy of domain. See Verifier.CheckMTASTS.
+func CheckMTASTS(domain string) (*MTASTSPolicy, error) {
+	return defaultVerifier.CheckMTASTS(context.Background(), domain)
+}
+
+// CheckTLSRPT looks up and parses the SMTP TLS reporting record of domain. See
+// Verifier.CheckTLSRPT.
+func CheckTLSRPT(domain string) (*TLSRPTRecord, error) {
+	return defaultVerifier.CheckTLSRPT(context.Background(), domain)
+}
+
+// IdentifyProvider looks up the mail hosts of domain and returns the provider hosting its
+// mailboxes, with the mail hosts. See Verifier.IdentifyProvider.
+func IdentifyProvider(domain string) (Provider, []string, error) {
+	return defaultVerifier.IdentifyProvider(context.Background(), domain)
+}
+
+// TryHost will verify if we can start a mail transaction with the host. A lot of
+// hosts block this method so don't expect much from it.
+func TryHost(host string, e EmailAddress) error {
+	return TryHostContext(context.Background(), host, e)
+}
+
+// TryHostContext is like TryHost, but the SMTP conversation is aborted when ctx is cancelled or its
+// deadline passes, in which case the error of ctx is returned.
+func TryHostContext(ctx context.Context, host string, e EmailAddress) error {
+	return defaultVerifier.TryHost(ctx, host, e)
+}
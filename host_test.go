@@ -0,0 +1,125 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/textproto"
+	"testing"
+)
+
+func TestErrorCode_policy(t *testing.T) {
+	v := NewVerifier(WithDenyList(NewDomainList([]string{"spam.com"})), WithDisposableList(NewDomainList([]string{"mailinator.com"})))
+	_, _, mailtoErr := ParseMailto("http://bar.com")
+	tests := []struct {
+		name string
+		err  error
+		want Code
+	}{
+		{"1", EmailAddress{"foo", "bar.foobar"}.ValidateIcanSuffix(), CodeNotICANN},
+		{"2", EmailAddress{"foo", "b\u202eac.com"}.ValidateSecurity(), CodeBidiControl},
+		{"3", EmailAddress{"foo", "b\u200bar.com"}.ValidateSecurity(), CodeInvisibleCharacter},
+		{"4", EmailAddress{"foo", "p\u0430ypal.com"}.ValidateSecurity(), CodeMixedScript},
+		{"5", v.ValidateHost(context.Background(), EmailAddress{"foo", "spam.com"}), CodeDenied},
+		{"6", v.ValidateHost(context.Background(), EmailAddress{"foo", "mailinator.com"}), CodeDisposable},
+		{"7", mailtoErr, CodeInvalidMailto},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(tt.err); got != tt.want {
+				t.Errorf("ErrorCode() = %v, want %v (%v)", got, tt.want, tt.err)
+			}
+		})
+	}
+	if err := v.ValidateHost(context.Background(), EmailAddress{"foo", "spam.com"}); !errors.Is(err, ErrDenied) {
+		t.Errorf("Verifier.ValidateHost() error = %v, want %v", err, ErrDenied)
+	}
+}
+
+func TestErrorCode_host(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.mail = func(addr string) string {
+		if addr == "hello@blocked.example.com" {
+			return "554 sender rejected"
+		}
+		return "250 OK"
+	}
+	s.rcpt = func(addr string) string {
+		switch addr {
+		case "fake@example.com":
+			return "550 no such user"
+		case "greylisted@example.com":
+			return "450 try again later"
+		}
+		return "250 OK"
+	}
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed.Close()
+
+	host := []*net.MX{{Host: s.host(), Pref: 10}}
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": host, "blocked.example.com": host}}
+	servfail := &testResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}
+	timeout := &testResolver{err: &net.DNSError{Err: "i/o timeout", IsTimeout: true}}
+	tests := []struct {
+		name     string
+		e        EmailAddress
+		resolver resolver
+		port     int
+		want     Code
+	}{
+		{"1", EmailAddress{"info", "example.com"}, r, s.port(), ""},
+		{"2", EmailAddress{"fake", "example.com"}, r, s.port(), CodeMailboxRejected},
+		{"3", EmailAddress{"greylisted", "example.com"}, r, s.port(), CodeSMTPTemporary},
+		{"4", EmailAddress{"info", "blocked.example.com"}, r, s.port(), CodeSMTPRejected},
+		{"5", EmailAddress{"info", "example.org"}, r, s.port(), CodeNoMX},
+		{"6", EmailAddress{"info", "example.com"}, servfail, s.port(), CodeDNSFailure},
+		{"7", EmailAddress{"info", "example.com"}, timeout, s.port(), CodeDNSTimeout},
+		{"8", EmailAddress{"info", "example.com"}, r, closed.Addr().(*net.TCPAddr).Port, CodeSMTPUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier()
+			v.resolver = tt.resolver
+			v.port = tt.port
+			_, err := v.CheckHost(context.Background(), tt.e)
+			if got := ErrorCode(err); got != tt.want {
+				t.Errorf("ErrorCode(Verifier.CheckHost()) = %v, want %v (%v)", got, tt.want, err)
+			}
+		})
+	}
+
+	v := NewVerifier()
+	v.resolver = r
+	v.port = s.port()
+	err := v.ValidateHost(context.Background(), EmailAddress{"fake", "example.com"})
+	var tpErr *textproto.Error
+	if !errors.As(err, &tpErr) || tpErr.Code != 550 || err.Error() != tpErr.Error() {
+		t.Errorf("Verifier.ValidateHost() error = %v, want the *textproto.Error of the host", err)
+	}
+	var smtpErr *SMTPError
+	if !errors.As(err, &smtpErr) || !smtpErr.Permanent() {
+		t.Errorf("Verifier.ValidateHost() error = %v, want a permanent *SMTPError", err)
+	}
+	err = v.ValidateHost(context.Background(), EmailAddress{"greylisted", "example.com"})
+	if !errors.As(err, &smtpErr) || !smtpErr.Temporary() || smtpErr.Code != 450 {
+		t.Errorf("Verifier.ValidateHost() error = %v, want a temporary *SMTPError", err)
+	}
+}
+
+func TestEmailAddress_ValidateHostContext(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := (EmailAddress{"foo", "gmail.com"}).ValidateHostContext(ctx); err != context.Canceled {
+		t.Errorf("EmailAddress.ValidateHostContext() error = %v, want %v", err, context.Canceled)
+	}
+	if err := TryHostContext(ctx, "127.0.0.1", EmailAddress{"foo", "gmail.com"}); err != context.Canceled {
+		t.Errorf("TryHostContext() error = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,79 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bytes"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// FindInHTML finds email addresses in an HTML document like FindWithOptions with opts, but
+// searches its text and the targets of its mailto: links instead of the markup. HTML entities such
+// as &#64; are decoded, text nodes are searched separately so addresses don't run into adjacent
+// markup, the contents of script and style elements are skipped and the recipients of a mailto:
+// link, its to, cc and bcc fields included, are found without its subject or body.
+func FindInHTML(haystack []byte, opts ...FindOption) []*EmailAddress {
+	return FindWithOptions(htmlText(haystack), opts...)
+}
+
+// htmlText returns the text of the document in b, one text node or mailto: recipient per line.
+func htmlText(b []byte) []byte {
+	var text bytes.Buffer
+	z := html.NewTokenizer(bytes.NewReader(b))
+	skip := false
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return text.Bytes()
+		case html.TextToken:
+			if !skip {
+				text.Write(z.Text())
+				text.WriteByte('\n')
+			}
+		case html.StartTagToken, html.SelfClosingTagToken:
+			tn, hasAttr := z.TagName()
+			a := atom.Lookup(tn)
+			skip = a == atom.Script || a == atom.Style
+			for hasAttr {
+				var key, val []byte
+				key, val, hasAttr = z.TagAttr()
+				if string(key) == "href" {
+					writeMailto(&text, string(val))
+				}
+			}
+		case html.EndTagToken:
+			skip = false
+		}
+	}
+}
+
+// writeMailto writes the recipients of a mailto: URI to text, one per line. When the URI can't
+// be parsed, its address part is written as is.
+func writeMailto(text *bytes.Buffer, uri string) {
+	uri = strings.TrimSpace(uri)
+	if len(uri) < 7 || !strings.EqualFold(uri[:7], "mailto:") {
+		return
+	}
+	to, opts, err := ParseMailto(uri)
+	if err != nil {
+		addrs := uri[7:]
+		if i := strings.IndexAny(addrs, "?#"); i >= 0 {
+			addrs = addrs[:i]
+		}
+		text.WriteString(strings.Replace(addrs, ",", "\n", -1))
+		text.WriteByte('\n')
+		return
+	}
+	for _, list := range [][]*EmailAddress{to, opts.Cc, opts.Bcc} {
+		for _, e := range list {
+			text.WriteString(e.String())
+			text.WriteByte('\n')
+		}
+	}
+}
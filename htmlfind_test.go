@@ -0,0 +1,40 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindInHTML(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		opts []FindOption
+		want []string
+	}{
+		{"1", `<a href="mailto:jane@example.com?subject=Hello%20there">Mail me</a>`, nil, []string{"jane@example.com"}},
+		{"2", `<p>foo&#64;bar.com, bar&commat;baz.com</p>`, nil, []string{"foo@bar.com", "bar@baz.com"}},
+		{"3", `<td>foo@bar.com</td><td>Next</td>`, nil, []string{"foo@bar.com"}},
+		{"4", `<script>var x = "spam@evil.com";</script><style>a{}</style><p>me@example.com</p>`, nil, []string{"me@example.com"}},
+		{"5", `<a href="mailto:a@one.com,b@two.com?cc=c@three.com&bcc=d@four.com&body=hi%20e@five.com">`, nil, []string{"a@one.com", "b@two.com", "c@three.com", "d@four.com"}},
+		{"6", `<a href="mailto:jane@example.com">jane@example.com</a>`, []FindOption{WithDedupe()}, []string{"jane@example.com"}},
+		{"7", `<a href="MAILTO:broken@@example.com,ok@example.com">x</a>`, nil, []string{"ok@example.com"}},
+		{"8", `<img src="logo@2x.png" alt=""><a href="https://example.com/@user">x</a>`, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, e := range FindInHTML([]byte(tt.html), tt.opts...) {
+				got = append(got, e.String())
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindInHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
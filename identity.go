@@ -0,0 +1,130 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+	"sync"
+)
+
+// ProviderRule describes how a mail provider maps addresses to mailboxes.
+type ProviderRule struct {
+	// Domain is the canonical domain of the provider, ie. gmail.com for googlemail.com. Empty
+	// keeps the domain of the address.
+	Domain string
+
+	// Separator starts the tag of a subaddress, ie. the + of user+tag@gmail.com. Zero means the
+	// provider has no subaddresses.
+	Separator byte
+
+	// IgnoreDots is true if dots in the local part are ignored, ie. u.ser@gmail.com.
+	IgnoreDots bool
+}
+
+// providerRules maps the domains of well known mail providers to their rules. Domains of the same
+// provider that deliver to the same mailbox share a canonical domain.
+var providerRules = map[string]ProviderRule{
+	"gmail.com":      {Domain: "gmail.com", Separator: '+', IgnoreDots: true},
+	"googlemail.com": {Domain: "gmail.com", Separator: '+', IgnoreDots: true},
+	"outlook.com":    {Separator: '+'},
+	"hotmail.com":    {Separator: '+'},
+	"live.com":       {Separator: '+'},
+	"icloud.com":     {Domain: "icloud.com", Separator: '+'},
+	"me.com":         {Domain: "icloud.com", Separator: '+'},
+	"mac.com":        {Domain: "icloud.com", Separator: '+'},
+	"yahoo.com":      {Domain: "yahoo.com", Separator: '-'},
+	"ymail.com":      {Domain: "ymail.com", Separator: '-'},
+	"fastmail.com":   {Separator: '+'},
+	"protonmail.com": {Domain: "protonmail.com", Separator: '+'},
+	"protonmail.ch":  {Domain: "protonmail.com", Separator: '+'},
+	"proton.me":      {Domain: "protonmail.com", Separator: '+'},
+	"pm.me":          {Domain: "protonmail.com", Separator: '+'},
+}
+
+var providerRulesMu sync.RWMutex
+
+// defaultProviderRule applies to domains without a rule, subaddresses with a + are common (see
+// RFC 5233) while dots are usually significant.
+var defaultProviderRule = ProviderRule{Separator: '+'}
+
+// SetProviderRule sets the rule of a mail provider for domain, such as the domain of your own
+// mail server, replacing its current rule. It is safe to call while other goroutines compare
+// addresses.
+func SetProviderRule(domain string, rule ProviderRule) {
+	rule.Domain = strings.TrimSuffix(strings.ToLower(rule.Domain), ".")
+	providerRulesMu.Lock()
+	defer providerRulesMu.Unlock()
+	providerRules[strings.TrimSuffix(strings.ToLower(domain), ".")] = rule
+}
+
+func providerRule(domain string) ProviderRule {
+	providerRulesMu.RLock()
+	defer providerRulesMu.RUnlock()
+	if rule, ok := providerRules[domain]; ok {
+		return rule
+	}
+	return defaultProviderRule
+}
+
+// Canonical returns the mailbox the email address delivers to according to the rule of its
+// provider, so addresses of the same person compare equal, ie. for deduplication. Both parts are
+// lowercased, subaddress tags are removed and, for providers that ignore them, dots and
+// alternative domains, so foo.bar+news@googlemail.com becomes foobar@gmail.com. Quoted local
+// parts are only lowercased. See SetProviderRule.
+func (e EmailAddress) Canonical() EmailAddress {
+	domain := strings.TrimSuffix(strings.ToLower(e.Domain), ".")
+	local := strings.ToLower(e.LocalPart)
+	rule := providerRule(domain)
+	if rule.Domain == "" {
+		rule.Domain = domain
+	}
+	if !strings.HasPrefix(local, "\"") {
+		local, _ = splitTag(local, rule.Separator)
+		if rule.IgnoreDots {
+			local = strings.Replace(local, ".", "", -1)
+		}
+	}
+	return EmailAddress{LocalPart: local, Domain: rule.Domain}
+}
+
+// identity returns the mailbox the email address delivers to according to the rules of its
+// provider, addresses with the same identity reach the same person.
+func identity(e EmailAddress) string {
+	c := e.Canonical()
+	return c.LocalPart + "@" + c.Domain
+}
+
+// EqualIgnoringTag reports whether a and b deliver to the same mailbox, ignoring letter case and
+// subaddress tags, and for providers that ignore them, dots and alternative domains. For example
+// user+x@gmail.com, u.ser@gmail.com and user@googlemail.com are all equal to user@gmail.com.
+func EqualIgnoringTag(a, b EmailAddress) bool {
+	return identity(a) == identity(b)
+}
+
+// FindDuplicates groups the email addresses that are equal according to EqualIgnoringTag. Only
+// groups of two or more addresses are returned, ordered by the first occurrence of the group, with
+// the addresses of a group in their original order.
+func FindDuplicates(emails []*EmailAddress) [][]*EmailAddress {
+	index := make(map[string]int)
+	var groups [][]*EmailAddress
+	for _, e := range emails {
+		id := identity(*e)
+		i, ok := index[id]
+		if !ok {
+			i = len(groups)
+			index[id] = i
+			groups = append(groups, nil)
+		}
+		groups[i] = append(groups[i], e)
+	}
+	duplicates := groups[:0]
+	for _, g := range groups {
+		if len(g) > 1 {
+			duplicates = append(duplicates, g)
+		}
+	}
+	return duplicates
+}
@@ -0,0 +1,135 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEqualIgnoringTag(t *testing.T) {
+	tests := []struct {
+		name string
+		a    EmailAddress
+		b    EmailAddress
+		want bool
+	}{
+		{"1", EmailAddress{"user+x", "gmail.com"}, EmailAddress{"user", "gmail.com"}, true},
+		{"2", EmailAddress{"user.x", "gmail.com"}, EmailAddress{"userx", "gmail.com"}, true},
+		{"3", EmailAddress{"user", "googlemail.com"}, EmailAddress{"User", "Gmail.com"}, true},
+		{"4", EmailAddress{"u.ser+news", "googlemail.com"}, EmailAddress{"user", "gmail.com"}, true},
+		{"5", EmailAddress{"user.x", "example.com"}, EmailAddress{"userx", "example.com"}, false},
+		{"6", EmailAddress{"user+x", "example.com"}, EmailAddress{"user", "example.com"}, true},
+		{"7", EmailAddress{"user-x", "yahoo.com"}, EmailAddress{"user", "yahoo.com"}, true},
+		{"8", EmailAddress{"user-x", "gmail.com"}, EmailAddress{"user", "gmail.com"}, false},
+		{"9", EmailAddress{"user", "me.com"}, EmailAddress{"user+tag", "icloud.com"}, true},
+		{"10", EmailAddress{"user", "gmail.com"}, EmailAddress{"user", "hotmail.com"}, false},
+		{"11", EmailAddress{`"user+x"`, "gmail.com"}, EmailAddress{"user", "gmail.com"}, false},
+		{"12", EmailAddress{"+x", "example.com"}, EmailAddress{"", "example.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := EqualIgnoringTag(tt.a, tt.b); got != tt.want {
+				t.Errorf("EqualIgnoringTag() = %v, want %v", got, tt.want)
+			}
+			if got := EqualIgnoringTag(tt.b, tt.a); got != tt.want {
+				t.Errorf("EqualIgnoringTag() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFindDuplicates(t *testing.T) {
+	a := &EmailAddress{"user+x", "gmail.com"}
+	b := &EmailAddress{"other", "example.com"}
+	c := &EmailAddress{"user.x", "gmail.com"}
+	d := &EmailAddress{"us.er", "googlemail.com"}
+	e := &EmailAddress{"Other+spam", "example.com"}
+	f := &EmailAddress{"unique", "example.com"}
+	tests := []struct {
+		name   string
+		emails []*EmailAddress
+		want   [][]*EmailAddress
+	}{
+		{"1", []*EmailAddress{a, b, c, d, e, f}, [][]*EmailAddress{{a, d}, {b, e}}},
+		{"2", []*EmailAddress{a, b, f}, [][]*EmailAddress{}},
+		{"3", nil, [][]*EmailAddress{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindDuplicates(tt.emails)
+			if len(got) == 0 && len(tt.want) == 0 {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindDuplicates() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_Canonical(t *testing.T) {
+	tests := []struct {
+		name  string
+		email EmailAddress
+		want  EmailAddress
+	}{
+		{"1", EmailAddress{"foo.bar+news", "gmail.com"}, EmailAddress{"foobar", "gmail.com"}},
+		{"2", EmailAddress{"Foo.Bar", "GoogleMail.com"}, EmailAddress{"foobar", "gmail.com"}},
+		{"3", EmailAddress{"foo.bar+news", "outlook.com"}, EmailAddress{"foo.bar", "outlook.com"}},
+		{"4", EmailAddress{"foo-news", "yahoo.com"}, EmailAddress{"foo", "yahoo.com"}},
+		{"5", EmailAddress{"foo+news", "pm.me"}, EmailAddress{"foo", "protonmail.com"}},
+		{"6", EmailAddress{"foo.bar+news", "Example.com."}, EmailAddress{"foo.bar", "example.com"}},
+		{"7", EmailAddress{`"Foo.Bar+news"`, "gmail.com"}, EmailAddress{`"foo.bar+news"`, "gmail.com"}},
+		{"8", EmailAddress{"+news", "gmail.com"}, EmailAddress{"+news", "gmail.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.email.Canonical(); got != tt.want {
+				t.Errorf("EmailAddress.Canonical() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetProviderRule(t *testing.T) {
+	providerRulesMu.RLock()
+	old, ok := providerRules["example.net"]
+	providerRulesMu.RUnlock()
+	defer func() {
+		providerRulesMu.Lock()
+		defer providerRulesMu.Unlock()
+		if ok {
+			providerRules["example.net"] = old
+		} else {
+			delete(providerRules, "example.net")
+		}
+	}()
+
+	SetProviderRule("Example.NET", ProviderRule{Domain: "Example.COM", Separator: '_', IgnoreDots: true})
+	tests := []struct {
+		name  string
+		email EmailAddress
+		want  EmailAddress
+	}{
+		{"1", EmailAddress{"foo.bar_news", "example.net"}, EmailAddress{"foobar", "example.com"}},
+		{"2", EmailAddress{"foo.bar+news", "example.net"}, EmailAddress{"foobar+news", "example.com"}},
+		{"3", EmailAddress{"foo.bar+news", "example.org"}, EmailAddress{"foo.bar", "example.org"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.email.Canonical(); got != tt.want {
+				t.Errorf("EmailAddress.Canonical() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	SetProviderRule("example.net", ProviderRule{})
+	e := EmailAddress{"foo.bar+news", "example.net"}
+	if got, want := e.Canonical(), (EmailAddress{"foo.bar+news", "example.net"}); got != want {
+		t.Errorf("EmailAddress.Canonical() without tags = %v, want %v", got, want)
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/idna"
+)
+
+// WithSMTPUTF8 makes Parse accept internationalized email addresses as defined in RFC 6531, with
+// UTF-8 characters in the local part, ie. あいうえお@domain.com, and Unicode domain names, ie.
+// 用户@例子.广告. Such addresses can only be delivered by mail hosts that announce SMTPUTF8, use
+// ToASCII to get the domain in the form used in DNS.
+func WithSMTPUTF8() ParseOption {
+	return func(o *parseOptions) {
+		o.smtputf8 = true
+	}
+}
+
+// IsInternational reports whether the local part of the email address contains non-ASCII
+// characters, so it can only be delivered by mail hosts that support SMTPUTF8.
+func (e EmailAddress) IsInternational() bool {
+	return !isASCII(e.LocalPart)
+}
+
+// ToASCII returns the email address with the domain converted to its ASCII form, ie.
+// 用户@xn--fsqu00a.xn--4rr70v for 用户@例子.广告. A local part can't be converted and is kept.
+func (e EmailAddress) ToASCII() (EmailAddress, error) {
+	d, err := e.DomainASCII()
+	if err != nil {
+		return e, err
+	}
+	e.Domain = d
+	return e, nil
+}
+
+// ToUnicode returns the email address with the domain converted to its Unicode form, ie.
+// 用户@例子.广告 for 用户@xn--fsqu00a.xn--4rr70v.
+func (e EmailAddress) ToUnicode() (EmailAddress, error) {
+	d, err := e.DomainUnicode()
+	if err != nil {
+		return e, err
+	}
+	e.Domain = d
+	return e, nil
+}
+
+// DomainASCII returns the domain in the ASCII form used in DNS and SMTP, ie. xn--bcher-kva.example
+// for bücher.example. The remote validation functions look up and contact the ASCII form.
+func (e EmailAddress) DomainASCII() (string, error) {
+	return convertDomain(e.Domain, idna.Lookup.ToASCII)
+}
+
+// DomainUnicode returns the domain in its Unicode form for display, ie. bücher.example for
+// xn--bcher-kva.example.
+func (e EmailAddress) DomainUnicode() (string, error) {
+	return convertDomain(e.Domain, idna.Lookup.ToUnicode)
+}
+
+func convertDomain(domain string, convert func(string) (string, error)) (string, error) {
+	if strings.HasPrefix(domain, "[") {
+		return domain, nil
+	}
+	d, err := convert(domain)
+	if err != nil {
+		return domain, newError(CodeInvalidDomain, fmt.Errorf("invalid internationalized domain %s: %w", domain, err))
+	}
+	return d, nil
+}
+
+// asciiDomain returns the ASCII form of domain, or domain itself if it can't be converted, in
+// which case looking it up fails.
+func asciiDomain(domain string) string {
+	if isASCII(domain) {
+		return domain
+	}
+	d, _ := convertDomain(domain, idna.Lookup.ToASCII)
+	return d
+}
+
+// checkInternational validates an email address with non-ASCII characters with validate. Every
+// non-ASCII character of the local part is valid in a dot-atom and a quoted string, and the domain
+// must be a valid internationalized domain name, the rest of the address is validated as usual.
+func checkInternational(email string, validate func(string) error) error {
+	i := strings.LastIndexByte(email, '@')
+	if i < 0 {
+		return formatError(email)
+	}
+	local, domain := email[:i], email[i+1:]
+	err := fmt.Errorf("format is incorrect for %s", email)
+	if !utf8.ValidString(local) {
+		return newError(CodeInvalidLocalPart, err)
+	}
+	local = strings.Map(func(r rune) rune {
+		if r >= utf8.RuneSelf {
+			return 'a'
+		}
+		return r
+	}, local)
+	if !isASCII(domain) {
+		d, idnaErr := idna.Lookup.ToASCII(domain)
+		if idnaErr != nil {
+			return newError(CodeInvalidDomain, fmt.Errorf("%v: %w", err, idnaErr))
+		}
+		domain = d
+	}
+	if vErr := validate(local + "@" + domain); vErr != nil {
+		return newError(ErrorCode(vErr), err)
+	}
+	return nil
+}
+
+func isASCII(s string) bool {
+	for i := 0; i < len(s); i++ {
+		if s[i] >= utf8.RuneSelf {
+			return false
+		}
+	}
+	return true
+}
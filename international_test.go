@@ -0,0 +1,136 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParse_smtputf8(t *testing.T) {
+	tests := []struct {
+		name     string
+		email    string
+		want     *EmailAddress
+		wantCode Code
+	}{
+		{"1", "あいうえお@domain.com", &EmailAddress{"あいうえお", "domain.com"}, ""},
+		{"2", "用户@例子.广告", &EmailAddress{"用户", "例子.广告"}, ""},
+		{"3", "josé.garcía@bücher.de", &EmailAddress{"josé.garcía", "bücher.de"}, ""},
+		{"4", `"用@户"@example.com`, &EmailAddress{`"用@户"`, "example.com"}, ""},
+		{"5", "foo@bar.com", &EmailAddress{"foo", "bar.com"}, ""},
+		{"6", "用户..名@example.com", nil, CodeInvalidLocalPart},
+		{"7", "用户@例子..广告", nil, CodeInvalidDomain},
+		{"8", "用户@-例子.广告", nil, CodeInvalidDomain},
+		{"9", "用户", nil, CodeInvalidFormat},
+		{"10", "\xff@example.com", nil, CodeInvalidLocalPart},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Parse(tt.email, WithSMTPUTF8())
+			if code := ErrorCode(err); code != tt.wantCode {
+				t.Errorf("Parse() error = %v, want code %q", err, tt.wantCode)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Parse() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Parse("あいうえお@domain.com"); err == nil {
+		t.Errorf("Parse() without WithSMTPUTF8 error = nil, want an error")
+	}
+}
+
+func TestEmailAddress_ToASCII(t *testing.T) {
+	tests := []struct {
+		name        string
+		e           EmailAddress
+		wantASCII   EmailAddress
+		wantUnicode EmailAddress
+		wantErr     bool
+	}{
+		{"1", EmailAddress{"用户", "例子.广告"}, EmailAddress{"用户", "xn--fsqu00a.xn--4rr70v"}, EmailAddress{"用户", "例子.广告"}, false},
+		{"2", EmailAddress{"foo", "bücher.de"}, EmailAddress{"foo", "xn--bcher-kva.de"}, EmailAddress{"foo", "bücher.de"}, false},
+		{"3", EmailAddress{"foo", "bar.com"}, EmailAddress{"foo", "bar.com"}, EmailAddress{"foo", "bar.com"}, false},
+		{"4", EmailAddress{"foo", "[192.0.2.1]"}, EmailAddress{"foo", "[192.0.2.1]"}, EmailAddress{"foo", "[192.0.2.1]"}, false},
+		{"5", EmailAddress{"foo", "-bar.com"}, EmailAddress{"foo", "-bar.com"}, EmailAddress{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.e.ToASCII()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EmailAddress.ToASCII() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.wantASCII {
+				t.Errorf("EmailAddress.ToASCII() = %v, want %v", got, tt.wantASCII)
+			}
+			if tt.wantErr {
+				return
+			}
+			if got, err = got.ToUnicode(); err != nil || got != tt.wantUnicode {
+				t.Errorf("EmailAddress.ToUnicode() = %v, %v, want %v", got, err, tt.wantUnicode)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_IsInternational(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want bool
+	}{
+		{"1", EmailAddress{"あいうえお", "domain.com"}, true},
+		{"2", EmailAddress{"foo", "例子.广告"}, false},
+		{"3", EmailAddress{"foo", "bar.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsInternational(); got != tt.want {
+				t.Errorf("EmailAddress.IsInternational() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_DomainASCII(t *testing.T) {
+	tests := []struct {
+		name        string
+		e           EmailAddress
+		wantASCII   string
+		wantUnicode string
+		wantErr     bool
+	}{
+		{"1", EmailAddress{"user", "bücher.example"}, "xn--bcher-kva.example", "bücher.example", false},
+		{"2", EmailAddress{"user", "xn--bcher-kva.example"}, "xn--bcher-kva.example", "bücher.example", false},
+		{"3", EmailAddress{"user", "BÜCHER.example"}, "xn--bcher-kva.example", "bücher.example", false},
+		{"4", EmailAddress{"user", "例子.广告"}, "xn--fsqu00a.xn--4rr70v", "例子.广告", false},
+		{"5", EmailAddress{"user", "xn--a.example"}, "", "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ascii, err := tt.e.DomainASCII()
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("EmailAddress.DomainASCII() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			unicode, uErr := tt.e.DomainUnicode()
+			if tt.wantErr {
+				if uErr == nil {
+					t.Errorf("EmailAddress.DomainUnicode() error = nil, want an error")
+				}
+				return
+			}
+			if ascii != tt.wantASCII {
+				t.Errorf("EmailAddress.DomainASCII() = %v, want %v", ascii, tt.wantASCII)
+			}
+			if uErr != nil || unicode != tt.wantUnicode {
+				t.Errorf("EmailAddress.DomainUnicode() = %v, %v, want %v", unicode, uErr, tt.wantUnicode)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// maxAddressLength is the maximum length of an address in the forward-path of RFC 5321.
+	maxAddressLength = 254
+
+	// maxLocalPartLength is the maximum length of a local part, as per RFC 5321 section 4.5.3.1.1.
+	maxLocalPartLength = 64
+
+	// maxDomainLength is the maximum length of a domain, as per RFC 1035 section 2.3.4.
+	maxDomainLength = 253
+
+	// maxLabelLength is the maximum length of a domain label, as per RFC 1035 section 2.3.4.
+	maxLabelLength = 63
+)
+
+var (
+	// ErrAddressTooLong is returned when an address exceeds 254 octets.
+	ErrAddressTooLong = errors.New("address too long")
+
+	// ErrLocalPartTooLong is returned when a local part exceeds 64 octets.
+	ErrLocalPartTooLong = errors.New("local part too long")
+
+	// ErrDomainTooLong is returned when a domain exceeds 253 octets.
+	ErrDomainTooLong = errors.New("domain too long")
+
+	// ErrLabelTooLong is returned when a label of a domain exceeds 63 octets.
+	ErrLabelTooLong = errors.New("domain label too long")
+)
+
+// WithoutLengthLimits makes Parse and the Find functions accept addresses that exceed the length
+// limits of RFC 5321 and RFC 1035. Such addresses can't be delivered, but may still be worth
+// extracting from a text.
+func WithoutLengthLimits() ParseOption {
+	return func(o *parseOptions) {
+		o.noLengthLimits = true
+	}
+}
+
+// lengthLimits reports whether the length limits apply.
+func (o parseOptions) lengthLimits() bool {
+	return o.hardened || !o.noLengthLimits
+}
+
+// checkLength validates the length of email and its parts, in octets. An email without @ is left
+// to the format checks.
+func checkLength(email string) error {
+	i := strings.LastIndexByte(email, '@')
+	if i < 0 {
+		return nil
+	}
+	local, domain := email[:i], email[i+1:]
+	if len(local) > maxLocalPartLength {
+		return newError(CodeLocalPartTooLong, fmt.Errorf("%w: exceeds %d characters", ErrLocalPartTooLong, maxLocalPartLength))
+	}
+	if len(domain) > maxDomainLength {
+		return newError(CodeDomainTooLong, fmt.Errorf("%w: exceeds %d characters", ErrDomainTooLong, maxDomainLength))
+	}
+	if len(email) > maxAddressLength {
+		return newError(CodeAddressTooLong, fmt.Errorf("%w: exceeds %d characters", ErrAddressTooLong, maxAddressLength))
+	}
+
+	if strings.HasPrefix(domain, "[") {
+		return nil
+	}
+	for domain != "" {
+		label := domain
+		if j := strings.IndexByte(domain, '.'); j >= 0 {
+			label, domain = domain[:j], domain[j+1:]
+		} else {
+			domain = ""
+		}
+		if len(label) > maxLabelLength {
+			return newError(CodeLabelTooLong, fmt.Errorf("%w: exceeds %d characters", ErrLabelTooLong, maxLabelLength))
+		}
+	}
+	return nil
+}
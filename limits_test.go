@@ -0,0 +1,54 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestParse_lengthLimits(t *testing.T) {
+	longLocal := strings.Repeat("a", 65) + "@bar.com"
+	longLabel := "foo@" + strings.Repeat("a", 64) + ".com"
+	longDomain := "foo@" + strings.Repeat("a.", 126) + "com"
+	longAddress := strings.Repeat("a", 64) + "@" + strings.Repeat("b", 63) + "." + strings.Repeat("c", 63) + "." + strings.Repeat("d", 63) + ".com"
+	tests := []struct {
+		name    string
+		email   string
+		opts    []ParseOption
+		wantErr error
+	}{
+		{"1", strings.Repeat("a", 64) + "@bar.com", nil, nil},
+		{"2", longLocal, nil, ErrLocalPartTooLong},
+		{"3", longLabel, nil, ErrLabelTooLong},
+		{"4", longDomain, nil, ErrDomainTooLong},
+		{"5", longAddress, nil, ErrAddressTooLong},
+		{"6", longLocal, []ParseOption{WithoutLengthLimits()}, nil},
+		{"7", longLabel, []ParseOption{WithoutLengthLimits()}, nil},
+		{"8", longAddress, []ParseOption{WithoutLengthLimits()}, nil},
+		{"9", longLocal, []ParseOption{WithoutLengthLimits(), WithHardening()}, ErrLocalPartTooLong},
+		{"10", "foo@[192.0.2.1]", nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := Parse(tt.email, tt.opts...)
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Parse() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestFind_lengthLimits(t *testing.T) {
+	text := []byte("foo@bar.com " + strings.Repeat("a", 65) + "@bar.com")
+	if got := Find(text, false); len(got) != 1 {
+		t.Errorf("Find() = %v, want only the address within the limits", got)
+	}
+	if got := Find(text, false, WithoutLengthLimits()); len(got) != 2 {
+		t.Errorf("Find() with WithoutLengthLimits = %v, want both addresses", got)
+	}
+}
@@ -0,0 +1,56 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithDenyList rejects email addresses whose domain, or one of its parent domains, is in the
+// list with an error wrapping ErrDenied, without any DNS or SMTP traffic. Use a DomainTrie for
+// exact and wildcard rules.
+func WithDenyList(list DomainList) Option {
+	return func(v *Verifier) {
+		v.denyList = list
+	}
+}
+
+// WithDisposableList rejects email addresses whose domain, or one of its parent domains, is in
+// the list with an error wrapping ErrDisposable, without any DNS or SMTP traffic.
+func WithDisposableList(list DomainList) Option {
+	return func(v *Verifier) {
+		v.disposableList = list
+	}
+}
+
+// WithAllowList exempts the domains in the list, and their subdomains, from the deny and
+// disposable lists.
+func WithAllowList(list DomainList) Option {
+	return func(v *Verifier) {
+		v.allowList = list
+	}
+}
+
+// checkLists returns an error if the domain is denied by the deny or disposable list.
+func (v *Verifier) checkLists(domain string) error {
+	if v.denyList == nil && v.disposableList == nil {
+		return nil
+	}
+	domain = strings.TrimSuffix(strings.ToLower(asciiDomain(domain)), ".")
+	if listed(v.allowList, domain) {
+		return nil
+	}
+	if listed(v.denyList, domain) {
+		return newError(CodeDenied, fmt.Errorf("%w: %s", ErrDenied, domain))
+	}
+	if listed(v.disposableList, domain) {
+		return newError(CodeDisposable, fmt.Errorf("%w: %s", ErrDisposable, domain))
+	}
+	return nil
+}
@@ -0,0 +1,131 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestVerifier_lists(t *testing.T) {
+	v := NewVerifier(
+		WithDenyList(NewDomainList([]string{"spam.com", "bar.com"})),
+		WithDisposableList(NewCompactDomainList([]string{"mailinator.com"})),
+		WithAllowList(NewDomainList([]string{"ok.bar.com"})),
+		WithResolvers("127.0.0.1:1"),
+		WithDNSTimeout(1),
+	)
+	tests := []struct {
+		name    string
+		e       EmailAddress
+		wantErr error
+	}{
+		{"1", EmailAddress{"foo", "spam.com"}, ErrDenied},
+		{"2", EmailAddress{"foo", "Mail.Spam.com."}, ErrDenied},
+		{"3", EmailAddress{"foo", "mailinator.com"}, ErrDisposable},
+		{"4", EmailAddress{"foo", "ok.bar.com"}, nil},
+		{"5", EmailAddress{"foo", "gmail.com"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			status, err := v.CheckHost(context.Background(), tt.e)
+			if tt.wantErr == nil {
+				if errors.Is(err, ErrDenied) || errors.Is(err, ErrDisposable) {
+					t.Errorf("Verifier.CheckHost() error = %v, want not listed", err)
+				}
+				return
+			}
+			if !errors.Is(err, tt.wantErr) || status != HostInvalid {
+				t.Errorf("Verifier.CheckHost() = %v, %v, want %v, %v", status, err, HostInvalid, tt.wantErr)
+			}
+			if err := v.ValidateHost(context.Background(), tt.e); !errors.Is(err, tt.wantErr) {
+				t.Errorf("Verifier.ValidateHost() error = %v, want %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifier_trie(t *testing.T) {
+	trie, err := NewDomainTrie([]string{"*.spam.com", "example.com"})
+	if err != nil {
+		t.Fatalf("NewDomainTrie() error = %v", err)
+	}
+	v := NewVerifier(WithDenyList(trie), WithResolvers("127.0.0.1:1"), WithDNSTimeout(1))
+	tests := []struct {
+		name   string
+		domain string
+		denied bool
+	}{
+		{"1", "mail.spam.com", true},
+		{"2", "spam.com", false},
+		{"3", "example.com", true},
+		{"4", "mail.example.com", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := v.CheckHost(context.Background(), EmailAddress{"foo", tt.domain})
+			if got := errors.Is(err, ErrDenied); got != tt.denied {
+				t.Errorf("Verifier.CheckHost() error = %v, want denied %v", err, tt.denied)
+			}
+		})
+	}
+}
+
+func TestWatchDomainList(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "deny.txt")
+	write := func(content string, age time.Duration) {
+		if err := ioutil.WriteFile(path, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		mod := time.Now().Add(-age)
+		if err := os.Chtimes(path, mod, mod); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("spam.com\n", time.Hour)
+
+	errs := make(chan error, 10)
+	l, err := WatchDomainList(path, 10*time.Millisecond, func(err error) {
+		errs <- err
+	})
+	if err != nil {
+		t.Fatalf("WatchDomainList() error = %v", err)
+	}
+	defer l.Close()
+
+	v := NewVerifier(WithDenyList(l))
+	if err := v.ValidateHost(context.Background(), EmailAddress{"foo", "spam.com"}); !errors.Is(err, ErrDenied) {
+		t.Fatalf("Verifier.ValidateHost() error = %v, want %v", err, ErrDenied)
+	}
+
+	write("junk.com\n", 0)
+	waitFor(t, func() bool { return l.Contains("junk.com") })
+	if l.Contains("spam.com") {
+		t.Errorf("FileDomainList.Contains(%q) = true after reload, want false", "spam.com")
+	}
+
+	if err := os.Remove(path); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case err := <-errs:
+		if !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("onError() error = %v, want %v", err, os.ErrNotExist)
+		}
+	case <-time.After(time.Second):
+		t.Fatalf("onError() not called for a removed file")
+	}
+	if !l.Contains("junk.com") {
+		t.Errorf("FileDomainList.Contains(%q) = false after failed reload, want true", "junk.com")
+	}
+}
@@ -0,0 +1,206 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+)
+
+// The load test drives a Verifier against the in-process SMTP server and resolver, run it with
+// ie.
+//
+//	go test -run TestLoad -load.duration 30s -load.rate 500 -load.concurrency 32
+var (
+	loadDuration    = flag.Duration("load.duration", 0, "duration of the load test, it is skipped when 0")
+	loadRate        = flag.Int("load.rate", 200, "requests per second of the load test")
+	loadConcurrency = flag.Int("load.concurrency", 16, "maximum number of concurrent requests of the load test")
+	loadDomains     = flag.Int("load.domains", 50, "number of distinct domains of the load test")
+	loadAddresses   = flag.Int("load.addresses", 1000, "number of distinct addresses of the load test")
+	loadDNSDelay    = flag.Duration("load.dnsdelay", time.Millisecond, "simulated latency of every DNS query of the load test")
+	loadCacheTTL    = flag.Duration("load.cache", time.Minute, "cache TTL of the load test, caching is disabled when 0")
+)
+
+// loadConfig configures runLoad.
+type loadConfig struct {
+	duration    time.Duration
+	rate        int
+	concurrency int
+	domains     int
+	addresses   int
+	dnsDelay    time.Duration
+	cacheTTL    time.Duration
+}
+
+// loadReport is the outcome of runLoad.
+type loadReport struct {
+	requests  int
+	elapsed   time.Duration
+	latencies []time.Duration // sorted
+	statuses  map[HostStatus]int
+	cache     CacheStats
+}
+
+func (r loadReport) throughput() float64 {
+	return float64(r.requests) / r.elapsed.Seconds()
+}
+
+// percentile returns the latency below which p percent of the requests completed.
+func (r loadReport) percentile(p float64) time.Duration {
+	if len(r.latencies) == 0 {
+		return 0
+	}
+	i := int(float64(len(r.latencies))*p/100+0.5) - 1
+	if i < 0 {
+		i = 0
+	}
+	if i >= len(r.latencies) {
+		i = len(r.latencies) - 1
+	}
+	return r.latencies[i]
+}
+
+func (r loadReport) String() string {
+	return fmt.Sprintf("%d requests in %v (%.1f/s), latency p50 %v p90 %v p99 %v max %v, %d verified %d invalid %d unverifiable, cache hit rate %.1f%%",
+		r.requests, r.elapsed.Round(time.Millisecond), r.throughput(),
+		r.percentile(50), r.percentile(90), r.percentile(99), r.percentile(100),
+		r.statuses[HostVerified], r.statuses[HostInvalid], r.statuses[HostUnverifiable],
+		100*r.cache.HitRate())
+}
+
+// runLoad starts CheckHost requests at the configured rate for the configured duration, never
+// running more than the configured concurrency at once. One in ten addresses is rejected by the
+// SMTP server and one in ten domains doesn't exist.
+func runLoad(t testing.TB, cfg loadConfig) loadReport {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		var i int
+		fmt.Sscanf(addr, "user%d@", &i) // #nosec
+		if i%10 == 9 {
+			return "550 no such user"
+		}
+		return "250 OK"
+	}
+	r := &testResolver{mx: make(map[string][]*net.MX), delay: cfg.dnsDelay}
+	for i := 0; i < cfg.domains; i++ {
+		if i%10 != 9 {
+			r.mx[fmt.Sprintf("domain%d.example", i)] = []*net.MX{{Host: s.host(), Pref: 10}}
+		}
+	}
+
+	var opts []Option
+	if cfg.cacheTTL > 0 {
+		opts = append(opts, WithCache(cfg.cacheTTL))
+	}
+	v := NewVerifier(opts...)
+	v.resolver = r
+	v.port = s.port()
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		report = loadReport{statuses: make(map[HostStatus]int)}
+		sem    = make(chan struct{}, cfg.concurrency)
+		tick   = time.NewTicker(time.Second / time.Duration(cfg.rate))
+	)
+	defer tick.Stop()
+	start := time.Now()
+	for n := 0; time.Since(start) < cfg.duration; n++ {
+		<-tick.C
+		sem <- struct{}{}
+		wg.Add(1)
+		i := n % cfg.addresses
+		e := EmailAddress{fmt.Sprintf("user%d", i), fmt.Sprintf("domain%d.example", i%cfg.domains)}
+		go func() {
+			defer func() { <-sem; wg.Done() }()
+			begin := time.Now()
+			status, _ := v.CheckHost(context.Background(), e)
+			latency := time.Since(begin)
+			mu.Lock()
+			report.latencies = append(report.latencies, latency)
+			report.statuses[status]++
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	report.elapsed = time.Since(start)
+	report.requests = len(report.latencies)
+	report.cache = v.CacheStats()
+	sort.Slice(report.latencies, func(i, j int) bool {
+		return report.latencies[i] < report.latencies[j]
+	})
+	return report
+}
+
+func TestLoad(t *testing.T) {
+	if *loadDuration == 0 {
+		t.Skip("load test disabled, enable it with -load.duration")
+	}
+	report := runLoad(t, loadConfig{
+		duration:    *loadDuration,
+		rate:        *loadRate,
+		concurrency: *loadConcurrency,
+		domains:     *loadDomains,
+		addresses:   *loadAddresses,
+		dnsDelay:    *loadDNSDelay,
+		cacheTTL:    *loadCacheTTL,
+	})
+	t.Log(report)
+}
+
+func Test_runLoad(t *testing.T) {
+	report := runLoad(t, loadConfig{
+		duration:    200 * time.Millisecond,
+		rate:        200,
+		concurrency: 4,
+		domains:     10,
+		addresses:   20,
+		cacheTTL:    time.Minute,
+	})
+	if report.requests == 0 {
+		t.Fatalf("runLoad() made no requests")
+	}
+	if report.statuses[HostUnverifiable] != 0 {
+		t.Errorf("runLoad() %d requests unverifiable, want 0", report.statuses[HostUnverifiable])
+	}
+	if report.requests > 20 && report.cache.ResultHits == 0 {
+		t.Errorf("runLoad() cache hits = 0 for %d requests to 20 addresses", report.requests)
+	}
+	if report.percentile(50) > report.percentile(99) {
+		t.Errorf("runLoad() p50 %v > p99 %v", report.percentile(50), report.percentile(99))
+	}
+}
+
+func BenchmarkVerifier_CheckHost(b *testing.B) {
+	for _, ttl := range []time.Duration{0, time.Minute} {
+		b.Run(fmt.Sprintf("cache=%v", ttl), func(b *testing.B) {
+			s := newTestServer(b, "127.0.0.1:0")
+			r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+			var opts []Option
+			if ttl > 0 {
+				opts = append(opts, WithCache(ttl))
+			}
+			v := NewVerifier(opts...)
+			v.resolver = r
+			v.port = s.port()
+			b.ResetTimer()
+			b.RunParallel(func(pb *testing.PB) {
+				for pb.Next() {
+					v.CheckHost(context.Background(), EmailAddress{"info", "example.com"}) // #nosec
+				}
+			})
+			b.ReportMetric(100*v.CacheStats().HitRate(), "%hit")
+		})
+	}
+}
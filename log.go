@@ -0,0 +1,97 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+)
+
+// Logger receives a line for every DNS query and SMTP connection of a Verifier. A *log.Logger
+// satisfies it.
+type Logger interface {
+	Printf(format string, v ...interface{})
+}
+
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	correlationIDKey
+)
+
+// WithLogger sets the Logger used when the context of a call doesn't carry one.
+func WithLogger(l Logger) Option {
+	return func(v *Verifier) {
+		v.logger = l
+	}
+}
+
+// ContextWithLogger returns a copy of ctx carrying l, the Verifier logs the DNS queries and SMTP
+// connections of calls made with the returned context to l instead of its own Logger.
+func ContextWithLogger(ctx context.Context, l Logger) context.Context {
+	return context.WithValue(ctx, loggerKey, l)
+}
+
+// ContextWithCorrelationID returns a copy of ctx carrying id, which prefixes every line the
+// Verifier logs for calls made with the returned context. Use it to attribute DNS queries and SMTP
+// connections to the request that caused them.
+func ContextWithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey, id)
+}
+
+// CorrelationID returns the correlation ID carried by ctx, or an empty string.
+func CorrelationID(ctx context.Context) string {
+	id, _ := ctx.Value(correlationIDKey).(string)
+	return id
+}
+
+// eventLogger is a Logger taking events with attributes instead of formatted lines, such as the
+// one of WithSlog. It is given the context of the call and the correlation ID as an attribute.
+type eventLogger interface {
+	Logger
+	event(ctx context.Context, msg string, args ...interface{})
+}
+
+// loggerOf returns the logger of ctx, or the logger of the Verifier.
+func (v *Verifier) loggerOf(ctx context.Context) Logger {
+	if l, _ := ctx.Value(loggerKey).(Logger); l != nil {
+		return l
+	}
+	return v.logger
+}
+
+// logf logs a line to the logger of ctx, or the logger of the Verifier.
+func (v *Verifier) logf(ctx context.Context, format string, args ...interface{}) {
+	l := v.loggerOf(ctx)
+	if l == nil {
+		return
+	}
+	if el, ok := l.(eventLogger); ok {
+		el.event(ctx, fmt.Sprintf(format, args...))
+		return
+	}
+	if id := CorrelationID(ctx); id != "" {
+		format, args = "[%s] "+format, append([]interface{}{id}, args...)
+	}
+	l.Printf(format, args...)
+}
+
+// replyObserver returns a line observer logging the replies of host, or nil unless the logger
+// takes events. Other loggers only get a line for every recipient.
+func (v *Verifier) replyObserver(ctx context.Context, host string) func(dir string, line []byte) {
+	el, ok := v.loggerOf(ctx).(eventLogger)
+	if !ok {
+		return nil
+	}
+	return func(dir string, line []byte) {
+		if dir == "S" {
+			el.event(ctx, "smtp: reply", "host", host, "reply", string(line))
+		}
+	}
+}
@@ -0,0 +1,72 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testLogger records the lines it is given.
+type testLogger struct {
+	mu    sync.Mutex
+	lines []string
+}
+
+func (l *testLogger) Printf(format string, v ...interface{}) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.lines = append(l.lines, fmt.Sprintf(format, v...))
+}
+
+func TestVerifier_logf(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+	fallback := &testLogger{}
+	v := NewVerifier(WithLogger(fallback))
+	v.resolver = r
+	v.port = s.port()
+
+	tenant := &testLogger{}
+	ctx := ContextWithCorrelationID(ContextWithLogger(context.Background(), tenant), "req-42%")
+	if err := v.ValidateHost(ctx, EmailAddress{"info", "example.com"}); err != nil {
+		t.Fatalf("Verifier.ValidateHost() error = %v", err)
+	}
+	want := []string{"dns: MX example.com", "smtp: dial " + s.host(), "smtp: RCPT TO info@example.com"}
+	if len(tenant.lines) != len(want) {
+		t.Fatalf("logged %q, want %d lines", tenant.lines, len(want))
+	}
+	for i, line := range tenant.lines {
+		if !strings.HasPrefix(line, "[req-42%] "+want[i]) {
+			t.Errorf("line %d = %q, want prefix %q", i, line, "[req-42%] "+want[i])
+		}
+	}
+	if len(fallback.lines) != 0 {
+		t.Errorf("Verifier logger got %q, want nothing", fallback.lines)
+	}
+
+	if _, err := v.lookupHost(context.Background(), "example.org"); err == nil {
+		t.Fatalf("Verifier.lookupHost() error = nil, want error")
+	}
+	if len(fallback.lines) != 2 || !strings.HasPrefix(fallback.lines[0], "dns: MX example.org") {
+		t.Errorf("Verifier logger got %q, want MX and A/AAAA queries", fallback.lines)
+	}
+}
+
+func TestCorrelationID(t *testing.T) {
+	if got := CorrelationID(context.Background()); got != "" {
+		t.Errorf("CorrelationID() = %q, want empty", got)
+	}
+	if got := CorrelationID(ContextWithCorrelationID(context.Background(), "abc")); got != "abc" {
+		t.Errorf("CorrelationID() = %q, want %q", got, "abc")
+	}
+}
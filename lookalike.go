@@ -0,0 +1,142 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+)
+
+// homoglyphs maps characters and character sequences to the character they visually resemble.
+var homoglyphs = strings.NewReplacer(
+	// sequences
+	"rn", "m", "vv", "w", "cl", "d",
+	// digits and letters
+	"0", "o", "1", "l", "i", "l",
+	// cyrillic
+	"а", "a", "с", "c", "ԁ", "d", "е", "e", "һ", "h", "і", "l", "ј", "j", "к", "k", "о", "o",
+	"р", "p", "ѕ", "s", "у", "y", "х", "x", "ѡ", "w",
+	// greek
+	"α", "a", "ε", "e", "ι", "l", "κ", "k", "ν", "v", "ο", "o", "ρ", "p", "τ", "t", "υ", "u",
+	// latin
+	"ɡ", "g", "ı", "l", "ł", "l",
+)
+
+// Lookalike describes a domain that resembles a protected domain.
+type Lookalike struct {
+	// Domain is the domain of the email address.
+	Domain string
+
+	// Protected is the protected domain it resembles.
+	Protected string
+
+	// Distance is the edit distance between both registrable domains after folding homoglyphs.
+	Distance int
+
+	// Homoglyph is true if the registrable domains are identical after folding homoglyphs.
+	Homoglyph bool
+
+	// Embedded is true if the protected domain is used as subdomain of another domain, such as
+	// mycompany.com.example.net.
+	Embedded bool
+}
+
+// CheckLookalike compares the domain of the email address against a list of protected domains,
+// such as the domains of your own brands, and returns the closest lookalike or nil. Domains are
+// compared by their registrable domain (ie. mycompany.co.uk) after folding characters that
+// look alike, such as 0 and o or rn and m, so mycornpany.com, rnycompany.com and mycompany.co
+// are all flagged as lookalikes of mycompany.com. Subdomains of the protected domains are never
+// flagged.
+func (e EmailAddress) CheckLookalike(protected []string) *Lookalike {
+	domain := strings.TrimSuffix(strings.ToLower(e.Domain), ".")
+	registrable := registrableDomain(domain)
+	folded := homoglyphs.Replace(registrable)
+
+	var best *Lookalike
+	for _, p := range protected {
+		p = registrableDomain(strings.TrimSuffix(strings.ToLower(p), "."))
+		if p == registrable {
+			return nil
+		}
+		l := &Lookalike{Domain: e.Domain, Protected: p}
+		if strings.HasPrefix(domain, p+".") || strings.Contains(domain, "."+p+".") {
+			l.Embedded = true
+		} else {
+			l.Distance = editDistance(folded, homoglyphs.Replace(p))
+			l.Homoglyph = l.Distance == 0
+			if l.Distance > maxLookalikeDistance(p) {
+				continue
+			}
+		}
+		if best == nil || l.Distance < best.Distance {
+			best = l
+		}
+	}
+	return best
+}
+
+// maxLookalikeDistance returns the edit distance under which a domain is considered to resemble
+// the protected domain, shorter domains allow less edits to avoid false positives.
+func maxLookalikeDistance(protected string) int {
+	if len(protected) < 8 {
+		return 1
+	}
+	return 2
+}
+
+// registrableDomain returns the domain directly below the public suffix, or the domain itself if
+// it has no public suffix.
+func registrableDomain(domain string) string {
+	if d, err := effectiveTLDPlusOne(domain); err == nil {
+		return d
+	}
+	return domain
+}
+
+// editDistance returns the optimal string alignment distance between a and b: the number of
+// insertions, deletions, substitutions and transpositions of adjacent characters needed to change
+// one into the other.
+func editDistance(a, b string) int {
+	return int(weightedDistance(a, b, func(x, y rune) float64 {
+		return 1
+	}))
+}
+
+// weightedDistance returns the optimal string alignment distance between a and b, where
+// substituting x by y costs subst(x, y) and every other edit costs 1.
+func weightedDistance(a, b string, subst func(x, y rune) float64) float64 {
+	s, t := []rune(a), []rune(b)
+	d := make([][]float64, len(s)+1)
+	for i := range d {
+		d[i] = make([]float64, len(t)+1)
+		d[i][0] = float64(i)
+	}
+	for j := range d[0] {
+		d[0][j] = float64(j)
+	}
+	for i := 1; i <= len(s); i++ {
+		for j := 1; j <= len(t); j++ {
+			cost := 0.0
+			if s[i-1] != t[j-1] {
+				cost = subst(s[i-1], t[j-1])
+			}
+			d[i][j] = min3(d[i-1][j]+1, d[i][j-1]+1, d[i-1][j-1]+cost)
+			if i > 1 && j > 1 && s[i-1] == t[j-2] && s[i-2] == t[j-1] && d[i-2][j-2]+1 < d[i][j] {
+				d[i][j] = d[i-2][j-2] + 1
+			}
+		}
+	}
+	return d[len(s)][len(t)]
+}
+
+func min3(a, b, c float64) float64 {
+	if b < a {
+		a = b
+	}
+	if c < a {
+		a = c
+	}
+	return a
+}
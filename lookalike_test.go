@@ -0,0 +1,64 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEmailAddress_CheckLookalike(t *testing.T) {
+	protected := []string{"mycompany.com", "acme.co.uk"}
+	tests := []struct {
+		name   string
+		domain string
+		want   *Lookalike
+	}{
+		{"1", "mycompany.com", nil},
+		{"2", "mail.MyCompany.com", nil},
+		{"3", "example.org", nil},
+		{"4", "mycornpany.com", &Lookalike{"mycornpany.com", "mycompany.com", 0, true, false}},
+		{"5", "myc0mpany.com", &Lookalike{"myc0mpany.com", "mycompany.com", 0, true, false}},
+		{"6", "mусоmpany.com", &Lookalike{"mусоmpany.com", "mycompany.com", 0, true, false}},
+		{"7", "mycomapny.com", &Lookalike{"mycomapny.com", "mycompany.com", 1, false, false}},
+		{"8", "mycompany.co", &Lookalike{"mycompany.co", "mycompany.com", 1, false, false}},
+		{"9", "mycompany.com.example.net", &Lookalike{"mycompany.com.example.net", "mycompany.com", 0, false, true}},
+		{"10", "acne.co.uk", &Lookalike{"acne.co.uk", "acme.co.uk", 1, false, false}},
+		{"11", "acne.com", nil},
+		{"12", "mycompanies.com", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := EmailAddress{LocalPart: "ceo", Domain: tt.domain}
+			if got := e.CheckLookalike(protected); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("EmailAddress.CheckLookalike() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func Test_editDistance(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b string
+		want int
+	}{
+		{"1", "", "", 0},
+		{"2", "gmail.com", "gmail.com", 0},
+		{"3", "gmail.com", "gmial.com", 1},
+		{"4", "gmail.com", "gmail.con", 1},
+		{"5", "gmail.com", "gmai.com", 1},
+		{"6", "gmail.com", "hotmail.com", 3},
+		{"7", "", "abc", 3},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := editDistance(tt.a, tt.b); got != tt.want {
+				t.Errorf("editDistance() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
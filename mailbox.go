@@ -0,0 +1,58 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"net/mail"
+	"strings"
+)
+
+// Mailbox is an email address with an optional display name, as found in the From and To header
+// fields of a message.
+type Mailbox struct {
+	// Name is the decoded display name, ie. Joe Smith, empty if the mailbox has none.
+	Name string
+
+	// Address is the email address of the mailbox.
+	Address *EmailAddress
+}
+
+// String returns the mailbox in the format of a header field, ie. "Joe Smith" <email@domain.com>.
+// Display names with non-ASCII characters are encoded as RFC 2047 encoded words.
+func (m Mailbox) String() string {
+	addr := &mail.Address{Name: m.Name}
+	if m.Address != nil {
+		addr.Address = m.Address.String()
+	}
+	return addr.String()
+}
+
+// ParseWithDisplayName parses a mailbox as defined in RFC 5322 with net/mail, either a name-addr
+// such as Joe Smith <email@domain.com> or a bare address. RFC 2047 encoded words in the display
+// name, such as =?UTF-8?q?J=C3=B6rg?=, are decoded. The address is validated like Parse with opts.
+func ParseWithDisplayName(mailbox string, opts ...ParseOption) (*Mailbox, error) {
+	a, err := mail.ParseAddress(mailbox)
+	if err != nil {
+		return nil, newError(CodeInvalidMailbox, fmt.Errorf("invalid mailbox %s: %w", mailbox, err))
+	}
+	e, err := Parse(quoteLocalPart(a.Address), opts...)
+	if err != nil {
+		return nil, err
+	}
+	return &Mailbox{Name: a.Name, Address: e}, nil
+}
+
+// quoteLocalPart quotes the local part of addr again if it isn't a dot-atom, as net/mail returns
+// the content of a quoted local part.
+func quoteLocalPart(addr string) string {
+	i := strings.LastIndexByte(addr, '@')
+	if i < 0 || validDotAtom(addr[:i]) {
+		return addr
+	}
+	local := strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(addr[:i])
+	return `"` + local + `"` + addr[i:]
+}
@@ -0,0 +1,68 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseWithDisplayName(t *testing.T) {
+	tests := []struct {
+		name     string
+		mailbox  string
+		want     *Mailbox
+		wantCode Code
+	}{
+		{"1", "Joe Smith <email@domain.com>", &Mailbox{"Joe Smith", &EmailAddress{"email", "domain.com"}}, ""},
+		{"2", `"Smith, Joe" <email@domain.com>`, &Mailbox{"Smith, Joe", &EmailAddress{"email", "domain.com"}}, ""},
+		{"3", "email@domain.com", &Mailbox{"", &EmailAddress{"email", "domain.com"}}, ""},
+		{"4", "<email@domain.com>", &Mailbox{"", &EmailAddress{"email", "domain.com"}}, ""},
+		{"5", "=?UTF-8?q?J=C3=B6rg_M=C3=BCller?= <joerg@domain.com>", &Mailbox{"Jörg Müller", &EmailAddress{"joerg", "domain.com"}}, ""},
+		{"6", "=?ISO-8859-1?Q?Andr=E9?= <andre@domain.com>", &Mailbox{"André", &EmailAddress{"andre", "domain.com"}}, ""},
+		{"7", `Joe <"joe smith"@domain.com>`, &Mailbox{"Joe", &EmailAddress{`"joe smith"`, "domain.com"}}, CodeInvalidLocalPart},
+		{"8", `Joe <"joe@smith"@domain.com>`, &Mailbox{"Joe", &EmailAddress{`"joe@smith"`, "domain.com"}}, ""},
+		{"9", "Joe Smith <email@domain>", nil, CodeInvalidDomain},
+		{"10", "Joe Smith email@domain.com", nil, CodeInvalidMailbox},
+		{"11", "Joe Smith <email@domain.com", nil, CodeInvalidMailbox},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseWithDisplayName(tt.mailbox)
+			if code := ErrorCode(err); code != tt.wantCode {
+				t.Fatalf("ParseWithDisplayName() error = %v, want code %q", err, tt.wantCode)
+			}
+			if err != nil {
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseWithDisplayName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMailbox_String(t *testing.T) {
+	tests := []struct {
+		name string
+		m    Mailbox
+		want string
+	}{
+		{"1", Mailbox{"Joe Smith", &EmailAddress{"email", "domain.com"}}, `"Joe Smith" <email@domain.com>`},
+		{"2", Mailbox{"", &EmailAddress{"email", "domain.com"}}, "<email@domain.com>"},
+		{"3", Mailbox{"Jörg", &EmailAddress{"joerg", "domain.com"}}, "=?utf-8?q?J=C3=B6rg?= <joerg@domain.com>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.m.String(); got != tt.want {
+				t.Errorf("Mailbox.String() = %v, want %v", got, tt.want)
+			}
+			if got, err := ParseWithDisplayName(tt.m.String()); err != nil || !reflect.DeepEqual(*got, tt.m) {
+				t.Errorf("ParseWithDisplayName(Mailbox.String()) = %v, %v, want %v", got, err, tt.m)
+			}
+		})
+	}
+}
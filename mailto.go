@@ -0,0 +1,158 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const (
+	// mailtoAddrChars are the characters that don't need to be percent-encoded in an address of a
+	// mailto URI, besides letters and digits.
+	mailtoAddrChars = "-._~!$'*+@"
+
+	// mailtoValueChars are the characters that don't need to be percent-encoded in a header field
+	// value of a mailto URI, besides letters and digits.
+	mailtoValueChars = "-._~!$'()*+,;:@"
+)
+
+// MailtoOptions holds the optional header fields of a mailto URI.
+type MailtoOptions struct {
+	Subject string
+	Body    string
+	Cc      []*EmailAddress
+	Bcc     []*EmailAddress
+}
+
+// Mailto returns a mailto URI as defined in RFC 6068 with the email address as recipient and the
+// header fields of opts, ie. mailto:foo@bar.com?subject=Hello%20world. Line breaks in the body are
+// encoded as CRLF.
+func (e EmailAddress) Mailto(opts MailtoOptions) string {
+	var b strings.Builder
+	b.WriteString("mailto:")
+	b.WriteString(escapeMailto(e.String(), mailtoAddrChars))
+
+	sep := "?"
+	field := func(name, value string) {
+		if value == "" {
+			return
+		}
+		b.WriteString(sep)
+		b.WriteString(name)
+		b.WriteByte('=')
+		b.WriteString(value)
+		sep = "&"
+	}
+	field("cc", escapeMailtoList(opts.Cc))
+	field("bcc", escapeMailtoList(opts.Bcc))
+	field("subject", escapeMailto(opts.Subject, mailtoValueChars))
+	body := strings.Replace(strings.Replace(opts.Body, "\r\n", "\n", -1), "\n", "\r\n", -1)
+	field("body", escapeMailto(body, mailtoValueChars))
+	return b.String()
+}
+
+// ParseMailto parses a mailto URI as defined in RFC 6068 and returns its recipients and the
+// subject, body, cc and bcc header fields. Every address is parsed and validated locally. Other
+// header fields are ignored.
+func ParseMailto(uri string) (to []*EmailAddress, opts MailtoOptions, err error) {
+	if len(uri) < 7 || !strings.EqualFold(uri[:7], "mailto:") {
+		return nil, opts, newError(CodeInvalidMailto, fmt.Errorf("not a mailto URI: %s", uri))
+	}
+	uri = uri[7:]
+	if i := strings.IndexByte(uri, '#'); i >= 0 {
+		uri = uri[:i]
+	}
+	query := ""
+	if i := strings.IndexByte(uri, '?'); i >= 0 {
+		uri, query = uri[:i], uri[i+1:]
+	}
+
+	if to, err = parseMailtoList(uri); err != nil {
+		return nil, opts, err
+	}
+	for _, hfield := range strings.Split(query, "&") {
+		if hfield == "" {
+			continue
+		}
+		name, value := hfield, ""
+		if i := strings.IndexByte(hfield, '='); i >= 0 {
+			name, value = hfield[:i], hfield[i+1:]
+		}
+		switch strings.ToLower(name) {
+		case "to":
+			var list []*EmailAddress
+			if list, err = parseMailtoList(value); err == nil {
+				to = append(to, list...)
+			}
+		case "cc":
+			var list []*EmailAddress
+			if list, err = parseMailtoList(value); err == nil {
+				opts.Cc = append(opts.Cc, list...)
+			}
+		case "bcc":
+			var list []*EmailAddress
+			if list, err = parseMailtoList(value); err == nil {
+				opts.Bcc = append(opts.Bcc, list...)
+			}
+		case "subject":
+			opts.Subject, err = url.PathUnescape(value)
+		case "body":
+			opts.Body, err = url.PathUnescape(value)
+		}
+		if err != nil {
+			return nil, MailtoOptions{}, err
+		}
+	}
+	return to, opts, nil
+}
+
+// parseMailtoList parses a comma separated list of percent-encoded addresses.
+func parseMailtoList(s string) ([]*EmailAddress, error) {
+	s, err := url.PathUnescape(s)
+	if err != nil {
+		return nil, err
+	}
+	var list []*EmailAddress
+	for _, addr := range strings.Split(s, ",") {
+		if addr == "" {
+			continue
+		}
+		e, err := Parse(addr)
+		if err != nil {
+			return nil, err
+		}
+		list = append(list, e)
+	}
+	return list, nil
+}
+
+// escapeMailtoList returns the percent-encoded, comma separated list of the addresses.
+func escapeMailtoList(list []*EmailAddress) string {
+	addrs := make([]string, 0, len(list))
+	for _, e := range list {
+		addrs = append(addrs, escapeMailto(e.String(), mailtoAddrChars))
+	}
+	return strings.Join(addrs, ",")
+}
+
+// escapeMailto percent-encodes every byte of s that isn't a letter, a digit or one of allowed.
+func escapeMailto(s, allowed string) string {
+	const hex = "0123456789ABCDEF"
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9' || strings.IndexByte(allowed, c) >= 0 {
+			b.WriteByte(c)
+			continue
+		}
+		b.WriteByte('%')
+		b.WriteByte(hex[c>>4])
+		b.WriteByte(hex[c&15])
+	}
+	return b.String()
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestEmailAddress_Mailto(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		opts MailtoOptions
+		want string
+	}{
+		{"1", EmailAddress{"foo", "bar.com"}, MailtoOptions{}, "mailto:foo@bar.com"},
+		{"2", EmailAddress{"foo+news", "bar.com"}, MailtoOptions{}, "mailto:foo+news@bar.com"},
+		{"3", EmailAddress{"foo?bar", "bar.com"}, MailtoOptions{}, "mailto:foo%3Fbar@bar.com"},
+		{"4", EmailAddress{"\"not@me\"", "bar.com"}, MailtoOptions{}, "mailto:%22not@me%22@bar.com"},
+		{"5", EmailAddress{"foo", "bar.com"}, MailtoOptions{Subject: "Hello world & more"}, "mailto:foo@bar.com?subject=Hello%20world%20%26%20more"},
+		{"6", EmailAddress{"foo", "bar.com"}, MailtoOptions{Body: "line 1\nline 2"}, "mailto:foo@bar.com?body=line%201%0D%0Aline%202"},
+		{"7", EmailAddress{"foo", "bar.com"}, MailtoOptions{Subject: "café"}, "mailto:foo@bar.com?subject=caf%C3%A9"},
+		{"8", EmailAddress{"foo", "bar.com"}, MailtoOptions{
+			Subject: "Hi",
+			Cc:      []*EmailAddress{{"a", "bar.com"}, {"b", "bar.com"}},
+			Bcc:     []*EmailAddress{{"c%d", "bar.com"}},
+		}, "mailto:foo@bar.com?cc=a@bar.com,b@bar.com&bcc=c%25d@bar.com&subject=Hi"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.Mailto(tt.opts); got != tt.want {
+				t.Errorf("EmailAddress.Mailto() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseMailto(t *testing.T) {
+	tests := []struct {
+		name     string
+		uri      string
+		wantTo   []*EmailAddress
+		wantOpts MailtoOptions
+		wantErr  bool
+	}{
+		{"1", "mailto:foo@bar.com", []*EmailAddress{{"foo", "bar.com"}}, MailtoOptions{}, false},
+		{"2", "MAILTO:foo+news@bar.com", []*EmailAddress{{"foo+news", "bar.com"}}, MailtoOptions{}, false},
+		{"3", "mailto:foo@bar.com,baz@bar.com", []*EmailAddress{{"foo", "bar.com"}, {"baz", "bar.com"}}, MailtoOptions{}, false},
+		{"4", "mailto:foo@bar.com?Subject=Hello%20world&body=a+b", []*EmailAddress{{"foo", "bar.com"}}, MailtoOptions{Subject: "Hello world", Body: "a+b"}, false},
+		{"5", "mailto:?to=foo@bar.com&cc=a@bar.com,b@bar.com&bcc=c@bar.com", []*EmailAddress{{"foo", "bar.com"}}, MailtoOptions{
+			Cc:  []*EmailAddress{{"a", "bar.com"}, {"b", "bar.com"}},
+			Bcc: []*EmailAddress{{"c", "bar.com"}},
+		}, false},
+		{"6", "mailto:foo%3Fbar@bar.com?x-custom=1", []*EmailAddress{{"foo?bar", "bar.com"}}, MailtoOptions{}, false},
+		{"7", "http://bar.com", nil, MailtoOptions{}, true},
+		{"8", "mailto:foo", nil, MailtoOptions{}, true},
+		{"9", "mailto:foo@bar.com?subject=%zz", nil, MailtoOptions{}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			gotTo, gotOpts, err := ParseMailto(tt.uri)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMailto() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(gotTo, tt.wantTo) {
+				t.Errorf("ParseMailto() to = %v, want %v", gotTo, tt.wantTo)
+			}
+			if !reflect.DeepEqual(gotOpts, tt.wantOpts) {
+				t.Errorf("ParseMailto() opts = %v, want %v", gotOpts, tt.wantOpts)
+			}
+		})
+	}
+}
+
+func TestParseMailto_roundTrip(t *testing.T) {
+	e := EmailAddress{"foo?bar", "bar.com"}
+	opts := MailtoOptions{
+		Subject: "Hello & welcome, friend?",
+		Body:    "line 1\r\nline 2 100%",
+		Cc:      []*EmailAddress{{"a+b", "bar.com"}},
+	}
+	to, got, err := ParseMailto(e.Mailto(opts))
+	if err != nil {
+		t.Fatalf("ParseMailto() error = %v", err)
+	}
+	if !reflect.DeepEqual(to, []*EmailAddress{&e}) || !reflect.DeepEqual(got, opts) {
+		t.Errorf("ParseMailto(Mailto()) = %v, %v, want %v, %v", to, got, e, opts)
+	}
+}
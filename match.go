@@ -0,0 +1,130 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bytes"
+	"html"
+)
+
+// Match is an email address found by FindIndex or FindFunc.
+type Match struct {
+	// Start and End are the byte offsets of the address in the haystack, haystack[Start:End] is
+	// the text that was matched.
+	Start, End int
+
+	// Address is the parsed address, it is nil for the matches of FindFunc.
+	Address *EmailAddress
+
+	// LocalPart and Domain are the parts of the address, set by FindFunc. They share memory with
+	// the haystack, so they are only valid as long as the haystack isn't modified.
+	LocalPart, Domain []byte
+}
+
+// Parse parses the address of m with opts, see Parse. Use it to keep an address of FindFunc.
+func (m Match) Parse(opts ...ParseOption) (*EmailAddress, error) {
+	if m.Address != nil {
+		return m.Address, nil
+	}
+	b := make([]byte, 0, len(m.LocalPart)+1+len(m.Domain))
+	b = append(append(append(b, m.LocalPart...), '@'), m.Domain...)
+	return Parse(string(b), opts...)
+}
+
+// FindFunc calls fn with every candidate address in haystack, in order, until fn returns false.
+// Candidates match the regular expression of Find but are not parsed, so FindFunc doesn't
+// allocate an EmailAddress per candidate: LocalPart and Domain of the Match are slices of
+// haystack and Address is nil. A few candidates, such as a..b@example.com, are rejected by Parse,
+// so call Match.Parse for the addresses that are kept.
+func FindFunc(haystack []byte, fn func(Match) bool) {
+	for start := 0; start < len(haystack); {
+		loc := findCommonRegexp.FindIndex(haystack[start:])
+		if loc == nil {
+			return
+		}
+		m := Match{Start: start + loc[0], End: start + loc[1]}
+		start = m.End
+		if m.End-m.Start > maxAddressLength {
+			continue
+		}
+		b := haystack[m.Start:m.End:m.End]
+		i := bytes.LastIndexByte(b, '@')
+		m.LocalPart, m.Domain = b[:i:i], b[i+1:]
+		if !fn(m) {
+			return
+		}
+	}
+}
+
+// FindIndex finds email addresses like Find and returns them with their position in haystack, ie.
+// to highlight or redact them. With WithHTMLEntityDecoding the offsets still refer to haystack, so
+// a match of foo&#64;bar.com spans the entity.
+func FindIndex(haystack []byte, opts ...ParseOption) []Match {
+	o := newParseOptions(opts)
+	b, offsets := haystack, []int(nil)
+	if o.htmlEntities {
+		b, offsets = unescapeIndex(haystack)
+	}
+
+	var matches []Match
+	for start := 0; start < len(b); {
+		loc := findCommonRegexp.FindIndex(b[start:])
+		if loc == nil {
+			break
+		}
+		m := Match{Start: start + loc[0], End: start + loc[1]}
+		start = m.End
+		if o.lengthLimits() && m.End-m.Start > maxAddressLength {
+			continue
+		}
+		e, err := o.parse(string(b[m.Start:m.End]))
+		if err != nil || o.skip(e) {
+			continue
+		}
+		if offsets != nil {
+			m.Start, m.End = offsets[m.Start], offsets[m.End]
+		}
+		m.Address = e
+		matches = append(matches, m)
+		if o.hardened && len(matches) >= maxHardenedResults {
+			break
+		}
+	}
+	return matches
+}
+
+// unescapeIndex decodes the HTML entities in b like html.UnescapeString. offsets holds the offset
+// in b of every byte of the decoded text, plus the length of b.
+func unescapeIndex(b []byte) (decoded []byte, offsets []int) {
+	decoded = make([]byte, 0, len(b))
+	offsets = make([]int, 0, len(b)+1)
+	for i := 0; i < len(b); {
+		if b[i] != '&' {
+			decoded = append(decoded, b[i])
+			offsets = append(offsets, i)
+			i++
+			continue
+		}
+		// An entity is decoded by the name or number following the &, up to an optional ;.
+		j := i + 1
+		for j < len(b) && (b[j] == '#' || isAlphanumeric(b[j])) {
+			j++
+		}
+		if j < len(b) && b[j] == ';' {
+			j++
+		}
+		for _, c := range []byte(html.UnescapeString(string(b[i:j]))) {
+			decoded = append(decoded, c)
+			offsets = append(offsets, i)
+		}
+		i = j
+	}
+	return decoded, append(offsets, len(b))
+}
+
+func isAlphanumeric(c byte) bool {
+	return c >= 'a' && c <= 'z' || c >= 'A' && c <= 'Z' || c >= '0' && c <= '9'
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestFindIndex(t *testing.T) {
+	tests := []struct {
+		name     string
+		haystack string
+		opts     []ParseOption
+		want     []string
+	}{
+		{"1", "Send me an email at foo@bar.com or foo@domain.fakesuffix.", nil, []string{"foo@bar.com", "foo@domain.fakesuffix"}},
+		{"2", "no addresses here, only foo at bar.com", nil, nil},
+		{"3", "mail foo&#64;bar.com &amp; bar&commat;baz.com", []ParseOption{WithHTMLEntityDecoding()}, []string{"foo&#64;bar.com", "bar&commat;baz.com"}},
+		{"4", "café foo@bar.com", []ParseOption{WithHTMLEntityDecoding()}, []string{"foo@bar.com"}},
+		{"5", "x&eacute; foo@bar.com", []ParseOption{WithHTMLEntityDecoding()}, []string{"foo@bar.com"}},
+		{"6", strings.Repeat("a", 65) + "@bar.com foo@bar.com", []ParseOption{WithHardening()}, []string{"foo@bar.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := FindIndex([]byte(tt.haystack), tt.opts...)
+			if len(got) != len(tt.want) {
+				t.Fatalf("FindIndex() = %v, want %v", got, tt.want)
+			}
+			found := Find([]byte(tt.haystack), false, tt.opts...)
+			for i, m := range got {
+				if s := tt.haystack[m.Start:m.End]; s != tt.want[i] {
+					t.Errorf("FindIndex()[%d] matched %q, want %q", i, s, tt.want[i])
+				}
+				if *m.Address != *found[i] {
+					t.Errorf("FindIndex()[%d].Address = %v, want %v", i, m.Address, found[i])
+				}
+			}
+		})
+	}
+}
+
+func TestFindFunc(t *testing.T) {
+	tests := []struct {
+		name     string
+		haystack string
+		stop     int
+		want     []string
+	}{
+		{"1", "Send me an email at foo@bar.com or foo@domain.fakesuffix.", 0, []string{"foo@bar.com", "foo@domain.fakesuffix"}},
+		{"2", "no addresses here, only foo at bar.com", 0, nil},
+		{"3", "a@b.com c@d.com e@f.com", 2, []string{"a@b.com", "c@d.com"}},
+		{"4", strings.Repeat("a", 250) + "@bar.com foo@bar.com", 0, []string{"foo@bar.com"}},
+		{"5", "a..b@example.com", 0, []string{"a..b@example.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			haystack := []byte(tt.haystack)
+			var got []string
+			FindFunc(haystack, func(m Match) bool {
+				if s := string(m.LocalPart) + "@" + string(m.Domain); s != tt.haystack[m.Start:m.End] {
+					t.Errorf("FindFunc() parts %q, want %q", s, tt.haystack[m.Start:m.End])
+				}
+				if &m.LocalPart[0] != &haystack[m.Start] {
+					t.Errorf("FindFunc() LocalPart is a copy, want a slice of the haystack")
+				}
+				got = append(got, tt.haystack[m.Start:m.End])
+				return len(got) != tt.stop
+			})
+			if strings.Join(got, " ") != strings.Join(tt.want, " ") {
+				t.Errorf("FindFunc() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMatch_Parse(t *testing.T) {
+	var matches []Match
+	FindFunc([]byte("foo@bar.com a..b@example.com"), func(m Match) bool {
+		matches = append(matches, m)
+		return true
+	})
+	if len(matches) != 2 {
+		t.Fatalf("FindFunc() = %d matches, want 2", len(matches))
+	}
+	if e, err := matches[0].Parse(); err != nil || e.String() != "foo@bar.com" {
+		t.Errorf("Match.Parse() = %v, %v, want foo@bar.com", e, err)
+	}
+	if _, err := matches[1].Parse(); ErrorCode(err) != CodeInvalidLocalPart {
+		t.Errorf("Match.Parse() error = %v, want %v", err, CodeInvalidLocalPart)
+	}
+}
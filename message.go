@@ -0,0 +1,196 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"encoding/base64"
+	"fmt"
+	"io"
+	"mime"
+	"mime/multipart"
+	"mime/quotedprintable"
+	"net/mail"
+	"net/textproto"
+	"sort"
+	"strconv"
+	"strings"
+
+	"golang.org/x/text/encoding/htmlindex"
+)
+
+// maxMessageDepth is the number of nested multipart and message/rfc822 parts FindInMessage
+// descends into.
+const maxMessageDepth = 16
+
+// MessageAddress is an email address found by FindInMessage.
+type MessageAddress struct {
+	Address *EmailAddress
+
+	// Sources are where the address was found, in order: a header, such as "header:From", the
+	// body of a single part message, "body", or a MIME part by its position, such as "part:1.2".
+	// The sources of an attached message are prefixed by its part, such as "part:2 header:From".
+	Sources []string
+}
+
+// FindInMessage finds the email addresses in the RFC 5322 message read from r, ie. an .eml file.
+// It walks the MIME structure of the message, decodes the encoded-words of its headers and the
+// base64 and quoted-printable parts of its body, converts them to UTF-8 and searches every header
+// and text part like FindWithOptions with opts, text/html parts like FindInHTML. Attachments other
+// than text and messages are skipped. Every address is returned once, in the order it was first
+// found, with the headers and parts it was found in. Addresses are compared like Equal. When a
+// part can't be read, the addresses found until then are returned with the error.
+func FindInMessage(r io.Reader, opts ...FindOption) ([]MessageAddress, error) {
+	m, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	f := &messageFinder{opts: opts, index: make(map[EmailAddress]int)}
+	err = f.message(textproto.MIMEHeader(m.Header), m.Body, "", 0)
+	return f.found, err
+}
+
+// messageFinder collects the addresses of a message and their sources.
+type messageFinder struct {
+	opts  []FindOption
+	found []MessageAddress
+	index map[EmailAddress]int
+}
+
+func (f *messageFinder) add(emails []*EmailAddress, source string) {
+	for _, e := range emails {
+		key := equalOptions{}.key(*e)
+		i, ok := f.index[key]
+		if !ok {
+			i = len(f.found)
+			f.index[key] = i
+			f.found = append(f.found, MessageAddress{Address: e})
+		}
+		if sources := f.found[i].Sources; len(sources) == 0 || sources[len(sources)-1] != source {
+			f.found[i].Sources = append(sources, source)
+		}
+	}
+}
+
+// message searches the headers and the body of a message, prefix is the source of the part it
+// is attached as.
+func (f *messageFinder) message(h textproto.MIMEHeader, body io.Reader, prefix string, depth int) error {
+	keys := make([]string, 0, len(h))
+	for k := range h {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	dec := mime.WordDecoder{CharsetReader: charsetReader}
+	for _, k := range keys {
+		for _, v := range h[k] {
+			if d, err := dec.DecodeHeader(v); err == nil {
+				v = d
+			}
+			f.add(FindWithOptions([]byte(v), f.opts...), prefix+"header:"+k)
+		}
+	}
+	return f.part(h, body, prefix, "", depth)
+}
+
+// part searches the body of a part with the header h, path is its position in the message, such
+// as 1.2, empty for the body of the message itself.
+func (f *messageFinder) part(h textproto.MIMEHeader, body io.Reader, prefix, path string, depth int) error {
+	source := prefix + "body"
+	if path != "" {
+		source = prefix + "part:" + path
+	}
+	mediaType, params, err := mime.ParseMediaType(h.Get("Content-Type"))
+	if err != nil {
+		mediaType, params = "text/plain", nil
+	}
+	if strings.HasPrefix(mediaType, "multipart/") {
+		if depth >= maxMessageDepth {
+			return nil
+		}
+		mr := multipart.NewReader(body, params["boundary"])
+		for i := 1; ; i++ {
+			p, err := mr.NextRawPart()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("%s: %w", source, err)
+			}
+			child := strconv.Itoa(i)
+			if path != "" {
+				child = path + "." + child
+			}
+			if err = f.part(p.Header, p, prefix, child, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	r := decodeTransfer(body, h.Get("Content-Transfer-Encoding"))
+	switch {
+	case mediaType == "message/rfc822":
+		if depth >= maxMessageDepth {
+			return nil
+		}
+		m, err := mail.ReadMessage(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		return f.message(textproto.MIMEHeader(m.Header), m.Body, source+" ", depth+1)
+	case strings.HasPrefix(mediaType, "text/"):
+		if cs := params["charset"]; cs != "" {
+			if cr, err := charsetReader(cs, r); err == nil {
+				r = cr
+			}
+		}
+		text, err := io.ReadAll(r)
+		if err != nil {
+			return fmt.Errorf("%s: %w", source, err)
+		}
+		if mediaType == "text/html" {
+			f.add(FindInHTML(text, f.opts...), source)
+		} else {
+			f.add(FindWithOptions(text, f.opts...), source)
+		}
+	}
+	return nil
+}
+
+// decodeTransfer decodes the body of a part with the Content-Transfer-Encoding cte.
+func decodeTransfer(r io.Reader, cte string) io.Reader {
+	switch strings.ToLower(strings.TrimSpace(cte)) {
+	case "base64":
+		return base64.NewDecoder(base64.StdEncoding, &base64Cleaner{r: r})
+	case "quoted-printable":
+		return quotedprintable.NewReader(r)
+	}
+	return r
+}
+
+// base64Cleaner drops the characters base64.NewDecoder doesn't skip, such as the spaces and tabs
+// some mailers leave at the end of a line.
+type base64Cleaner struct {
+	r io.Reader
+}
+
+func (c *base64Cleaner) Read(p []byte) (int, error) {
+	n, err := c.r.Read(p)
+	b := p[:0]
+	for _, ch := range p[:n] {
+		if ch != ' ' && ch != '\t' {
+			b = append(b, ch)
+		}
+	}
+	return len(b), err
+}
+
+// charsetReader converts the text read from input in charset to UTF-8.
+func charsetReader(charset string, input io.Reader) (io.Reader, error) {
+	enc, err := htmlindex.Get(charset)
+	if err != nil {
+		return nil, err
+	}
+	return enc.NewDecoder().Reader(input), nil
+}
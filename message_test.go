@@ -0,0 +1,96 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+const testMessage = "From: =?UTF-8?B?SsO8cmdlbg==?= <jurgen@example.com>\r\n" +
+	"To: team@example.org\r\n" +
+	"Subject: =?ISO-8859-1?Q?Kontakt_=E4_sales=40example.net?=\r\n" +
+	"MIME-Version: 1.0\r\n" +
+	"Content-Type: multipart/mixed; boundary=outer\r\n" +
+	"\r\n" +
+	"--outer\r\n" +
+	"Content-Type: multipart/alternative; boundary=inner\r\n" +
+	"\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/plain; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: quoted-printable\r\n" +
+	"\r\n" +
+	"Write to support=40example.com or jurgen@example.com, a very long line th=\r\n" +
+	"at is soft broken.\r\n" +
+	"--inner\r\n" +
+	"Content-Type: text/html; charset=utf-8\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"PGEgaHJlZj0ibWFpbHRvOmh0bWxAZXhhbXBsZS5jb20/c3ViamVjdD1IaSI+TWFpbDwvYT48\r\n" +
+	"c2NyaXB0PmEgPSAic3BhbUBldmlsLmNvbSI8L3NjcmlwdD4=\r\n" +
+	"--inner--\r\n" +
+	"--outer\r\n" +
+	"Content-Type: image/png\r\n" +
+	"Content-Transfer-Encoding: base64\r\n" +
+	"\r\n" +
+	"aW1hZ2VAZXhhbXBsZS5jb20=\r\n" +
+	"--outer\r\n" +
+	"Content-Type: message/rfc822\r\n" +
+	"\r\n" +
+	"From: old@example.com\r\n" +
+	"\r\n" +
+	"Forwarded from team@Example.ORG\r\n" +
+	"--outer--\r\n"
+
+func TestFindInMessage(t *testing.T) {
+	found, err := FindInMessage(strings.NewReader(testMessage))
+	if err != nil {
+		t.Fatalf("FindInMessage() error = %v", err)
+	}
+	want := map[string][]string{
+		"jurgen@example.com":  {"header:From", "part:1.1"},
+		"sales@example.net":   {"header:Subject"},
+		"team@example.org":    {"header:To", "part:3 body"},
+		"support@example.com": {"part:1.1"},
+		"html@example.com":    {"part:1.2"},
+		"old@example.com":     {"part:3 header:From"},
+	}
+	got := make(map[string][]string)
+	for _, a := range found {
+		got[a.Address.String()] = a.Sources
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("FindInMessage() = %v, want %v", got, want)
+	}
+	if found[0].Address.String() != "jurgen@example.com" {
+		t.Errorf("FindInMessage()[0] = %v, want the first address of the headers", found[0].Address)
+	}
+}
+
+func TestFindInMessage_errors(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+		want    int
+		wantErr bool
+	}{
+		{"1", "not a message", 0, true},
+		{"2", "From: a@example.com\r\n\r\nplain b@example.com\r\n", 2, false},
+		{"3", "From: a@example.com\r\nContent-Type: text/plain\r\nContent-Transfer-Encoding: base64\r\n\r\n!!!\r\n", 1, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			found, err := FindInMessage(strings.NewReader(tt.message))
+			if (err != nil) != tt.wantErr {
+				t.Errorf("FindInMessage() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(found) != tt.want {
+				t.Errorf("FindInMessage() = %v, want %d addresses", found, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,91 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"time"
+)
+
+// Metrics receives measurements of the DNS lookups, cache lookups and SMTP probes of a Verifier,
+// ie. to count them with Prometheus counters and histograms. The mail host is passed as its
+// registrable domain, ie. google.com for aspmx.l.google.com, to keep the number of label values
+// low. A Metrics must be safe for concurrent use by multiple goroutines and return quickly.
+type Metrics interface {
+	// DNSLookup is called after every DNS query, retries included once, with the record type
+	// (MX, IP, TXT or PTR), the duration and the error of the query.
+	DNSLookup(recordType string, d time.Duration, err error)
+
+	// CacheLookup is called for every lookup of a cache of WithCache, cache is host, result or
+	// catchall.
+	CacheLookup(cache string, hit bool)
+
+	// SMTPConnect is called after every connection attempt to a mail host.
+	SMTPConnect(host string, d time.Duration, err error)
+
+	// SMTPProbe is called after every conversation with a mail host with the class of its last
+	// reply, 2xx, 4xx or 5xx, or error if the conversation failed without a reply, and the
+	// duration of the conversation.
+	SMTPProbe(host string, class string, d time.Duration)
+}
+
+// WithMetrics reports the DNS lookups, cache lookups and SMTP probes of the Verifier to m.
+func WithMetrics(m Metrics) Option {
+	return func(v *Verifier) {
+		v.metrics = m
+	}
+}
+
+// replyClass returns the class of the reply err is the result of, see Metrics.SMTPProbe.
+func replyClass(err error) string {
+	if err == nil {
+		return "2xx"
+	}
+	var smtpErr *SMTPError
+	if errors.As(err, &smtpErr) {
+		return strconv.Itoa(smtpErr.Code/100) + "xx"
+	}
+	return "error"
+}
+
+// metricsResolver reports the queries of resolver to metrics.
+type metricsResolver struct {
+	resolver resolver
+	metrics  Metrics
+}
+
+func (r *metricsResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	start := time.Now()
+	mx, err := r.resolver.LookupMX(ctx, name)
+	r.metrics.DNSLookup("MX", time.Since(start), err)
+	return mx, err
+}
+
+func (r *metricsResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	start := time.Now()
+	ips, err := r.resolver.LookupIP(ctx, network, host)
+	r.metrics.DNSLookup("IP", time.Since(start), err)
+	return ips, err
+}
+
+func (r *metricsResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	start := time.Now()
+	txt, err := r.resolver.LookupTXT(ctx, name)
+	r.metrics.DNSLookup("TXT", time.Since(start), err)
+	return txt, err
+}
+
+func (r *metricsResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	start := time.Now()
+	names, err := r.resolver.LookupAddr(ctx, addr)
+	r.metrics.DNSLookup("PTR", time.Since(start), err)
+	return names, err
+}
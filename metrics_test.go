@@ -0,0 +1,109 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+	"time"
+)
+
+// countingMetrics is a Metrics counting the measurements by their labels.
+type countingMetrics struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (m *countingMetrics) add(format string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[fmt.Sprintf(format, args...)]++
+}
+
+func (m *countingMetrics) DNSLookup(recordType string, d time.Duration, err error) {
+	m.add("dns %s %v", recordType, err != nil)
+}
+
+func (m *countingMetrics) CacheLookup(cache string, hit bool) {
+	m.add("cache %s %v", cache, hit)
+}
+
+func (m *countingMetrics) SMTPConnect(host string, d time.Duration, err error) {
+	m.add("connect %s %v", host, err != nil)
+}
+
+func (m *countingMetrics) SMTPProbe(host string, class string, d time.Duration) {
+	m.add("probe %s %s", host, class)
+}
+
+func TestWithMetrics(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		switch addr {
+		case "info@example.com":
+			return "250 OK"
+		case "later@example.com":
+			return "451 4.7.1 greylisted"
+		default:
+			return "550 5.1.1 no such user"
+		}
+	}
+	m := &countingMetrics{}
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+	v := NewVerifier(WithPort(s.port()), WithCache(time.Minute), WithMetrics(m), WithResolver(r))
+
+	for _, local := range []string{"info", "info", "nobody", "later"} {
+		v.CheckHost(context.Background(), EmailAddress{local, "example.com"}) // #nosec
+	}
+	v.CheckHost(context.Background(), EmailAddress{"info", "example.net"}) // #nosec
+
+	want := map[string]int{
+		"dns MX false":            1,
+		"dns MX true":             1,
+		"dns IP true":             1,
+		"cache result false":      4,
+		"cache result true":       1,
+		"cache host false":        2,
+		"cache host true":         2,
+		"connect 127.0.0.1 false": 3,
+		"probe 127.0.0.1 2xx":     1,
+		"probe 127.0.0.1 4xx":     1,
+		"probe 127.0.0.1 5xx":     1,
+	}
+	if !reflect.DeepEqual(m.counts, want) {
+		t.Errorf("Metrics = %v, want %v", m.counts, want)
+	}
+}
+
+func Test_replyClass(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want string
+	}{
+		{"1", nil, "2xx"},
+		{"2", newError(CodeSMTPTemporary, &SMTPError{Code: 421}), "4xx"},
+		{"3", newError(CodeMailboxRejected, &SMTPError{Code: 550}), "5xx"},
+		{"4", newError(CodeSMTPFailure, errors.New("EOF")), "error"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := replyClass(tt.err); got != tt.want {
+				t.Errorf("replyClass() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
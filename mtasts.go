@@ -0,0 +1,162 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxMTASTSMaxAge is the largest max_age of an MTA-STS policy, about a year.
+const maxMTASTSMaxAge = 31557600 * time.Second
+
+// MTASTSPolicy is a parsed MTA-STS policy (RFC 8461), with which a domain requires mail to be
+// delivered over TLS to the listed mail hosts.
+type MTASTSPolicy struct {
+	// ID is the id of the _mta-sts TXT record, it changes when the policy changes. It is empty if
+	// the domain has no MTA-STS policy.
+	ID string
+
+	// Mode is enforce, testing or none. With testing, failures are only reported.
+	Mode string
+
+	// MX are the patterns of the allowed mail hosts, ie. mail.example.com or *.example.net.
+	MX []string
+
+	// MaxAge is the time the policy may be cached.
+	MaxAge time.Duration
+}
+
+// Exists reports whether the domain has an MTA-STS policy.
+func (p MTASTSPolicy) Exists() bool {
+	return p.ID != ""
+}
+
+// Enforced reports whether mail may only be delivered over TLS to the allowed mail hosts.
+func (p MTASTSPolicy) Enforced() bool {
+	return p.Mode == "enforce"
+}
+
+// Matches reports whether a mail host is allowed by the policy. A pattern starting with *.
+// matches a single label, so *.example.com matches mx.example.com but not example.com.
+func (p MTASTSPolicy) Matches(host string) bool {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	for _, pattern := range p.MX {
+		pattern = strings.ToLower(pattern)
+		if strings.HasPrefix(pattern, "*.") {
+			if i := strings.IndexByte(host, '.'); i > 0 && host[i+1:] == pattern[2:] {
+				return true
+			}
+		} else if host == pattern {
+			return true
+		}
+	}
+	return false
+}
+
+// ParseMTASTSPolicy parses an MTA-STS policy file, as served at
+// https://mta-sts.example.com/.well-known/mta-sts.txt. The ID is not part of the file.
+func ParseMTASTSPolicy(policy string) (*MTASTSPolicy, error) {
+	p := &MTASTSPolicy{}
+	version, maxAge := false, false
+	for _, line := range strings.Split(policy, "\n") {
+		line = strings.TrimRight(line, "\r")
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		i := strings.IndexByte(line, ':')
+		if i < 0 {
+			return nil, fmt.Errorf("invalid MTA-STS policy, malformed line %q", line)
+		}
+		key, value := strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		switch key {
+		case "version":
+			if value != "STSv1" {
+				return nil, fmt.Errorf("invalid MTA-STS policy, unsupported version %q", value)
+			}
+			version = true
+		case "mode":
+			if value != "enforce" && value != "testing" && value != "none" {
+				return nil, fmt.Errorf("invalid MTA-STS policy, unknown mode %q", value)
+			}
+			p.Mode = value
+		case "mx":
+			p.MX = append(p.MX, value)
+		case "max_age":
+			n, err := strconv.ParseUint(value, 10, 32)
+			if err != nil || time.Duration(n)*time.Second > maxMTASTSMaxAge {
+				return nil, fmt.Errorf("invalid MTA-STS policy, max_age %q is not a valid number of seconds", value)
+			}
+			p.MaxAge, maxAge = time.Duration(n)*time.Second, true
+		}
+	}
+	switch {
+	case !version:
+		return nil, fmt.Errorf("invalid MTA-STS policy, missing version")
+	case p.Mode == "":
+		return nil, fmt.Errorf("invalid MTA-STS policy, missing mode")
+	case !maxAge:
+		return nil, fmt.Errorf("invalid MTA-STS policy, missing max_age")
+	case p.Mode != "none" && len(p.MX) == 0:
+		return nil, fmt.Errorf("invalid MTA-STS policy, missing mx")
+	}
+	return p, nil
+}
+
+// mtaSTSRecordID returns the id of an _mta-sts TXT record, ie. v=STSv1; id=20160831085700Z, and
+// whether txt is such a record.
+func mtaSTSRecordID(txt string) (id string, ok bool) {
+	tags := strings.Split(txt, ";")
+	if strings.TrimSpace(tags[0]) != "v=STSv1" {
+		return "", false
+	}
+	for _, tag := range tags[1:] {
+		if i := strings.IndexByte(tag, '='); i >= 0 && strings.TrimSpace(tag[:i]) == "id" {
+			id = strings.TrimSpace(tag[i+1:])
+		}
+	}
+	return id, true
+}
+
+// TLSRPTRecord is a parsed SMTP TLS reporting record (RFC 8460), published at _smtp._tls followed
+// by the domain.
+type TLSRPTRecord struct {
+	// Record is the TXT record, it is empty if the domain has none.
+	Record string
+
+	// Reports are the URIs that TLS failure reports are sent to, ie. mailto:tlsrpt@example.com or
+	// https://report.example.com/v1.
+	Reports []string
+}
+
+// Exists reports whether the domain has a TLSRPT record.
+func (r TLSRPTRecord) Exists() bool {
+	return r.Record != ""
+}
+
+// ParseTLSRPT parses a TLSRPT record, ie. v=TLSRPTv1; rua=mailto:tlsrpt@example.com.
+func ParseTLSRPT(record string) (*TLSRPTRecord, error) {
+	if !isTLSRPT(record) {
+		return nil, fmt.Errorf("invalid TLSRPT record, missing v=TLSRPTv1: %q", record)
+	}
+	r := &TLSRPTRecord{Record: record}
+	for _, tag := range strings.Split(record, ";")[1:] {
+		if i := strings.IndexByte(tag, '='); i >= 0 && strings.TrimSpace(tag[:i]) == "rua" {
+			r.Reports = uriList(tag[i+1:])
+		}
+	}
+	if len(r.Reports) == 0 {
+		return nil, fmt.Errorf("invalid TLSRPT record, missing rua: %q", record)
+	}
+	return r, nil
+}
+
+// isTLSRPT reports whether the TXT record is a TLSRPT record.
+func isTLSRPT(txt string) bool {
+	return strings.TrimSpace(strings.SplitN(txt, ";", 2)[0]) == "v=TLSRPTv1"
+}
@@ -0,0 +1,94 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestParseMTASTSPolicy(t *testing.T) {
+	tests := []struct {
+		name    string
+		policy  string
+		want    *MTASTSPolicy
+		wantErr bool
+	}{
+		{"1", "version: STSv1\r\nmode: enforce\r\nmx: mail.example.com\r\nmx: *.example.net\r\nmax_age: 604800\r\n",
+			&MTASTSPolicy{Mode: "enforce", MX: []string{"mail.example.com", "*.example.net"}, MaxAge: 7 * 24 * time.Hour}, false},
+		{"2", "version: STSv1\nmode: none\nmax_age: 0\n", &MTASTSPolicy{Mode: "none"}, false},
+		{"3", "version: STSv1\nmode: testing\nmx: mx.example.com\nmax_age: 86400\nextension: ignored\n",
+			&MTASTSPolicy{Mode: "testing", MX: []string{"mx.example.com"}, MaxAge: 24 * time.Hour}, false},
+		{"4", "version: STSv2\nmode: enforce\nmx: mx.example.com\nmax_age: 86400\n", nil, true},
+		{"5", "version: STSv1\nmode: strict\nmx: mx.example.com\nmax_age: 86400\n", nil, true},
+		{"6", "version: STSv1\nmode: enforce\nmax_age: 86400\n", nil, true},
+		{"7", "version: STSv1\nmode: enforce\nmx: mx.example.com\n", nil, true},
+		{"8", "version: STSv1\nmode: enforce\nmx: mx.example.com\nmax_age: 99999999\n", nil, true},
+		{"9", "<html>not found</html>", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseMTASTSPolicy(tt.policy)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseMTASTSPolicy() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseMTASTSPolicy() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMTASTSPolicy_Matches(t *testing.T) {
+	p := MTASTSPolicy{Mode: "enforce", MX: []string{"mail.example.com", "*.Example.net"}}
+	tests := []struct {
+		name string
+		host string
+		want bool
+	}{
+		{"1", "mail.example.com.", true},
+		{"2", "MAIL.example.com", true},
+		{"3", "mx.example.com", false},
+		{"4", "mx1.example.net", true},
+		{"5", "example.net", false},
+		{"6", "a.mx1.example.net", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := p.Matches(tt.host); got != tt.want {
+				t.Errorf("MTASTSPolicy.Matches() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTLSRPT(t *testing.T) {
+	tests := []struct {
+		name    string
+		record  string
+		want    []string
+		wantErr bool
+	}{
+		{"1", "v=TLSRPTv1; rua=mailto:tlsrpt@example.com", []string{"mailto:tlsrpt@example.com"}, false},
+		{"2", "v=TLSRPTv1;rua=mailto:a@example.com,https://report.example.com/v1", []string{"mailto:a@example.com", "https://report.example.com/v1"}, false},
+		{"3", "v=TLSRPTv1;", nil, true},
+		{"4", "v=spf1 -all", nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseTLSRPT(tt.record)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseTLSRPT() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && !reflect.DeepEqual(got.Reports, tt.want) {
+				t.Errorf("ParseTLSRPT() = %v, want %v", got.Reports, tt.want)
+			}
+		})
+	}
+}
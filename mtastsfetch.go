@@ -0,0 +1,111 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+)
+
+// maxMTASTSPolicySize is the largest MTA-STS policy file that is read.
+const maxMTASTSPolicySize = 64 << 10
+
+// WithHTTPClient sets the client used to fetch MTA-STS policies, which are served over HTTPS.
+// Defaults to http.DefaultClient.
+func WithHTTPClient(c *http.Client) Option {
+	return func(v *Verifier) {
+		v.httpClient = c
+	}
+}
+
+// CheckMTASTS looks up the _mta-sts TXT record of domain and fetches the MTA-STS policy it
+// announces from https://mta-sts. followed by the domain. A domain without a record returns an
+// empty MTASTSPolicy. Use Matches to check the mail hosts against the policy.
+func (v *Verifier) CheckMTASTS(ctx context.Context, domain string) (*MTASTSPolicy, error) {
+	domain = asciiDomain(domain)
+	txt, err := v.resolver.LookupTXT(ctx, "_mta-sts."+domain)
+	v.logf(ctx, "dns: TXT _mta-sts.%s: %d records, error %v", domain, len(txt), err)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	var id string
+	for _, t := range txt {
+		if i, ok := mtaSTSRecordID(t); ok {
+			if id != "" {
+				return nil, fmt.Errorf("domain %s has multiple MTA-STS records", domain)
+			}
+			if i == "" {
+				return nil, fmt.Errorf("MTA-STS record of domain %s has no id", domain)
+			}
+			id = i
+		}
+	}
+	if id == "" {
+		return &MTASTSPolicy{}, nil
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, "https://mta-sts."+domain+"/.well-known/mta-sts.txt", nil)
+	if err != nil {
+		return nil, err
+	}
+	c := v.httpClient
+	if c == nil {
+		c = http.DefaultClient
+	}
+	resp, err := c.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching MTA-STS policy of domain %s: %s", domain, resp.Status)
+	}
+	if t, _, _ := mime.ParseMediaType(resp.Header.Get("Content-Type")); t != "text/plain" {
+		return nil, fmt.Errorf("MTA-STS policy of domain %s has media type %q, want text/plain", domain, t)
+	}
+	b, err := io.ReadAll(io.LimitReader(resp.Body, maxMTASTSPolicySize))
+	if err != nil {
+		return nil, err
+	}
+	p, err := ParseMTASTSPolicy(string(b))
+	if err != nil {
+		return nil, err
+	}
+	p.ID = id
+	return p, nil
+}
+
+// CheckTLSRPT looks up the SMTP TLS reporting record of domain. A domain without a record returns
+// an empty TLSRPTRecord.
+func (v *Verifier) CheckTLSRPT(ctx context.Context, domain string) (*TLSRPTRecord, error) {
+	domain = asciiDomain(domain)
+	txt, err := v.resolver.LookupTXT(ctx, "_smtp._tls."+domain)
+	v.logf(ctx, "dns: TXT _smtp._tls.%s: %d records, error %v", domain, len(txt), err)
+	if err != nil && !isNotFound(err) {
+		return nil, err
+	}
+	var r *TLSRPTRecord
+	for _, t := range txt {
+		if !isTLSRPT(t) {
+			continue
+		}
+		if r != nil {
+			return nil, fmt.Errorf("domain %s has multiple TLSRPT records", domain)
+		}
+		if r, err = ParseTLSRPT(t); err != nil {
+			return nil, err
+		}
+	}
+	if r == nil {
+		return &TLSRPTRecord{}, nil
+	}
+	return r, nil
+}
@@ -0,0 +1,107 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifier_CheckMTASTS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case r.URL.Path != "/.well-known/mta-sts.txt":
+			http.NotFound(w, r)
+		case r.Host == "mta-sts.example.com":
+			w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+			io.WriteString(w, "version: STSv1\nmode: enforce\nmx: *.example.com\nmax_age: 86400\n") // #nosec
+		case r.Host == "mta-sts.example.net":
+			w.Header().Set("Content-Type", "text/html")
+			io.WriteString(w, "version: STSv1\nmode: enforce\nmx: *.example.net\nmax_age: 86400\n") // #nosec
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer srv.Close()
+
+	// Every policy host is served by srv.
+	client := srv.Client()
+	client.Transport.(*http.Transport).DialContext = func(ctx context.Context, network, addr string) (net.Conn, error) {
+		return (&net.Dialer{}).DialContext(ctx, network, srv.Listener.Addr().String())
+	}
+	r := &testResolver{
+		txt: map[string][]string{
+			"_mta-sts.example.com": {"v=STSv1; id=20160831085700Z;"},
+			"_mta-sts.example.net": {"v=STSv1; id=1"},
+			"_mta-sts.example.org": {"v=STSv1; id=1"},
+			"_mta-sts.example.io":  {"v=STSv1;"},
+		},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		id      string
+		mode    string
+		wantErr bool
+	}{
+		{"1", "example.com", "20160831085700Z", "enforce", false},
+		{"2", "example.net", "", "", true},
+		{"3", "example.org", "", "", true},
+		{"4", "example.io", "", "", true},
+		{"5", "missing.com", "", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithResolver(r), WithHTTPClient(client))
+			got, err := v.CheckMTASTS(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.CheckMTASTS() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && (got.ID != tt.id || got.Mode != tt.mode) {
+				t.Errorf("Verifier.CheckMTASTS() = %+v, want id %v, mode %v", got, tt.id, tt.mode)
+			}
+		})
+	}
+}
+
+func TestVerifier_CheckTLSRPT(t *testing.T) {
+	r := &testResolver{
+		txt: map[string][]string{
+			"_smtp._tls.example.com": {"v=TLSRPTv1; rua=mailto:tlsrpt@example.com"},
+			"_smtp._tls.example.net": {"v=TLSRPTv1; rua=mailto:a@example.net", "v=TLSRPTv1; rua=mailto:b@example.net"},
+		},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		exists  bool
+		wantErr bool
+	}{
+		{"1", "example.com", true, false},
+		{"2", "example.net", false, true},
+		{"3", "missing.com", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithResolver(r))
+			got, err := v.CheckTLSRPT(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.CheckTLSRPT() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != nil && got.Exists() != tt.exists {
+				t.Errorf("Verifier.CheckTLSRPT() = %+v, want exists %v", got, tt.exists)
+			}
+		})
+	}
+}
@@ -0,0 +1,89 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build nonet || tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+)
+
+// ErrNoNetwork is returned by the remote validation functions when the package is built without
+// network support, with the nonet build tag or by TinyGo. It wraps ErrUnverifiable.
+var ErrNoNetwork = fmt.Errorf("%w: built without network support", ErrUnverifiable)
+
+// ValidateHost always returns ErrNoNetwork, the package is built without network support.
+func (e EmailAddress) ValidateHost() error {
+	return ErrNoNetwork
+}
+
+// ValidateHostContext always returns ErrNoNetwork, the package is built without network support.
+func (e EmailAddress) ValidateHostContext(ctx context.Context) error {
+	return ErrNoNetwork
+}
+
+// CheckHost always returns HostUnverifiable and ErrNoNetwork, the package is built without
+// network support.
+func (e EmailAddress) CheckHost() (HostStatus, error) {
+	return HostUnverifiable, ErrNoNetwork
+}
+
+// LookupHost always returns ErrNoNetwork, the package is built without network support.
+func LookupHost(domain string) (string, error) {
+	return "", ErrNoNetwork
+}
+
+// LookupMXRecords always returns ErrNoNetwork, the package is built without network support.
+func LookupMXRecords(domain string) ([]MXRecord, error) {
+	return nil, ErrNoNetwork
+}
+
+// CheckSPF always returns ErrNoNetwork, the package is built without network support. Use
+// ParseSPF with records of your own.
+func CheckSPF(domain string) (*SPFRecord, error) {
+	return nil, ErrNoNetwork
+}
+
+// CheckDMARC always returns ErrNoNetwork, the package is built without network support. Use
+// ParseDMARC with records of your own.
+func CheckDMARC(domain string) (*DMARCRecord, error) {
+	return nil, ErrNoNetwork
+}
+
+// CheckMTASTS always returns ErrNoNetwork, the package is built without network support. Use
+// ParseMTASTSPolicy with policies of your own.
+func CheckMTASTS(domain string) (*MTASTSPolicy, error) {
+	return nil, ErrNoNetwork
+}
+
+// CheckTLSRPT always returns ErrNoNetwork, the package is built without network support. Use
+// ParseTLSRPT with records of your own.
+func CheckTLSRPT(domain string) (*TLSRPTRecord, error) {
+	return nil, ErrNoNetwork
+}
+
+// IdentifyProvider always returns ErrNoNetwork, the package is built without network support.
+// Use ProviderForHost with mail hosts of your own.
+func IdentifyProvider(domain string) (Provider, []string, error) {
+	return ProviderUnknown, nil, ErrNoNetwork
+}
+
+// TryHost always returns ErrNoNetwork, the package is built without network support.
+func TryHost(host string, e EmailAddress) error {
+	return ErrNoNetwork
+}
+
+// TryHostContext always returns ErrNoNetwork, the package is built without network support.
+func TryHostContext(ctx context.Context, host string, e EmailAddress) error {
+	return ErrNoNetwork
+}
+
+// FetchSuffixList always returns ErrNoNetwork, the package is built without network support. Use
+// LoadSuffixList or ParseSuffixList instead.
+func FetchSuffixList(ctx context.Context, url string) (SuffixProvider, error) {
+	return nil, ErrNoNetwork
+}
@@ -0,0 +1,35 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build nonet || tinygo
+
+package emailaddress
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestNoNetwork(t *testing.T) {
+	e := EmailAddress{"foo", "gmail.com"}
+	if err := e.ValidateHost(); !errors.Is(err, ErrNoNetwork) {
+		t.Errorf("EmailAddress.ValidateHost() error = %v, want %v", err, ErrNoNetwork)
+	}
+	if status, err := e.CheckHost(); status != HostUnverifiable || !errors.Is(err, ErrUnverifiable) {
+		t.Errorf("EmailAddress.CheckHost() = %v, %v, want %v, %v", status, err, HostUnverifiable, ErrNoNetwork)
+	}
+	if _, err := LookupHost("gmail.com"); !errors.Is(err, ErrNoNetwork) {
+		t.Errorf("LookupHost() error = %v, want %v", err, ErrNoNetwork)
+	}
+	if err := TryHost("gmail-smtp-in.l.google.com", e); !errors.Is(err, ErrNoNetwork) {
+		t.Errorf("TryHost() error = %v, want %v", err, ErrNoNetwork)
+	}
+	if got := Find([]byte("foo@gmail.com"), true); len(got) != 0 {
+		t.Errorf("Find() = %v, want no addresses when validating hosts", got)
+	}
+	if got := Find([]byte("foo@gmail.com"), false); len(got) != 1 {
+		t.Errorf("Find() = %v, want foo@gmail.com", got)
+	}
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+
+	"golang.org/x/text/cases"
+	"golang.org/x/text/unicode/norm"
+)
+
+// NormalizationForm is a Unicode normalization form as defined in Unicode Standard Annex #15.
+type NormalizationForm int
+
+const (
+	// NFC is canonical decomposition followed by canonical composition, RFC 6532 recommends it
+	// for internationalized email addresses.
+	NFC NormalizationForm = iota
+
+	// NFKC is compatibility decomposition followed by canonical composition, it also folds
+	// compatibility characters such as fullwidth letters and ligatures.
+	NFKC
+
+	// NFD is canonical decomposition.
+	NFD
+
+	// NFKD is compatibility decomposition.
+	NFKD
+)
+
+// NormalizeOptions configures Normalize.
+type NormalizeOptions struct {
+	// Form is the Unicode normalization form, defaults to NFC.
+	Form NormalizationForm
+
+	// FoldLocalPart case folds the local part. The local part is case sensitive as per RFC 5321,
+	// but most systems treat it as case insensitive.
+	FoldLocalPart bool
+}
+
+// Normalize returns the email address with both parts in the Unicode normalization form of opts,
+// the domain is lowercased. Different systems use different equivalence rules, for instance NFKC
+// and case folding are common for identity providers, so the rules are configurable.
+func (e EmailAddress) Normalize(opts NormalizeOptions) EmailAddress {
+	f := opts.Form.form()
+	local := f.String(e.LocalPart)
+	if opts.FoldLocalPart {
+		local = f.String(cases.Fold().String(local))
+	}
+	return EmailAddress{
+		LocalPart: local,
+		Domain:    f.String(strings.ToLower(f.String(e.Domain))),
+	}
+}
+
+func (n NormalizationForm) form() norm.Form {
+	switch n {
+	case NFKC:
+		return norm.NFKC
+	case NFD:
+		return norm.NFD
+	case NFKD:
+		return norm.NFKD
+	default:
+		return norm.NFC
+	}
+}
@@ -0,0 +1,33 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "testing"
+
+func TestEmailAddress_Normalize(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		opts NormalizeOptions
+		want EmailAddress
+	}{
+		{"1", EmailAddress{"Foo", "Bar.COM"}, NormalizeOptions{}, EmailAddress{"Foo", "bar.com"}},
+		{"2", EmailAddress{"Foo", "Bar.COM"}, NormalizeOptions{FoldLocalPart: true}, EmailAddress{"foo", "bar.com"}},
+		{"3", EmailAddress{"jose\u0301", "bar.com"}, NormalizeOptions{}, EmailAddress{"jos\u00e9", "bar.com"}},
+		{"4", EmailAddress{"jos\u00e9", "bar.com"}, NormalizeOptions{Form: NFD}, EmailAddress{"jose\u0301", "bar.com"}},
+		{"5", EmailAddress{"ｆｏｏ", "bar.com"}, NormalizeOptions{}, EmailAddress{"ｆｏｏ", "bar.com"}},
+		{"6", EmailAddress{"ｆｏｏ", "bar.com"}, NormalizeOptions{Form: NFKC}, EmailAddress{"foo", "bar.com"}},
+		{"7", EmailAddress{"Straße", "BÜCHER.example"}, NormalizeOptions{FoldLocalPart: true}, EmailAddress{"strasse", "bücher.example"}},
+		{"8", EmailAddress{"ﬁle", "bar.com"}, NormalizeOptions{Form: NFKD}, EmailAddress{"file", "bar.com"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.Normalize(tt.opts); got != tt.want {
+				t.Errorf("EmailAddress.Normalize() = %+q, want %+q", got, tt.want)
+			}
+		})
+	}
+}
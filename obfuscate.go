@@ -0,0 +1,65 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strconv"
+	"strings"
+)
+
+// ObfuscationStyle is a rendering of an email address that is harder to scrape, see Obfuscate.
+type ObfuscationStyle int
+
+const (
+	// ObfuscateWords spells out the @ and dots, ie. user [at] domain [dot] com. It is understood
+	// by people and by FindObfuscated.
+	ObfuscateWords ObfuscationStyle = iota
+
+	// ObfuscateHTMLEntities encodes every character as a decimal HTML entity, ie. &#117;&#115;...
+	// Browsers display the address as is, while scrapers need to decode the entities first, see
+	// WithHTMLEntityDecoding.
+	ObfuscateHTMLEntities
+
+	// ObfuscateROT13 rotates the letters of the address by 13 places, ie. hfre@qbznva.pbz. The
+	// encoding is reversible, obfuscating the result again returns the address, so it can be
+	// decoded by a script in the browser.
+	ObfuscateROT13
+)
+
+// Obfuscate returns the email address rendered in style, for publishing contact addresses on
+// websites. It is not a security measure, it only deters simple scrapers.
+func (e EmailAddress) Obfuscate(style ObfuscationStyle) string {
+	s := e.String()
+	switch style {
+	case ObfuscateHTMLEntities:
+		var b strings.Builder
+		for _, r := range s {
+			b.WriteString("&#")
+			b.WriteString(strconv.Itoa(int(r)))
+			b.WriteByte(';')
+		}
+		return b.String()
+	case ObfuscateROT13:
+		return strings.Map(rot13, s)
+	default:
+		i := strings.LastIndexByte(s, '@')
+		if i < 0 {
+			return s
+		}
+		return s[:i] + " [at] " + strings.Replace(s[i+1:], ".", " [dot] ", -1)
+	}
+}
+
+func rot13(r rune) rune {
+	switch {
+	case r >= 'a' && r <= 'z':
+		return 'a' + (r-'a'+13)%26
+	case r >= 'A' && r <= 'Z':
+		return 'A' + (r-'A'+13)%26
+	default:
+		return r
+	}
+}
@@ -0,0 +1,49 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"html"
+	"reflect"
+	"testing"
+)
+
+func TestEmailAddress_Obfuscate(t *testing.T) {
+	tests := []struct {
+		name  string
+		email EmailAddress
+		style ObfuscationStyle
+		want  string
+	}{
+		{"1", EmailAddress{"user", "domain.com"}, ObfuscateWords, "user [at] domain [dot] com"},
+		{"2", EmailAddress{"first.last", "mail.domain.co.uk"}, ObfuscateWords, "first.last [at] mail [dot] domain [dot] co [dot] uk"},
+		{"3", EmailAddress{"us", "d.io"}, ObfuscateHTMLEntities, "&#117;&#115;&#64;&#100;&#46;&#105;&#111;"},
+		{"4", EmailAddress{"User", "Domain.com"}, ObfuscateROT13, "Hfre@Qbznva.pbz"},
+		{"5", EmailAddress{}, ObfuscateWords, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.email.Obfuscate(tt.style); got != tt.want {
+				t.Errorf("EmailAddress.Obfuscate() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_Obfuscate_reversible(t *testing.T) {
+	e := EmailAddress{"first.last+tag", "domain.com"}
+	want := []*EmailAddress{&e}
+	if got := FindObfuscated([]byte("mail "+e.Obfuscate(ObfuscateWords)), false); !reflect.DeepEqual(got, want) {
+		t.Errorf("FindObfuscated() = %v, want %v", got, want)
+	}
+	if got := html.UnescapeString(e.Obfuscate(ObfuscateHTMLEntities)); got != e.String() {
+		t.Errorf("html.UnescapeString() = %v, want %v", got, e)
+	}
+	rot := EmailAddress{"svefg.ynfg+gnt", "qbznva.pbz"}
+	if got := rot.Obfuscate(ObfuscateROT13); got != e.String() {
+		t.Errorf("EmailAddress.Obfuscate() twice = %v, want %v", got, e)
+	}
+}
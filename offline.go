@@ -0,0 +1,125 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// StaticMX is a static dataset of the mail hosts of domains, for validating without DNS and SMTP
+// in air-gapped environments. See WithOffline.
+type StaticMX map[string][]*net.MX
+
+// ReadStaticMX reads a StaticMX from r with one MX record per line, as a domain, a preference and
+// a mail host separated by whitespace:
+//
+//	example.com 10 mx1.example.com
+//	example.com 20 mx2.example.com
+//
+// Empty lines and comments starting with # are skipped. A null MX (the host .) marks a domain that
+// doesn't accept mail.
+func ReadStaticMX(r io.Reader) (StaticMX, error) {
+	mx := make(StaticMX)
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := stripComment(scanner.Text())
+		if line == "" {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) != 3 {
+			return nil, fmt.Errorf("line %d: want domain, preference and host, got %q", n, line)
+		}
+		pref, err := strconv.ParseUint(fields[1], 10, 16)
+		if err != nil {
+			return nil, fmt.Errorf("line %d: invalid preference %q", n, fields[1])
+		}
+		domain := strings.ToLower(strings.TrimSuffix(fields[0], "."))
+		mx[domain] = append(mx[domain], &net.MX{Host: fields[2], Pref: uint16(pref)})
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return mx, nil
+}
+
+// LoadStaticMX reads the StaticMX in the file at path. See ReadStaticMX.
+func LoadStaticMX(path string) (StaticMX, error) {
+	f, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ReadStaticMX(f)
+}
+
+// WithOffline looks up the mail hosts of domains in mx instead of DNS and skips the SMTP probe,
+// so the Verifier works without network access. ValidateHost only fails for domains without mail
+// host in mx, or with a null MX, deterministically. CheckHost, Verify and ValidateBatch report the
+// addresses of the other domains as unverifiable, since their recipient was not probed, and
+// InspectMX and DetectCatchAll fail with an error matching ErrUnverifiable. Use it with
+// WithVerifier to filter dead domains in Find:
+//
+//	v := emailaddress.NewVerifier(emailaddress.WithOffline(mx))
+//	emails := emailaddress.Find(text, true, emailaddress.WithVerifier(v))
+func WithOffline(mx StaticMX) Option {
+	return func(v *Verifier) {
+		v.resolver = staticResolver(mx)
+		v.offline = true
+	}
+}
+
+// WithVerifier validates the hosts of the addresses found by Find and its variants with v instead
+// of the default Verifier, when validateHost is true.
+func WithVerifier(v *Verifier) ParseOption {
+	return func(o *parseOptions) {
+		o.hostValidator = v
+	}
+}
+
+// validateHost validates the host of e for Find, see WithVerifier.
+func (v *Verifier) validateHost(ctx context.Context, e EmailAddress) error {
+	return v.ValidateHost(ctx, e)
+}
+
+// staticResolver answers MX queries from a StaticMX. Every other name doesn't exist.
+type staticResolver StaticMX
+
+// LookupMX returns the mail hosts of name, none for a null MX.
+func (r staticResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	if mx, ok := r[strings.ToLower(strings.TrimSuffix(name, "."))]; ok {
+		if len(mx) == 1 && mx[0].Host == "." {
+			return nil, nil
+		}
+		return mx, nil
+	}
+	return nil, staticNotFound(name)
+}
+
+func (r staticResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	return nil, staticNotFound(host)
+}
+
+func (r staticResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	return nil, staticNotFound(name)
+}
+
+func (r staticResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	return nil, staticNotFound(addr)
+}
+
+func staticNotFound(name string) error {
+	return &net.DNSError{Err: "no such host in static MX data", Name: name, IsNotFound: true}
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const testStaticMX = `# static MX data
+example.com 10 mx1.example.com
+example.com 20 mx2.example.com
+Example.NET. 0 mx.example.net
+nomail.com 0 .
+`
+
+func TestReadStaticMX(t *testing.T) {
+	tests := []struct {
+		name    string
+		input   string
+		domains int
+		wantErr bool
+	}{
+		{name: "1", input: testStaticMX, domains: 3},
+		{name: "2", input: "", domains: 0},
+		{name: "3", input: "example.com mx.example.com\n", wantErr: true},
+		{name: "4", input: "example.com high mx.example.com\n", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			mx, err := ReadStaticMX(strings.NewReader(tt.input))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("ReadStaticMX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if len(mx) != tt.domains {
+				t.Errorf("ReadStaticMX() = %d domains, want %d", len(mx), tt.domains)
+			}
+		})
+	}
+}
+
+func TestWithOffline(t *testing.T) {
+	mx, err := ReadStaticMX(strings.NewReader(testStaticMX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier(WithOffline(mx))
+
+	tests := []struct {
+		name    string
+		domain  string
+		wantErr bool
+		status  HostStatus
+	}{
+		{name: "1", domain: "example.com", status: HostUnverifiable},
+		{name: "2", domain: "example.net", status: HostUnverifiable},
+		{name: "3", domain: "nomail.com", wantErr: true, status: HostInvalid},
+		{name: "4", domain: "unknown.org", wantErr: true, status: HostInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := EmailAddress{"info", tt.domain}
+			if err := v.ValidateHost(context.Background(), e); (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.ValidateHost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			status, err := v.CheckHost(context.Background(), e)
+			if status != tt.status {
+				t.Errorf("Verifier.CheckHost() = %v, %v, want %v", status, err, tt.status)
+			}
+			if status == HostUnverifiable && !errors.Is(err, ErrUnverifiable) {
+				t.Errorf("Verifier.CheckHost() error = %v, want ErrUnverifiable", err)
+			}
+		})
+	}
+}
+
+func TestWithOffline_noProbe(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	v := NewVerifier(WithPort(s.port()), WithOffline(StaticMX{"example.com": {{Host: s.host(), Pref: 10}}}))
+
+	if caps, err := v.InspectMX(context.Background(), "example.com"); caps != nil || !errors.Is(err, ErrUnverifiable) {
+		t.Errorf("Verifier.InspectMX() = %v, %v, want ErrUnverifiable", caps, err)
+	}
+	if got, err := v.DetectCatchAll(context.Background(), "example.com"); got || !errors.Is(err, ErrUnverifiable) {
+		t.Errorf("Verifier.DetectCatchAll() = %v, %v, want ErrUnverifiable", got, err)
+	}
+	if got := s.remoteAddrs(); len(got) != 0 {
+		t.Errorf("offline Verifier connected %d times", len(got))
+	}
+}
+
+func TestWithVerifier(t *testing.T) {
+	mx, err := ReadStaticMX(strings.NewReader(testStaticMX))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v := NewVerifier(WithOffline(mx))
+	text := []byte("info@example.com, info@nomail.com and sales@unknown.org")
+
+	for _, find := range []func([]byte, bool, ...ParseOption) []*EmailAddress{Find, FindWithRFC5322, FindWithIcannSuffix} {
+		emails := find(text, true, WithVerifier(v))
+		if len(emails) != 1 || emails[0].String() != "info@example.com" {
+			t.Errorf("Find() = %v, want [info@example.com]", emails)
+		}
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// ErrParked is returned when the mail host of a domain belongs to a domain parking service.
+var ErrParked = errors.New("domain is parked")
+
+// defaultParkingHosts are the domains of the mail hosts of well known domain parking services and
+// registrar placeholders. Parkers often accept mail for any recipient, so a probe says nothing
+// about the address.
+var defaultParkingHosts = NewDomainList([]string{
+	"above.com", "afternic.com", "bodis.com", "dan.com", "hugedomains.com", "namebrightdns.com",
+	"parkingcrew.net", "parklogic.com", "sedoparking.com", "uniregistrymarket.link",
+})
+
+// WithParkingList replaces the list of domain parking services. A domain is parked if its mail
+// host, or one of the parent domains of the host, is in hosts, or if the domain has no MX records
+// and its address is in one of the networks. By default a list of well known parking services is
+// used. WithParkingList(nil, nil) disables parked domain detection.
+func WithParkingList(hosts DomainList, networks []*net.IPNet) Option {
+	return func(v *Verifier) {
+		v.parkingHosts = hosts
+		v.parkingNetworks = networks
+	}
+}
+
+// IsParked reports whether the mail host of domain belongs to a domain parking service.
+func (v *Verifier) IsParked(ctx context.Context, domain string) (bool, error) {
+	host, err := v.lookupHost(ctx, domain)
+	if err != nil {
+		return false, err
+	}
+	return v.parked(host), nil
+}
+
+// checkParked returns an error wrapping ErrParked if host belongs to a domain parking service.
+func (v *Verifier) checkParked(domain, host string) error {
+	if v.parked(host) {
+		return newError(CodeParked, fmt.Errorf("%w: %s is served by %s", ErrParked, domain, host))
+	}
+	return nil
+}
+
+// parked reports whether host, a host name or IP address as returned by lookupHost, belongs to
+// a domain parking service.
+func (v *Verifier) parked(host string) bool {
+	if ip := net.ParseIP(host); ip != nil {
+		for _, n := range v.parkingNetworks {
+			if n.Contains(ip) {
+				return true
+			}
+		}
+		return false
+	}
+	return listed(v.parkingHosts, strings.TrimSuffix(strings.ToLower(host), "."))
+}
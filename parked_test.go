@@ -0,0 +1,70 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"testing"
+)
+
+func TestVerifier_IsParked(t *testing.T) {
+	_, network, _ := net.ParseCIDR("192.0.2.0/24")
+	r := &testResolver{
+		mx: map[string][]*net.MX{
+			"parked.com":  {{Host: "mx156.sedoparking.com.", Pref: 10}},
+			"example.com": {{Host: "mx.example.com.", Pref: 10}},
+		},
+		ips: map[string][]net.IP{
+			"placeholder.com": {net.ParseIP("192.0.2.10")},
+			"noparking.com":   {net.ParseIP("198.51.100.1")},
+		},
+	}
+	tests := []struct {
+		name    string
+		domain  string
+		opts    []Option
+		want    bool
+		wantErr bool
+	}{
+		{"1", "parked.com", nil, true, false},
+		{"2", "example.com", nil, false, false},
+		{"3", "placeholder.com", nil, false, false},
+		{"4", "placeholder.com", []Option{WithParkingList(nil, []*net.IPNet{network})}, true, false},
+		{"5", "noparking.com", []Option{WithParkingList(nil, []*net.IPNet{network})}, false, false},
+		{"6", "parked.com", []Option{WithParkingList(nil, nil)}, false, false},
+		{"7", "example.com", []Option{WithParkingList(NewDomainList([]string{"example.com"}), nil)}, true, false},
+		{"8", "example.org", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(tt.opts...)
+			v.resolver = r
+			got, err := v.IsParked(context.Background(), tt.domain)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.IsParked() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if got != tt.want {
+				t.Errorf("Verifier.IsParked() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_CheckHost_parked(t *testing.T) {
+	v := NewVerifier()
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"parked.com": {{Host: "mx.parkingcrew.net", Pref: 10}}}}
+	status, err := v.CheckHost(context.Background(), EmailAddress{"info", "parked.com"})
+	if status != HostInvalid || !errors.Is(err, ErrParked) || ErrorCode(err) != CodeParked {
+		t.Errorf("Verifier.CheckHost() = %v, %v, want %v, %v", status, err, HostInvalid, ErrParked)
+	}
+	if err := v.ValidateHost(context.Background(), EmailAddress{"info", "parked.com"}); !errors.Is(err, ErrParked) {
+		t.Errorf("Verifier.ValidateHost() error = %v, want %v", err, ErrParked)
+	}
+}
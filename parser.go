@@ -0,0 +1,203 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strings"
+)
+
+// WithRegexParser validates addresses with the RFC 5322 regular expression that Parse used before
+// it had a parser of its own. The regular expression accepts a few addresses the parser rejects,
+// such as domains that join a host name and an address literal (foo@bar.com[192.0.2.1]), and
+// rejects tagged address literals such as foo@[IPv6:2001:db8::1]. It is also a lot slower.
+func WithRegexParser() ParseOption {
+	return func(o *parseOptions) {
+		o.regexParser = true
+	}
+}
+
+// validate checks the syntax of email.
+func (o parseOptions) validate(email string) error {
+	if o.regexParser {
+		if !validRfc5322Regexp.MatchString(email) {
+			return formatError(email)
+		}
+		return nil
+	}
+	return checkAddress(email)
+}
+
+// checkAddress validates email against the addr-spec of RFC 5322 without comments and folding
+// white space. The local part is a dot-atom or a quoted string, the domain is a host name of at
+// least two labels or an address literal of RFC 5321 section 4.1.3. The error has a code telling
+// which part is invalid.
+func checkAddress(email string) error {
+	var code Code
+	switch i := strings.LastIndexByte(email, '@'); {
+	case i < 0:
+		code = CodeInvalidFormat
+	case !validLocalPart(email[:i]):
+		code = CodeInvalidLocalPart
+	case !validDomain(email[i+1:]):
+		code = CodeInvalidDomain
+	default:
+		return nil
+	}
+	return newError(code, fmt.Errorf("format is incorrect for %s", email))
+}
+
+func validLocalPart(s string) bool {
+	if strings.HasPrefix(s, `"`) {
+		return validQuotedString(s)
+	}
+	return validDotAtom(s)
+}
+
+// validDotAtom reports whether s is one or more runs of atext separated by single dots.
+func validDotAtom(s string) bool {
+	dot := true // at the start or after a dot, where atext must follow
+	for i := 0; i < len(s); i++ {
+		switch c := s[i]; {
+		case c == '.':
+			if dot {
+				return false
+			}
+			dot = true
+		case isAtext(c):
+			dot = false
+		default:
+			return false
+		}
+	}
+	return !dot
+}
+
+// validQuotedString reports whether s is a quoted string of qtext and quoted-pairs.
+func validQuotedString(s string) bool {
+	if len(s) < 2 || s[len(s)-1] != '"' {
+		return false
+	}
+	for i := 1; i < len(s)-1; i++ {
+		switch c := s[i]; {
+		case c == '\\':
+			if i++; i == len(s)-1 || !isQuotedPair(s[i]) {
+				return false
+			}
+		case !isQtext(c):
+			return false
+		}
+	}
+	return true
+}
+
+func validDomain(s string) bool {
+	if strings.HasPrefix(s, "[") {
+		return validAddressLiteral(s)
+	}
+	return validHostname(s)
+}
+
+// validHostname reports whether s is two or more labels of letters, digits and hyphens separated
+// by dots, where labels start and end with a letter or digit.
+func validHostname(s string) bool {
+	labels, start := 1, 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] != '.' {
+			if !isAlphanumeric(s[i]) && s[i] != '-' {
+				return false
+			}
+			continue
+		}
+		if i == start || s[start] == '-' || s[i-1] == '-' {
+			return false
+		}
+		if i < len(s) {
+			labels++
+		}
+		start = i + 1
+	}
+	return labels >= 2
+}
+
+// validAddressLiteral reports whether s is an IPv4 address or a tagged address, such as
+// IPv6:2001:db8::1, in brackets.
+func validAddressLiteral(s string) bool {
+	if len(s) < 2 || s[len(s)-1] != ']' {
+		return false
+	}
+	s = s[1 : len(s)-1]
+	if validIPv4(s) {
+		return true
+	}
+	i := strings.IndexByte(s, ':')
+	if i <= 0 || s[i-1] == '-' || i == len(s)-1 {
+		return false
+	}
+	for j := 0; j < i; j++ {
+		if !isAlphanumeric(s[j]) && s[j] != '-' {
+			return false
+		}
+	}
+	for j := i + 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '\\':
+			if j++; j == len(s) || !isQuotedPair(s[j]) {
+				return false
+			}
+		case !isDcontent(c):
+			return false
+		}
+	}
+	return true
+}
+
+// validIPv4 reports whether s is an IPv4 address in dotted decimal form without leading zeros.
+func validIPv4(s string) bool {
+	parts, n, digits := 0, 0, 0
+	for i := 0; i <= len(s); i++ {
+		if i < len(s) && s[i] >= '0' && s[i] <= '9' {
+			if digits == 1 && n == 0 {
+				return false
+			}
+			n, digits = n*10+int(s[i]-'0'), digits+1
+			if n > 255 {
+				return false
+			}
+			continue
+		}
+		if digits == 0 || i < len(s) && s[i] != '.' {
+			return false
+		}
+		parts, n, digits = parts+1, 0, 0
+	}
+	return parts == 4
+}
+
+// isAtext reports whether c is an atext character of RFC 5322 section 3.2.3.
+func isAtext(c byte) bool {
+	return isAlphanumeric(c) || strings.IndexByte("!#$%&'*+/=?^_`{|}~-", c) >= 0
+}
+
+// isQtext reports whether c may appear unescaped in a quoted string, the control characters of
+// obs-qtext included.
+func isQtext(c byte) bool {
+	return c >= 0x01 && c <= 0x08 || c == 0x0b || c == 0x0c || c >= 0x0e && c <= 0x1f ||
+		c == 0x21 || c >= 0x23 && c <= 0x5b || c >= 0x5d && c <= 0x7f
+}
+
+// isQuotedPair reports whether c may follow a backslash, the control characters of obs-qp
+// included.
+func isQuotedPair(c byte) bool {
+	return c >= 0x01 && c <= 0x09 || c == 0x0b || c == 0x0c || c >= 0x0e && c <= 0x7f
+}
+
+// isDcontent reports whether c may appear in a general address literal, the control characters of
+// obs-dtext included.
+func isDcontent(c byte) bool {
+	return c >= 0x01 && c <= 0x08 || c == 0x0b || c == 0x0c || c >= 0x0e && c <= 0x1f ||
+		c >= 0x21 && c <= 0x5a || c >= 0x5e && c <= 0x7f
+}
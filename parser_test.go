@@ -0,0 +1,153 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+	"testing"
+)
+
+func Test_checkAddress(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  Code
+	}{
+		{"1", "foo@bar.com", ""},
+		{"2", "Foo.Bar+baz!#$%&'*/=?^_`{|}~-@sub.bar-baz.co.uk", ""},
+		{"3", `"foo bar"@bar.com`, CodeInvalidLocalPart},
+		{"4", `"foo@bar\"baz\\"@bar.com`, ""},
+		{"5", `"foo\"@bar.com`, CodeInvalidLocalPart},
+		{"6", `""@bar.com`, ""},
+		{"7", `foo."bar"@bar.com`, CodeInvalidLocalPart},
+		{"8", "foo..bar@bar.com", CodeInvalidLocalPart},
+		{"9", ".foo@bar.com", CodeInvalidLocalPart},
+		{"10", "foo.@bar.com", CodeInvalidLocalPart},
+		{"11", "@bar.com", CodeInvalidLocalPart},
+		{"12", "foo", CodeInvalidFormat},
+		{"13", "foo@bar", CodeInvalidDomain},
+		{"14", "foo@bar.com.", CodeInvalidDomain},
+		{"15", "foo@bar-.com", CodeInvalidDomain},
+		{"16", "foo@bar..com", CodeInvalidDomain},
+		{"17", "foo@b_r.com", CodeInvalidDomain},
+		{"18", "foo@", CodeInvalidDomain},
+		{"19", "foo@[192.0.2.1]", ""},
+		{"20", "foo@[255.255.255.255]", ""},
+		{"21", "foo@[256.0.0.1]", CodeInvalidDomain},
+		{"22", "foo@[192.0.2.01]", CodeInvalidDomain},
+		{"23", "foo@[192.0.2]", CodeInvalidDomain},
+		{"24", "foo@[IPv6:2001:db8::1]", ""},
+		{"25", "foo@[IPv6-:2001:db8::1]", CodeInvalidDomain},
+		{"26", "foo@[IPv6:]", CodeInvalidDomain},
+		{"27", "foo@[IPv6:2001:db8::[1]]", CodeInvalidDomain},
+		{"28", "foo@bar.com[192.0.2.1]", CodeInvalidDomain},
+		{"29", "foo@[192.0.2.1", CodeInvalidDomain},
+		{"30", "foo@192.0.2.1", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ErrorCode(checkAddress(tt.email)); got != tt.want {
+				t.Errorf("checkAddress() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// Test_checkAddress_regex checks that the parser agrees with the regular expression on
+// combinations of local parts and domains, apart from the address literals they deliberately
+// disagree on.
+func Test_checkAddress_regex(t *testing.T) {
+	locals := []string{
+		"foo", "FOO.bar", "a+b", "-", "!#$%", "foo.", ".foo", "fo..o", `"foo"`, `"fo o"`, `"fo\"o"`,
+		`"fo\o"`, `"foo`, `foo"`, `""`, "fo o", "fo(o)", "fo,o", "f[o]o", "",
+	}
+	domains := []string{
+		"bar.com", "BAR.COM", "b-r.com", "b--r.co.uk", "bar", "bar.", ".bar.com", "bar..com", "-bar.com",
+		"bar-.com", "b_r.com", "123.123.123.123", "[192.0.2.1]", "[300.0.2.1]", "[01.0.2.1]",
+		"[1.2.3]", "[tag:foo bar]", "[:foo]", "[tag-:foo]", "[tag:]", "[192.0.2.1", "",
+	}
+	for _, l := range locals {
+		for _, d := range domains {
+			email := l + "@" + d
+			want := validRfc5322Regexp.MatchString(email) && d != ""
+			if got := checkAddress(email) == nil; got != want {
+				t.Errorf("checkAddress(%q) = %v, the regular expression says %v", email, got, want)
+			}
+		}
+	}
+}
+
+func TestParse_regexParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		email   string
+		wantErr bool
+	}{
+		{"1", "foo@bar.com", false},
+		{"2", "foo@bar.com[192.0.2.1]", false},
+		{"3", "foo@bar", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := Parse(tt.email, WithRegexParser()); (err != nil) != tt.wantErr {
+				t.Errorf("Parse() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+var benchmarkAddresses = []string{
+	"email@domain.com", "firstname+last.name@sub.domain.co.uk", `"email"@domain.com`,
+	"email@[123.123.123.123]", "email..email@domain.com", "plainaddress",
+}
+
+func BenchmarkParse(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, email := range benchmarkAddresses {
+			_, _ = Parse(email)
+		}
+	}
+}
+
+func BenchmarkParse_regexParser(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		for _, email := range benchmarkAddresses {
+			_, _ = Parse(email, WithRegexParser())
+		}
+	}
+}
+
+var benchmarkText = []byte(strings.Repeat("Send me an email at foo@bar.com or foo@domain.fakesuffix, not at !--logo@2x.png. ", 100))
+
+func BenchmarkFindWithRFC5322(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FindWithRFC5322(benchmarkText, false)
+	}
+}
+
+func BenchmarkFindWithRFC5322_regexParser(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FindWithRFC5322(benchmarkText, false, WithRegexParser())
+	}
+}
+
+func BenchmarkFind(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		Find(benchmarkText, false)
+	}
+}
+
+func BenchmarkFindFunc(b *testing.B) {
+	b.ReportAllocs()
+	for i := 0; i < b.N; i++ {
+		FindFunc(benchmarkText, func(m Match) bool { return true })
+	}
+}
@@ -0,0 +1,208 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bufio"
+	"context"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Stage is a step of a Pipeline. Process returns false to drop the address from the pipeline, an
+// error aborts the pipeline.
+type Stage interface {
+	Process(ctx context.Context, e *EmailAddress) (bool, error)
+}
+
+// StageFunc adapts an ordinary function to a Stage.
+type StageFunc func(ctx context.Context, e *EmailAddress) (bool, error)
+
+// Process calls f(ctx, e).
+func (f StageFunc) Process(ctx context.Context, e *EmailAddress) (bool, error) {
+	return f(ctx, e)
+}
+
+// Source provides the input of a Pipeline. Next returns io.EOF when there is no more input.
+type Source interface {
+	Next(ctx context.Context) (string, error)
+}
+
+// Sink receives the addresses that passed every stage of a Pipeline.
+type Sink interface {
+	Put(ctx context.Context, e *EmailAddress) error
+}
+
+// SinkFunc adapts an ordinary function to a Sink.
+type SinkFunc func(ctx context.Context, e *EmailAddress) error
+
+// Put calls f(ctx, e).
+func (f SinkFunc) Put(ctx context.Context, e *EmailAddress) error {
+	return f(ctx, e)
+}
+
+// Pipeline cleans and parses the input of a Source, runs every address through its stages and
+// writes the remaining addresses to a Sink. A typical list hygiene pipeline is:
+//
+//	p := emailaddress.NewPipeline(
+//		emailaddress.DedupeStage(nil),
+//		emailaddress.SuppressStage(unsubscribed),
+//		emailaddress.FilterStage(isWanted),
+//		emailaddress.VerifyStage(verifier, true),
+//	)
+//	err := p.Run(ctx, emailaddress.ReaderSource(file), sink)
+type Pipeline struct {
+	stages []Stage
+}
+
+// NewPipeline returns a pipeline running the stages in the given order.
+func NewPipeline(stages ...Stage) *Pipeline {
+	return &Pipeline{stages: stages}
+}
+
+// Run reads all input of src until io.EOF. Every input is cleaned with Clean and parsed, input
+// that is not a valid email address is dropped. It stops at the first error of the source, a stage or
+// the sink, or when ctx is done.
+func (p *Pipeline) Run(ctx context.Context, src Source, sink Sink) error {
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		s, err := src.Next(ctx)
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		e, err := Parse(Clean(s))
+		if err != nil {
+			continue
+		}
+		keep, err := p.process(ctx, e)
+		if err != nil {
+			return err
+		}
+		if keep {
+			if err := sink.Put(ctx, e); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (p *Pipeline) process(ctx context.Context, e *EmailAddress) (bool, error) {
+	for _, s := range p.stages {
+		if keep, err := s.Process(ctx, e); !keep || err != nil {
+			return false, err
+		}
+	}
+	return true, nil
+}
+
+// Clean strips the noise commonly surrounding email addresses in lists, such as whitespace,
+// quotes, angle brackets, a mailto: prefix and trailing punctuation, and lowercases the domain.
+func Clean(s string) string {
+	for prev := ""; s != prev; {
+		prev = s
+		s = strings.TrimSpace(s)
+		s = strings.Trim(s, "'<>")
+		s = strings.TrimRight(s, ".,;:")
+		if len(s) > 7 && strings.EqualFold(s[:7], "mailto:") {
+			s = s[7:]
+		}
+	}
+	if strings.Count(s, "\"") == 2 && strings.HasPrefix(s, "\"") && strings.HasSuffix(s, "\"") {
+		s = s[1 : len(s)-1]
+	}
+	s = strings.TrimSpace(s)
+	if i := strings.LastIndexByte(s, '@'); i >= 0 {
+		s = s[:i+1] + strings.ToLower(s[i+1:])
+	}
+	return s
+}
+
+// DedupeStage drops addresses that were seen before. Two addresses are duplicates if key returns
+// the same value for them, a nil key compares their Canonical form, so Foo+news@gmail.com and
+// foo@gmail.com are duplicates.
+func DedupeStage(key func(*EmailAddress) string) Stage {
+	if key == nil {
+		key = func(e *EmailAddress) string {
+			return e.Canonical().String()
+		}
+	}
+	var mu sync.Mutex
+	seen := make(map[string]struct{})
+	return StageFunc(func(ctx context.Context, e *EmailAddress) (bool, error) {
+		k := key(e)
+		mu.Lock()
+		defer mu.Unlock()
+		if _, ok := seen[k]; ok {
+			return false, nil
+		}
+		seen[k] = struct{}{}
+		return true, nil
+	})
+}
+
+// SuppressStage drops the addresses in the suppression list, compared case-insensitively.
+func SuppressStage(suppressed []string) Stage {
+	set := make(map[string]struct{}, len(suppressed))
+	for _, s := range suppressed {
+		set[strings.ToLower(Clean(s))] = struct{}{}
+	}
+	return StageFunc(func(ctx context.Context, e *EmailAddress) (bool, error) {
+		_, ok := set[strings.ToLower(e.String())]
+		return !ok, nil
+	})
+}
+
+// FilterStage drops the addresses for which keep returns false.
+func FilterStage(keep func(*EmailAddress) bool) Stage {
+	return StageFunc(func(ctx context.Context, e *EmailAddress) (bool, error) {
+		return keep(e), nil
+	})
+}
+
+// SliceSource returns a Source reading from a slice.
+func SliceSource(input []string) Source {
+	return &sliceSource{input: input}
+}
+
+type sliceSource struct {
+	input []string
+}
+
+func (s *sliceSource) Next(ctx context.Context) (string, error) {
+	if len(s.input) == 0 {
+		return "", io.EOF
+	}
+	next := s.input[0]
+	s.input = s.input[1:]
+	return next, nil
+}
+
+// ReaderSource returns a Source reading one address per line from r, empty lines are skipped.
+func ReaderSource(r io.Reader) Source {
+	return &readerSource{scanner: bufio.NewScanner(r)}
+}
+
+type readerSource struct {
+	scanner *bufio.Scanner
+}
+
+func (s *readerSource) Next(ctx context.Context) (string, error) {
+	for s.scanner.Scan() {
+		if line := strings.TrimSpace(s.scanner.Text()); line != "" {
+			return line, nil
+		}
+	}
+	if err := s.scanner.Err(); err != nil {
+		return "", err
+	}
+	return "", io.EOF
+}
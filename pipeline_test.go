@@ -0,0 +1,52 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"context"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestClean(t *testing.T) {
+	tests := []struct {
+		name string
+		s    string
+		want string
+	}{
+		{"1", "foo@bar.com", "foo@bar.com"},
+		{"2", "  Foo@Bar.COM \t", "Foo@bar.com"},
+		{"3", "<foo@bar.com>", "foo@bar.com"},
+		{"4", "mailto:foo@bar.com", "foo@bar.com"},
+		{"5", "'foo@bar.com',", "foo@bar.com"},
+		{"6", "\"foo@bar.com\";", "foo@bar.com"},
+		{"7", "\"foo bar\"@bar.com", "\"foo bar\"@bar.com"},
+		{"8", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Clean(tt.s); got != tt.want {
+				t.Errorf("Clean() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestReaderSource(t *testing.T) {
+	src := ReaderSource(strings.NewReader("foo@bar.com\n\n  bar@bar.com\r\n"))
+	var got []string
+	for {
+		s, err := src.Next(context.Background())
+		if err != nil {
+			break
+		}
+		got = append(got, s)
+	}
+	if want := []string{"foo@bar.com", "bar@bar.com"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("ReaderSource() = %v, want %v", got, want)
+	}
+}
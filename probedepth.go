@@ -0,0 +1,68 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import "fmt"
+
+// ProbeDepth is the last step of the SMTP conversation a probe gets to. Stopping earlier issues
+// fewer commands to the mail host, at the price of knowing less about the recipient.
+type ProbeDepth int
+
+const (
+	// ProbeConnectOnly connects to the mail host and reads its greeting.
+	ProbeConnectOnly ProbeDepth = iota + 1
+
+	// ProbeHelo greets the mail host with EHLO, and STARTTLS when offered.
+	ProbeHelo
+
+	// ProbeMailFrom starts a mail transaction with MAIL FROM, which checks that the host accepts
+	// the sender.
+	ProbeMailFrom
+
+	// ProbeRcpt sends RCPT TO for the recipient, which checks the mailbox exists. It is the
+	// default.
+	ProbeRcpt
+)
+
+func (d ProbeDepth) String() string {
+	switch d {
+	case ProbeConnectOnly:
+		return "connect"
+	case ProbeHelo:
+		return "helo"
+	case ProbeMailFrom:
+		return "mail-from"
+	case ProbeRcpt:
+		return "rcpt"
+	default:
+		return fmt.Sprintf("ProbeDepth(%d)", int(d))
+	}
+}
+
+// WithProbeDepth sets the last step of the SMTP conversation, ie. ProbeMailFrom to never issue
+// RCPT TO against the mail host. ValidateHost succeeds when every step up to depth succeeds.
+// CheckHost, Verify and ValidateBatch don't know whether the recipient exists before ProbeRcpt,
+// so they report such addresses as unverifiable, with an error matching ErrUnverifiable, and
+// skip the catch-all detection. DetectCatchAll returns an error matching ErrUnverifiable.
+func WithProbeDepth(depth ProbeDepth) Option {
+	return func(v *Verifier) {
+		v.probeDepth = depth
+	}
+}
+
+// notProbed returns the error of a probe that succeeded without probing the recipient, or nil if
+// the recipient was probed.
+func (v *Verifier) notProbed() error {
+	if v.offline {
+		return fmt.Errorf("recipient not probed, offline: %w", ErrUnverifiable)
+	}
+	if v.probeDepth >= ProbeRcpt {
+		return nil
+	}
+	return fmt.Errorf("recipient not probed, probe depth is %v: %w", v.probeDepth, ErrUnverifiable)
+}
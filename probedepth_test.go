@@ -0,0 +1,115 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestWithProbeDepth(t *testing.T) {
+	tests := []struct {
+		name   string
+		depth  ProbeDepth
+		mail   string
+		want   []string
+		status HostStatus
+		code   Code
+	}{
+		{"1", ProbeConnectOnly, "", []string{"QUIT"}, HostUnverifiable, ""},
+		{"2", ProbeHelo, "", []string{"EHLO example.com", "QUIT"}, HostUnverifiable, ""},
+		{"3", ProbeMailFrom, "", []string{"EHLO example.com", "MAIL FROM:<hello@example.com> BODY=8BITMIME", "RSET", "QUIT"}, HostUnverifiable, ""},
+		{"4", ProbeMailFrom, "550 sender rejected", []string{"EHLO example.com", "MAIL FROM:<hello@example.com> BODY=8BITMIME"}, HostUnverifiable, CodeSMTPRejected},
+		{"5", ProbeRcpt, "", []string{"EHLO example.com", "MAIL FROM:<hello@example.com> BODY=8BITMIME", "RCPT TO:<info@example.com>", "RSET", "QUIT"}, HostVerified, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0")
+			if tt.mail != "" {
+				s.mail = func(string) string { return tt.mail }
+			}
+			v := NewVerifier(WithPort(s.port()), WithProbeDepth(tt.depth))
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+			e := EmailAddress{"info", "example.com"}
+
+			err := v.ValidateHost(context.Background(), e)
+			if ErrorCode(err) != tt.code || (err == nil) != (tt.code == "") {
+				t.Errorf("Verifier.ValidateHost() error = %v, want code %v", err, tt.code)
+			}
+			if got := s.commands(); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Verifier.ValidateHost() sent %q, want %q", got, tt.want)
+			}
+
+			status, err := v.CheckHost(context.Background(), e)
+			if status != tt.status {
+				t.Errorf("Verifier.CheckHost() = %v, %v, want %v", status, err, tt.status)
+			}
+			if tt.code == "" && tt.depth < ProbeRcpt && !errors.Is(err, ErrUnverifiable) {
+				t.Errorf("Verifier.CheckHost() error = %v, want ErrUnverifiable", err)
+			}
+		})
+	}
+}
+
+func TestWithProbeDepth_DetectCatchAll(t *testing.T) {
+	tests := []struct {
+		name    string
+		depth   ProbeDepth
+		wantErr bool
+	}{
+		{"1", ProbeConnectOnly, true},
+		{"2", ProbeHelo, true},
+		{"3", ProbeMailFrom, true},
+		{"4", ProbeRcpt, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0")
+			s.rcpt = func(string) string { return "550 5.1.1 no such user" }
+			v := NewVerifier(WithPort(s.port()), WithProbeDepth(tt.depth), WithCache(time.Minute))
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+
+			got, err := v.DetectCatchAll(context.Background(), "example.com")
+			if got || (err != nil) != tt.wantErr || (tt.wantErr && !errors.Is(err, ErrUnverifiable)) {
+				t.Errorf("Verifier.DetectCatchAll() = %v, %v, want ErrUnverifiable %v", got, err, tt.wantErr)
+			}
+			if _, ok := v.catchAllCache.get("example.com"); ok != !tt.wantErr {
+				t.Errorf("Verifier.DetectCatchAll() cached = %v, want %v", ok, !tt.wantErr)
+			}
+			if got := len(s.remoteAddrs()); (got > 0) != !tt.wantErr {
+				t.Errorf("Verifier.DetectCatchAll() made %d connections", got)
+			}
+		})
+	}
+}
+
+func TestWithProbeDepth_Verify(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	v := NewVerifier(WithPort(s.port()), WithProbeDepth(ProbeMailFrom))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+
+	got := v.Verify(context.Background(), "info@example.com")
+	if got.Verdict != VerdictUnknown || !got.SMTPConnected || got.RecipientAccepted || got.CatchAll || !errors.Is(got.Err, ErrUnverifiable) {
+		t.Errorf("Verifier.Verify() = %+v, want an unknown verdict", got)
+	}
+	results := v.ValidateBatch(context.Background(), []*EmailAddress{{"info", "example.com"}, {"sales", "example.com"}})
+	for _, r := range results {
+		if r.Status != HostUnverifiable || !errors.Is(r.Err, ErrUnverifiable) {
+			t.Errorf("Verifier.ValidateBatch() = %v, %v, want unverifiable", r.Status, r.Err)
+		}
+	}
+	for _, cmd := range s.commands() {
+		if len(cmd) >= 4 && cmd[:4] == "RCPT" {
+			t.Errorf("Verifier sent %q with ProbeMailFrom", cmd)
+		}
+	}
+}
@@ -0,0 +1,126 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"net/netip"
+	"strings"
+)
+
+// Provider is a mail provider hosting the mailboxes of a domain, see IdentifyProvider.
+type Provider int
+
+const (
+	// ProviderUnknown indicates that the mail hosts belong to no known provider.
+	ProviderUnknown Provider = iota
+
+	// ProviderSelfHosted indicates that the mail hosts are in the domain itself, or that the
+	// domain has no MX records and receives mail on its own address.
+	ProviderSelfHosted
+
+	ProviderGoogle     // Google Workspace and Gmail
+	ProviderMicrosoft  // Microsoft 365 and Outlook.com
+	ProviderZoho       // Zoho Mail
+	ProviderProton     // Proton Mail
+	ProviderFastmail   // Fastmail
+	ProviderYahoo      // Yahoo Mail and AOL
+	ProviderICloud     // iCloud Mail
+	ProviderYandex     // Yandex Mail
+	ProviderMimecast   // Mimecast, a filtering gateway
+	ProviderProofpoint // Proofpoint, a filtering gateway
+)
+
+func (p Provider) String() string {
+	switch p {
+	case ProviderSelfHosted:
+		return "self-hosted"
+	case ProviderGoogle:
+		return "google"
+	case ProviderMicrosoft:
+		return "microsoft"
+	case ProviderZoho:
+		return "zoho"
+	case ProviderProton:
+		return "proton"
+	case ProviderFastmail:
+		return "fastmail"
+	case ProviderYahoo:
+		return "yahoo"
+	case ProviderICloud:
+		return "icloud"
+	case ProviderYandex:
+		return "yandex"
+	case ProviderMimecast:
+		return "mimecast"
+	case ProviderProofpoint:
+		return "proofpoint"
+	default:
+		return "unknown"
+	}
+}
+
+// providerHosts maps the domains of the mail hosts of known providers to the provider, a mail host
+// matches a domain or one of its subdomains.
+var providerHosts = map[string]Provider{
+	"google.com":             ProviderGoogle,
+	"googlemail.com":         ProviderGoogle,
+	"protection.outlook.com": ProviderMicrosoft,
+	"outlook.com":            ProviderMicrosoft,
+	"hotmail.com":            ProviderMicrosoft,
+	"zoho.com":               ProviderZoho,
+	"zoho.eu":                ProviderZoho,
+	"zoho.in":                ProviderZoho,
+	"zohomail.com":           ProviderZoho,
+	"protonmail.ch":          ProviderProton,
+	"proton.me":              ProviderProton,
+	"messagingengine.com":    ProviderFastmail,
+	"fastmail.com":           ProviderFastmail,
+	"yahoodns.net":           ProviderYahoo,
+	"icloud.com":             ProviderICloud,
+	"yandex.net":             ProviderYandex,
+	"yandex.ru":              ProviderYandex,
+	"mimecast.com":           ProviderMimecast,
+	"pphosted.com":           ProviderProofpoint,
+	"ppe-hosted.com":         ProviderProofpoint,
+}
+
+// ProviderForHost returns the provider a mail host belongs to, ie. ProviderGoogle for
+// aspmx.l.google.com, or ProviderUnknown. It never reports ProviderSelfHosted, which depends on
+// the domain, see IdentifyProvider.
+func ProviderForHost(host string) Provider {
+	host = strings.TrimSuffix(strings.ToLower(host), ".")
+	if p, ok := providerHosts[host]; ok {
+		return p
+	}
+	for i := strings.IndexByte(host, '.'); i >= 0; i = strings.IndexByte(host, '.') {
+		host = host[i+1:]
+		if p, ok := providerHosts[host]; ok {
+			return p
+		}
+	}
+	return ProviderUnknown
+}
+
+// identifyProvider returns the provider of the mail hosts of domain, in order of preference.
+func identifyProvider(domain string, hosts []string) Provider {
+	for _, h := range hosts {
+		if p := ProviderForHost(h); p != ProviderUnknown {
+			return p
+		}
+	}
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	own, err := effectiveTLDPlusOne(domain)
+	if err != nil {
+		own = domain
+	}
+	for _, h := range hosts {
+		h = strings.TrimSuffix(strings.ToLower(h), ".")
+		if _, err := netip.ParseAddr(h); err == nil || h == own || strings.HasSuffix(h, "."+own) {
+			return ProviderSelfHosted
+		}
+	}
+	return ProviderUnknown
+}
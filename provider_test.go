@@ -0,0 +1,36 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "testing"
+
+func TestProviderForHost(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want Provider
+	}{
+		{"1", "aspmx.l.google.com.", ProviderGoogle},
+		{"2", "Example-com.mail.protection.outlook.com", ProviderMicrosoft},
+		{"3", "mx.zoho.eu", ProviderZoho},
+		{"4", "mail.protonmail.ch", ProviderProton},
+		{"5", "in1-smtp.messagingengine.com", ProviderFastmail},
+		{"6", "mta5.am0.yahoodns.net", ProviderYahoo},
+		{"7", "mx01.mail.icloud.com", ProviderICloud},
+		{"8", "mx.yandex.net", ProviderYandex},
+		{"9", "eu-smtp-inbound-1.mimecast.com", ProviderMimecast},
+		{"10", "mx0a-00123.pphosted.com", ProviderProofpoint},
+		{"11", "mx.example.com", ProviderUnknown},
+		{"12", "notgoogle.com", ProviderUnknown},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ProviderForHost(tt.host); got != tt.want {
+				t.Errorf("ProviderForHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
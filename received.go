@@ -0,0 +1,166 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"io"
+	"net/mail"
+	"net/netip"
+	"strings"
+	"time"
+)
+
+// ReceivedHop is a parsed Received header (RFC 5321 section 4.4), a single hop of the path a
+// message took.
+type ReceivedHop struct {
+	// From is the name the sending host identified itself with, ie. mail.example.com.
+	From string
+
+	// FromIP is the address the sending host connected from, as recorded by the receiving host.
+	// It is the zero Addr if the header does not include it.
+	FromIP netip.Addr
+
+	// By is the name of the receiving host.
+	By string
+
+	// With is the protocol, ie. SMTP or ESMTPS.
+	With string
+
+	// ID is the identifier the receiving host assigned to the message.
+	ID string
+
+	// For is the envelope recipient, nil if the header names none or it is not a valid email
+	// address, in which case Err describes why.
+	For *EmailAddress
+	Err error
+
+	// Date is when the receiving host received the message, the zero time if it is missing or
+	// malformed.
+	Date time.Time
+
+	// Raw is the unparsed value of the header.
+	Raw string
+}
+
+// ParseReceived reads a raw message and parses its Received headers. The hops are returned in the
+// order the message travelled, so the first hop is the host closest to the origin.
+func ParseReceived(r io.Reader) ([]ReceivedHop, error) {
+	msg, err := mail.ReadMessage(r)
+	if err != nil {
+		return nil, err
+	}
+	headers := msg.Header["Received"]
+	hops := make([]ReceivedHop, len(headers))
+	for i, h := range headers {
+		hops[len(headers)-1-i] = ParseReceivedHeader(h)
+	}
+	return hops, nil
+}
+
+// ParseReceivedHeader parses the value of a single Received header. Unknown clauses are ignored,
+// so it never fails; missing clauses are left empty.
+func ParseReceivedHeader(value string) ReceivedHop {
+	h := ReceivedHop{Raw: value}
+	if i := strings.LastIndexByte(value, ';'); i >= 0 {
+		if t, err := mail.ParseDate(strings.TrimSpace(value[i+1:])); err == nil {
+			h.Date = t
+		}
+		value = value[:i]
+	}
+
+	var key, clause string
+	for _, tok := range receivedTokens(value) {
+		if strings.HasPrefix(tok, "(") {
+			if clause == "from" && !h.FromIP.IsValid() {
+				h.FromIP = bracketedIP(tok)
+			}
+			continue
+		}
+		if key == "" {
+			if k := strings.ToLower(tok); receivedClauses[k] {
+				key, clause = k, k
+			}
+			continue
+		}
+		switch key {
+		case "from":
+			h.From = tok
+			if !h.FromIP.IsValid() {
+				h.FromIP = bracketedIP(tok)
+			}
+		case "by":
+			h.By = tok
+		case "with":
+			h.With = tok
+		case "id":
+			h.ID = tok
+		case "for":
+			h.For, h.Err = Parse(strings.Trim(tok, "<>"))
+		}
+		key = ""
+	}
+	return h
+}
+
+// receivedClauses are the keywords of the clauses of a Received header.
+var receivedClauses = map[string]bool{"from": true, "by": true, "via": true, "with": true, "id": true, "for": true}
+
+// receivedTokens splits a Received header into words and (possibly nested) comments, comments
+// keep their parentheses.
+func receivedTokens(s string) []string {
+	var tokens []string
+	for i := 0; i < len(s); {
+		switch s[i] {
+		case ' ', '\t', '\r', '\n':
+			i++
+		case '(':
+			depth, j := 0, i
+			for ; j < len(s); j++ {
+				if s[j] == '\\' {
+					j++
+				} else if s[j] == '(' {
+					depth++
+				} else if s[j] == ')' {
+					if depth--; depth == 0 {
+						break
+					}
+				}
+			}
+			if j >= len(s) {
+				j = len(s) - 1
+			}
+			tokens = append(tokens, s[i:j+1])
+			i = j + 1
+		default:
+			j := i
+			for j < len(s) && !strings.ContainsRune(" \t\r\n(", rune(s[j])) {
+				j++
+			}
+			tokens = append(tokens, s[i:j])
+			i = j
+		}
+	}
+	return tokens
+}
+
+// bracketedIP returns the first IP address between brackets in s, ie. [192.0.2.1] or
+// [IPv6:2001:db8::1].
+func bracketedIP(s string) netip.Addr {
+	for {
+		i := strings.IndexByte(s, '[')
+		if i < 0 {
+			return netip.Addr{}
+		}
+		j := strings.IndexByte(s[i:], ']')
+		if j < 0 {
+			return netip.Addr{}
+		}
+		if ip, err := netip.ParseAddr(unbracketHost(s[i : i+j+1])); err == nil {
+			return ip
+		}
+		s = s[i+j+1:]
+	}
+}
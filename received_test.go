@@ -0,0 +1,72 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"net/netip"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseReceived(t *testing.T) {
+	msg := "Received: from mx.example.org (mx.example.org [198.51.100.7])\r\n" +
+		"\tby inbox.example.org (Postfix) with ESMTPS id 4A1B2C\r\n" +
+		"\tfor <jane@example.org>; Tue, 1 Jan 2019 10:00:05 +0000\r\n" +
+		"Received: from [192.0.2.1] (helo=laptop) by mx.example.org with ESMTPSA id xyz for bad@@example.org; Tue, 1 Jan 2019 10:00:00 +0000\r\n" +
+		"Subject: hello\r\n" +
+		"\r\n" +
+		"body\r\n"
+	hops, err := ParseReceived(strings.NewReader(msg))
+	if err != nil {
+		t.Fatalf("ParseReceived() error = %v", err)
+	}
+	if len(hops) != 2 {
+		t.Fatalf("ParseReceived() = %v hops, want 2", len(hops))
+	}
+
+	first := hops[0]
+	if first.From != "[192.0.2.1]" || first.FromIP != netip.MustParseAddr("192.0.2.1") || first.By != "mx.example.org" ||
+		first.With != "ESMTPSA" || first.ID != "xyz" || first.For != nil || first.Err == nil {
+		t.Errorf("ParseReceived() first hop = %+v", first)
+	}
+	second := hops[1]
+	date := time.Date(2019, 1, 1, 10, 0, 5, 0, time.UTC)
+	if second.From != "mx.example.org" || second.FromIP != netip.MustParseAddr("198.51.100.7") || second.By != "inbox.example.org" ||
+		second.With != "ESMTPS" || second.ID != "4A1B2C" || second.For == nil || second.For.String() != "jane@example.org" ||
+		second.Err != nil || !second.Date.Equal(date) {
+		t.Errorf("ParseReceived() second hop = %+v", second)
+	}
+
+	if _, err := ParseReceived(strings.NewReader("not a message")); err == nil {
+		t.Errorf("ParseReceived() error = nil for a malformed message")
+	}
+}
+
+func TestParseReceivedHeader(t *testing.T) {
+	tests := []struct {
+		name   string
+		header string
+		from   string
+		ip     string
+		by     string
+	}{
+		{"1", "from a.example (b.example [IPv6:2001:db8::1]) by c.example; Tue, 1 Jan 2019 10:00:00 +0000", "a.example", "2001:db8::1", "c.example"},
+		{"2", "by c.example (nested (comment [192.0.2.9])) via relay", "", "", "c.example"},
+		{"3", "from a.example (unknown [not-an-ip] [192.0.2.3]) by c.example", "a.example", "192.0.2.3", "c.example"},
+		{"4", "from a.example (unterminated [192.0.2.4]", "a.example", "192.0.2.4", ""},
+		{"5", "", "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := ParseReceivedHeader(tt.header)
+			ip, _ := netip.ParseAddr(tt.ip)
+			if got.From != tt.from || got.By != tt.by || got.FromIP != ip {
+				t.Errorf("ParseReceivedHeader() = %+v, want from %v ip %v by %v", got, tt.from, tt.ip, tt.by)
+			}
+		})
+	}
+}
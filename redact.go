@@ -0,0 +1,123 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"strings"
+	"unicode/utf8"
+)
+
+// RedactStyle is the way Redact masks an email address.
+type RedactStyle int
+
+const (
+	// RedactPartial keeps the first and last character of the local part, the first character
+	// of every label of the domain and its public suffix, ie. j**e@d*****.com.
+	RedactPartial RedactStyle = iota
+
+	// RedactHash replaces the address by the hex encoded SHA-256 hash of the lowercased address,
+	// or its HMAC when a key is set, so occurrences of the same address can still be correlated.
+	RedactHash
+
+	// RedactToken replaces the address by a fixed token.
+	RedactToken
+)
+
+// defaultRedactToken is the token of RedactToken when RedactOptions has none.
+const defaultRedactToken = "[email]"
+
+// RedactOptions configures RedactWith.
+type RedactOptions struct {
+	// Style is the way addresses are masked, defaults to RedactPartial.
+	Style RedactStyle
+
+	// Key is the key of the HMAC of RedactHash, without a key the hash is a plain SHA-256 which
+	// can be reversed for known addresses.
+	Key []byte
+
+	// Token replaces addresses with RedactToken, defaults to [email].
+	Token string
+}
+
+// Redact returns the email address masked for logs and user interfaces, ie. j**e@d*****.com for
+// jane@domain.com. See RedactWith.
+func (e EmailAddress) Redact() string {
+	return e.RedactWith(RedactOptions{})
+}
+
+// RedactWith returns the email address masked in the style of opts.
+func (e EmailAddress) RedactWith(opts RedactOptions) string {
+	switch opts.Style {
+	case RedactHash:
+		s := strings.ToLower(e.String())
+		if opts.Key == nil {
+			sum := sha256.Sum256([]byte(s))
+			return hex.EncodeToString(sum[:])
+		}
+		h := hmac.New(sha256.New, opts.Key)
+		h.Write([]byte(s)) // #nosec
+		return hex.EncodeToString(h.Sum(nil))
+	case RedactToken:
+		if opts.Token == "" {
+			return defaultRedactToken
+		}
+		return opts.Token
+	default:
+		return maskLocalPart(e.LocalPart) + "@" + maskDomain(e.Domain)
+	}
+}
+
+// Redact returns a copy of text with every email address found by FindIndex masked like
+// EmailAddress.Redact, ie. for logging. See RedactWith.
+func Redact(text []byte, opts ...ParseOption) []byte {
+	return RedactWith(text, RedactOptions{}, opts...)
+}
+
+// RedactWith returns a copy of text with every email address found by FindIndex masked in the
+// style of ropts.
+func RedactWith(text []byte, ropts RedactOptions, opts ...ParseOption) []byte {
+	out := make([]byte, 0, len(text))
+	last := 0
+	for _, m := range FindIndex(text, opts...) {
+		out = append(out, text[last:m.Start]...)
+		out = append(out, m.Address.RedactWith(ropts)...)
+		last = m.End
+	}
+	return append(out, text[last:]...)
+}
+
+// maskLocalPart keeps the first and last character of s if it is long enough to hide the rest.
+func maskLocalPart(s string) string {
+	n := utf8.RuneCountInString(s)
+	if n <= 2 {
+		return strings.Repeat("*", n)
+	}
+	first, i := utf8.DecodeRuneInString(s)
+	last, _ := utf8.DecodeLastRuneInString(s[i:])
+	return string(first) + strings.Repeat("*", n-2) + string(last)
+}
+
+// maskDomain keeps the public suffix of domain and the first character of its other labels.
+// Address literals are masked as a whole.
+func maskDomain(domain string) string {
+	if strings.HasPrefix(domain, "[") {
+		return "[" + strings.Repeat("*", utf8.RuneCountInString(domain)-2) + "]"
+	}
+	suffix, _ := publicSuffix(strings.ToLower(domain))
+	if len(suffix) >= len(domain) {
+		return domain
+	}
+	labels := strings.Split(domain[:len(domain)-len(suffix)-1], ".")
+	for i, l := range labels {
+		if first, n := utf8.DecodeRuneInString(l); n > 0 {
+			labels[i] = string(first) + strings.Repeat("*", utf8.RuneCountInString(l)-1)
+		}
+	}
+	return strings.Join(labels, ".") + domain[len(domain)-len(suffix)-1:]
+}
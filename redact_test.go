@@ -0,0 +1,54 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "testing"
+
+func TestEmailAddress_RedactWith(t *testing.T) {
+	tests := []struct {
+		name  string
+		email EmailAddress
+		opts  RedactOptions
+		want  string
+	}{
+		{"1", EmailAddress{"jane", "domain.com"}, RedactOptions{}, "j**e@d*****.com"},
+		{"2", EmailAddress{"jo", "domain.co.uk"}, RedactOptions{}, "**@d*****.co.uk"},
+		{"3", EmailAddress{"j", "mail.domain.com"}, RedactOptions{}, "*@m***.d*****.com"},
+		{"4", EmailAddress{"jöhn", "dömain.com"}, RedactOptions{}, "j**n@d*****.com"},
+		{"5", EmailAddress{"jane", "[192.0.2.1]"}, RedactOptions{}, "j**e@[*********]"},
+		{"6", EmailAddress{"jane", "Domain.COM"}, RedactOptions{Style: RedactHash}, "ce49c0361ab50a8197b0597027369ad2fe757a2b7e5df8e1a3c82735b3a19f0e"},
+		{"7", EmailAddress{"jane", "domain.com"}, RedactOptions{Style: RedactHash, Key: []byte("key")}, "eecc540342c5937ee9ab1a42697f7f301fb23dcf938d79b7d1ae74767e9d7d40"},
+		{"8", EmailAddress{"jane", "domain.com"}, RedactOptions{Style: RedactToken}, "[email]"},
+		{"9", EmailAddress{"jane", "domain.com"}, RedactOptions{Style: RedactToken, Token: "<redacted>"}, "<redacted>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.email.RedactWith(tt.opts); got != tt.want {
+				t.Errorf("EmailAddress.RedactWith() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestRedact(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		opts []ParseOption
+		want string
+	}{
+		{"1", "mail jane@domain.com or bob@bar.org.", nil, "mail j**e@d*****.com or b*b@b**.org."},
+		{"2", "no addresses here", nil, "no addresses here"},
+		{"3", "mail jane&#64;domain.com now", []ParseOption{WithHTMLEntityDecoding()}, "mail j**e@d*****.com now"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := string(Redact([]byte(tt.text), tt.opts...)); got != tt.want {
+				t.Errorf("Redact() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,95 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"sort"
+	"strings"
+	"sync/atomic"
+)
+
+// defaultRoles are local parts that belong to a function or department rather than a person.
+var defaultRoles = []string{
+	"abuse", "accounting", "admin", "billing", "careers", "contact", "do-not-reply", "donotreply",
+	"enquiries", "feedback", "help", "hello", "hostmaster", "hr", "info", "jobs", "legal",
+	"mailer-daemon", "marketing", "newsletter", "no-reply", "noc", "noreply", "office",
+	"postmaster", "press", "privacy", "root", "sales", "security", "support", "team", "webmaster",
+}
+
+// roles holds the roleSet of IsRoleAccount.
+var roles atomic.Value
+
+func init() {
+	roles.Store(newRoleSet(defaultRoles))
+}
+
+// roleSet is a set of lowercased role local parts.
+type roleSet map[string]struct{}
+
+func newRoleSet(list []string) roleSet {
+	s := make(roleSet, len(list))
+	for _, r := range list {
+		s[strings.ToLower(r)] = struct{}{}
+	}
+	return s
+}
+
+func currentRoles() roleSet {
+	return roles.Load().(roleSet)
+}
+
+// contains reports whether local, without its subaddress, is in the set.
+func (s roleSet) contains(local string) bool {
+	_, ok := s[roleLocalPart(local)]
+	return ok
+}
+
+// roleLocalPart lowercases local and strips a subaddress, such as +news.
+func roleLocalPart(local string) string {
+	local = strings.ToLower(local)
+	if i := strings.IndexByte(local, '+'); i > 0 {
+		local = local[:i]
+	}
+	return local
+}
+
+// RoleAccounts returns the local parts IsRoleAccount reports as role accounts, sorted. It is a
+// list of common roles, such as info, admin and noreply, until SetRoleAccounts is called.
+func RoleAccounts() []string {
+	s := currentRoles()
+	list := make([]string, 0, len(s))
+	for r := range s {
+		list = append(list, r)
+	}
+	sort.Strings(list)
+	return list
+}
+
+// SetRoleAccounts replaces the local parts reported as role accounts by IsRoleAccount, Analyze
+// and WithoutRoleAccounts. It is safe to call while other goroutines check addresses.
+func SetRoleAccounts(list []string) {
+	roles.Store(newRoleSet(list))
+}
+
+// IsRoleAccount reports whether the email address belongs to a function or department rather
+// than a person, such as info@ or postmaster@. The local part is compared case-insensitively and
+// without its subaddress, so Info+News@ is a role account too. See RoleAccounts.
+func (e EmailAddress) IsRoleAccount() bool {
+	return currentRoles().contains(e.LocalPart)
+}
+
+// WithoutRoleAccounts makes Find and the other search functions skip role accounts, see
+// IsRoleAccount. Parse ignores it.
+func WithoutRoleAccounts() ParseOption {
+	return func(o *parseOptions) {
+		o.noRoleAccounts = true
+	}
+}
+
+// skip reports whether a search drops e.
+func (o parseOptions) skip(e *EmailAddress) bool {
+	return o.noRoleAccounts && e.IsRoleAccount()
+}
@@ -0,0 +1,70 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bytes"
+	"reflect"
+	"testing"
+)
+
+func TestEmailAddress_IsRoleAccount(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want bool
+	}{
+		{"1", EmailAddress{"info", "example.com"}, true},
+		{"2", EmailAddress{"NoReply", "example.com"}, true},
+		{"3", EmailAddress{"postmaster+bounces", "example.com"}, true},
+		{"4", EmailAddress{"john.smith", "example.com"}, false},
+		{"5", EmailAddress{"information", "example.com"}, false},
+		{"6", EmailAddress{"+info", "example.com"}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.IsRoleAccount(); got != tt.want {
+				t.Errorf("EmailAddress.IsRoleAccount() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetRoleAccounts(t *testing.T) {
+	defer SetRoleAccounts(RoleAccounts())
+	SetRoleAccounts([]string{"Recruiting", "info"})
+	if got, want := RoleAccounts(), []string{"info", "recruiting"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("RoleAccounts() = %v, want %v", got, want)
+	}
+	if !(EmailAddress{"recruiting", "example.com"}).IsRoleAccount() || (EmailAddress{"sales", "example.com"}).IsRoleAccount() {
+		t.Errorf("EmailAddress.IsRoleAccount() doesn't use the new list")
+	}
+}
+
+func TestFind_withoutRoleAccounts(t *testing.T) {
+	text := []byte("Mail info@example.com, Sales+EU@example.com or john@example.com.")
+	want := []*EmailAddress{{"john", "example.com"}}
+	if got := Find(text, false, WithoutRoleAccounts()); !reflect.DeepEqual(got, want) {
+		t.Errorf("Find() = %v, want %v", got, want)
+	}
+	if got := FindIndex(text, WithoutRoleAccounts()); len(got) != 1 || !reflect.DeepEqual(got[0].Address, want[0]) {
+		t.Errorf("FindIndex() = %v, want %v", got, want)
+	}
+	var found []*EmailAddress
+	err := FindReader(bytes.NewReader(text), func(e *EmailAddress) bool {
+		found = append(found, e)
+		return true
+	}, WithoutRoleAccounts())
+	if err != nil || !reflect.DeepEqual(found, want) {
+		t.Errorf("FindReader() = %v, %v, want %v", found, err, want)
+	}
+	if got := Find(text, false); len(got) != 3 {
+		t.Errorf("Find() without the option = %v, want all 3 addresses", got)
+	}
+	if _, err := Parse("info@example.com", WithoutRoleAccounts()); err != nil {
+		t.Errorf("Parse() error = %v, want role accounts accepted", err)
+	}
+}
@@ -0,0 +1,113 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strings"
+	"unicode"
+)
+
+// allowedScriptMixes are the combinations of scripts that are commonly used together within a
+// single word, as per the highly restrictive profile of Unicode Technical Standard #39.
+var allowedScriptMixes = [][]string{
+	{"Latin", "Han", "Hiragana", "Katakana"},
+	{"Latin", "Han", "Bopomofo"},
+	{"Latin", "Han", "Hangul"},
+}
+
+// ValidateSecurity tests the email address for characters that are used to visually spoof
+// addresses when they are displayed: bidirectional control characters, invisible characters such
+// as zero-width joiners, and labels (the dot separated parts of the local part and domain) that
+// mix scripts, such as Latin and Cyrillic. It returns an error for the first issue found.
+func (e EmailAddress) ValidateSecurity() error {
+	for _, part := range []string{e.LocalPart, e.Domain} {
+		for _, r := range part {
+			switch {
+			case isBidiControl(r):
+				return newError(CodeBidiControl, fmt.Errorf("bidirectional control character %U found in %s", r, e))
+			case isInvisible(r):
+				return newError(CodeInvisibleCharacter, fmt.Errorf("invisible character %U found in %s", r, e))
+			}
+		}
+		for _, label := range strings.Split(part, ".") {
+			if scripts := labelScripts(label); !allowedScripts(scripts) {
+				return newError(CodeMixedScript, fmt.Errorf("label %q mixes the scripts %s", label, strings.Join(scripts, ", ")))
+			}
+		}
+	}
+	return nil
+}
+
+func isBidiControl(r rune) bool {
+	return r == '\u061c' || r == '\u200e' || r == '\u200f' ||
+		r >= '\u202a' && r <= '\u202e' || r >= '\u2066' && r <= '\u2069'
+}
+
+func isInvisible(r rune) bool {
+	switch r {
+	case '\u00ad', '\u034f', '\u180e', '\u200b', '\u200c', '\u200d', '\u2060', '\u2061', '\u2062',
+		'\u2063', '\u2064', '\ufeff':
+		return true
+	}
+	return false
+}
+
+// labelScripts returns the names of the scripts used in label, ignoring the characters that are
+// shared by scripts.
+func labelScripts(label string) []string {
+	var scripts []string
+	for _, r := range label {
+		if r < unicode.MaxASCII {
+			if unicode.IsLetter(r) {
+				scripts = appendScript(scripts, "Latin")
+			}
+			continue
+		}
+		for name, table := range unicode.Scripts {
+			if name != "Common" && name != "Inherited" && unicode.Is(table, r) {
+				scripts = appendScript(scripts, name)
+				break
+			}
+		}
+	}
+	return scripts
+}
+
+func appendScript(scripts []string, name string) []string {
+	for _, s := range scripts {
+		if s == name {
+			return scripts
+		}
+	}
+	return append(scripts, name)
+}
+
+// allowedScripts reports whether scripts may be used together in a single label.
+func allowedScripts(scripts []string) bool {
+	if len(scripts) <= 1 {
+		return true
+	}
+mixes:
+	for _, mix := range allowedScriptMixes {
+		for _, s := range scripts {
+			if !containsString(mix, s) {
+				continue mixes
+			}
+		}
+		return true
+	}
+	return false
+}
+
+func containsString(list []string, s string) bool {
+	for _, l := range list {
+		if l == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,37 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "testing"
+
+func TestEmailAddress_ValidateSecurity(t *testing.T) {
+	tests := []struct {
+		name    string
+		e       EmailAddress
+		wantErr bool
+	}{
+		{"1", EmailAddress{"foo", "bar.com"}, false},
+		{"2", EmailAddress{"firstname+last.name", "sub.domain.co.uk"}, false},
+		{"3", EmailAddress{"用户", "例子.广告"}, false},
+		{"4", EmailAddress{"ユーザー", "日本語ドメイン.jp"}, false},
+		{"5", EmailAddress{"пользователь", "пример.рф"}, false},
+		{"6", EmailAddress{"admin", "pаypal.com"}, true},
+		{"7", EmailAddress{"аdmin", "paypal.com"}, true},
+		{"8", EmailAddress{"admin", "paypal.com.пример"}, false},
+		{"9", EmailAddress{"foo\u202egnp.exe", "bar.com"}, true},
+		{"10", EmailAddress{"foo", "b\u200bar.com"}, true},
+		{"11", EmailAddress{"foo\u200d", "bar.com"}, true},
+		{"12", EmailAddress{"αβγ", "bar.com"}, false},
+		{"13", EmailAddress{"αbc", "bar.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.e.ValidateSecurity(); (err != nil) != tt.wantErr {
+				t.Errorf("EmailAddress.ValidateSecurity() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
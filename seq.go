@@ -0,0 +1,60 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package emailaddress
+
+import (
+	"io"
+	"iter"
+)
+
+// FindSeq returns an iterator over the email addresses in haystack, found like FindWithOptions
+// with opts, without collecting them in a slice. The search stops when the loop ends early. With
+// WithHostValidation the hosts are validated one at a time as the loop goes, in the context of
+// WithContext, see FindWithOptions to validate them concurrently.
+//
+//	for e := range emailaddress.FindSeq(text, emailaddress.WithICANNSuffix()) {
+//		if e.Domain == "example.com" {
+//			break
+//		}
+//	}
+func FindSeq(haystack []byte, opts ...FindOption) iter.Seq[*EmailAddress] {
+	return func(yield func(*EmailAddress) bool) {
+		o := newFindOptions(opts)
+		n := 0
+		o.each(haystack, func(e *EmailAddress) bool {
+			if o.validateHost && o.parse.validateHost(o.ctx, e) != nil {
+				return o.ctx.Err() == nil
+			}
+			n++
+			return yield(e) && (o.maxResults <= 0 || n < o.maxResults)
+		})
+	}
+}
+
+// FindReaderSeq returns an iterator over the email addresses in r, found like FindReader. The
+// search stops when the loop ends early. An error of r other than io.EOF is yielded last, with a
+// nil address.
+//
+//	for e, err := range emailaddress.FindReaderSeq(f) {
+//		if err != nil {
+//			return err
+//		}
+//		fmt.Println(e)
+//	}
+func FindReaderSeq(r io.Reader, opts ...ParseOption) iter.Seq2[*EmailAddress, error] {
+	return func(yield func(*EmailAddress, error) bool) {
+		more := true
+		err := FindReader(r, func(e *EmailAddress) bool {
+			more = yield(e, nil)
+			return more
+		}, opts...)
+		if err != nil && more {
+			yield(nil, err)
+		}
+	}
+}
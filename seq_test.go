@@ -0,0 +1,88 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build go1.23
+
+package emailaddress
+
+import (
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFindSeq(t *testing.T) {
+	text := []byte("a@one.com nobody@one.com dead@two.com b@two.com c@three.com")
+
+	tests := []struct {
+		name  string
+		opts  []FindOption
+		stop  string
+		want  []string
+		calls int
+	}{
+		{name: "1", want: []string{"a@one.com", "nobody@one.com", "dead@two.com", "b@two.com", "c@three.com"}},
+		{name: "2", stop: "nobody@one.com", want: []string{"a@one.com", "nobody@one.com"}},
+		{name: "3", opts: []FindOption{WithMaxResults(3)}, want: []string{"a@one.com", "nobody@one.com", "dead@two.com"}},
+		{name: "4", opts: []FindOption{WithHostValidation()}, want: []string{"a@one.com", "b@two.com", "c@three.com"}, calls: 5},
+		{name: "5", opts: []FindOption{WithHostValidation()}, stop: "a@one.com", want: []string{"a@one.com"}, calls: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := &testHostValidator{}
+			opts := append([]FindOption{WithParseOptions(func(o *parseOptions) { o.hostValidator = v })}, tt.opts...)
+			var got []string
+			for e := range FindSeq(text, opts...) {
+				got = append(got, e.String())
+				if e.String() == tt.stop {
+					break
+				}
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindSeq() = %q, want %q", got, tt.want)
+			}
+			if len(v.calls) != tt.calls {
+				t.Errorf("validated %q, want %d validations", v.calls, tt.calls)
+			}
+		})
+	}
+}
+
+func TestFindReaderSeq(t *testing.T) {
+	errRead := errors.New("read failed")
+
+	tests := []struct {
+		name    string
+		r       io.Reader
+		stop    int
+		want    int
+		wantErr error
+	}{
+		{name: "1", r: strings.NewReader("a@one.com b@two.com c@three.com"), want: 3},
+		{name: "2", r: strings.NewReader("a@one.com b@two.com c@three.com"), stop: 2, want: 2},
+		{name: "3", r: io.MultiReader(strings.NewReader("a@one.com "), iotest.ErrReader(errRead)), want: 1, wantErr: errRead},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got int
+			var err error
+			for e, seqErr := range FindReaderSeq(tt.r) {
+				if seqErr != nil {
+					err = seqErr
+					continue
+				}
+				if got++; e == nil || got == tt.stop {
+					break
+				}
+			}
+			if got != tt.want || !errors.Is(err, tt.wantErr) {
+				t.Errorf("FindReaderSeq() = %d addresses, error %v, want %d, %v", got, err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,198 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bufio"
+	"crypto/tls"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testServer is a minimal in-process SMTP server used to exercise the probing code without
+// network access.
+type testServer struct {
+	ln net.Listener
+
+	// mail returns the reply for a MAIL FROM command, defaults to accepting every sender.
+	mail func(addr string) string
+
+	// rcpt returns the reply for a RCPT TO command, defaults to accepting every recipient.
+	rcpt func(addr string) string
+
+	// extensions are announced in the reply to EHLO, defaults to PIPELINING and 8BITMIME.
+	extensions []string
+
+	// tlsConfig, if set, makes the server announce and accept STARTTLS.
+	tlsConfig *tls.Config
+
+	// implicitTLS makes the server start every connection with a TLS handshake, with tlsConfig.
+	implicitTLS bool
+
+	// auth, if set, makes the server announce AUTH PLAIN and returns the reply for the AUTH
+	// command.
+	auth func(line string) string
+
+	mu      sync.Mutex
+	remotes []net.Addr
+	cmds    []string
+}
+
+// newTestServer starts a test server listening on addr (ie. 127.0.0.1:0), it is closed when
+// the test finishes. opts configure the server before it starts serving.
+func newTestServer(t testing.TB, addr string, opts ...func(*testServer)) *testServer {
+	t.Helper()
+	ln, err := net.Listen("tcp", addr)
+	if err != nil {
+		t.Skipf("cannot listen on %s: %v", addr, err)
+	}
+	s := &testServer{ln: ln}
+	for _, opt := range opts {
+		opt(s)
+	}
+	t.Cleanup(func() { ln.Close() })
+	go s.serve()
+	return s
+}
+
+// withTLS makes the test server accept STARTTLS with config, or start every connection with a
+// TLS handshake if implicit is true. Nil config leaves TLS off.
+func withTLS(config *tls.Config, implicit bool) func(*testServer) {
+	return func(s *testServer) {
+		s.tlsConfig, s.implicitTLS = config, implicit
+	}
+}
+
+func (s *testServer) host() string {
+	host, _, _ := net.SplitHostPort(s.ln.Addr().String())
+	return host
+}
+
+func (s *testServer) port() int {
+	_, p, _ := net.SplitHostPort(s.ln.Addr().String())
+	port, _ := strconv.Atoi(p)
+	return port
+}
+
+func (s *testServer) commands() []string {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]string(nil), s.cmds...)
+}
+
+func (s *testServer) remoteAddrs() []net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]net.Addr(nil), s.remotes...)
+}
+
+func (s *testServer) serve() {
+	for {
+		conn, err := s.ln.Accept()
+		if err != nil {
+			return
+		}
+		s.mu.Lock()
+		s.remotes = append(s.remotes, conn.RemoteAddr())
+		s.mu.Unlock()
+		go s.handle(conn)
+	}
+}
+
+func (s *testServer) handle(conn net.Conn) {
+	defer conn.Close()
+	if s.implicitTLS {
+		conn = tls.Server(conn, s.tlsConfig)
+	}
+	r := bufio.NewReader(conn)
+	reply := func(line string) {
+		conn.Write([]byte(line + "\r\n"))
+	}
+	reply("220 test ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		line = strings.TrimRight(line, "\r\n")
+		s.mu.Lock()
+		s.cmds = append(s.cmds, line)
+		s.mu.Unlock()
+
+		verb := strings.ToUpper(line)
+		if i := strings.IndexAny(verb, " :"); i >= 0 {
+			verb = verb[:i]
+		}
+		switch verb {
+		case "EHLO":
+			extensions := s.extensions
+			if extensions == nil {
+				extensions = []string{"PIPELINING", "8BITMIME"}
+			}
+			if s.tlsConfig != nil && !s.implicitTLS {
+				extensions = append(extensions[:len(extensions):len(extensions)], "STARTTLS")
+			}
+			if s.auth != nil {
+				extensions = append(extensions[:len(extensions):len(extensions)], "AUTH PLAIN")
+			}
+			lines := append([]string{"test greets you"}, extensions...)
+			for i, line := range lines {
+				if i == len(lines)-1 {
+					reply("250 " + line)
+				} else {
+					reply("250-" + line)
+				}
+			}
+		case "STARTTLS":
+			if s.tlsConfig == nil {
+				reply("502 command not implemented")
+				continue
+			}
+			reply("220 ready to start TLS")
+			conn = tls.Server(conn, s.tlsConfig)
+			r = bufio.NewReader(conn)
+		case "AUTH":
+			if s.auth == nil {
+				reply("502 command not implemented")
+				continue
+			}
+			reply(s.auth(line))
+		case "HELO", "RSET", "NOOP":
+			reply("250 OK")
+		case "MAIL":
+			if s.mail != nil {
+				reply(s.mail(commandAddr(line)))
+			} else {
+				reply("250 OK")
+			}
+		case "RCPT":
+			if s.rcpt != nil {
+				reply(s.rcpt(commandAddr(line)))
+			} else {
+				reply("250 OK")
+			}
+		case "QUIT":
+			reply("221 bye")
+			return
+		default:
+			reply("502 command not implemented")
+		}
+	}
+}
+
+// commandAddr returns the address of a MAIL FROM or RCPT TO command.
+func commandAddr(line string) string {
+	addr := line[strings.IndexByte(line, ':')+1:]
+	if i := strings.IndexByte(addr, '>'); i >= 0 {
+		addr = addr[:i]
+	}
+	return strings.Trim(addr, "< ")
+}
@@ -0,0 +1,203 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+)
+
+// maxServiceResponseSize is the largest response of a verification API that is read.
+const maxServiceResponseSize = 1 << 20
+
+// VerificationService verifies email addresses, ie. with the SMTP probes of a Verifier or with
+// the API of a third-party verification service, see HTTPVerificationService.
+type VerificationService interface {
+	Verify(ctx context.Context, address string) ValidationResult
+}
+
+// verificationServiceValue wraps a VerificationService, so implementations of different types
+// can be stored in an atomic.Value.
+type verificationServiceValue struct {
+	VerificationService
+}
+
+var verificationService atomic.Value
+
+// SetVerificationService replaces the service used by EmailAddress.Verify, which defaults to the
+// SMTP probes of the default Verifier, with s. Nil restores the default. It is safe to call while
+// other goroutines verify addresses.
+func SetVerificationService(s VerificationService) {
+	if s == nil {
+		s = defaultVerifier
+	}
+	verificationService.Store(verificationServiceValue{s})
+}
+
+// currentVerificationService returns the service set with SetVerificationService.
+func currentVerificationService() VerificationService {
+	if s, ok := verificationService.Load().(verificationServiceValue); ok {
+		return s.VerificationService
+	}
+	return defaultVerifier
+}
+
+// DefaultServiceStatuses maps the statuses of common verification APIs, such as ZeroBounce,
+// Kickbox, NeverBounce and Mailgun, to verdicts. Statuses not listed are VerdictUnknown.
+var DefaultServiceStatuses = map[string]Verdict{
+	"valid":         VerdictDeliverable,
+	"deliverable":   VerdictDeliverable,
+	"invalid":       VerdictUndeliverable,
+	"undeliverable": VerdictUndeliverable,
+	"do_not_send":   VerdictUndeliverable,
+	"do_not_mail":   VerdictUndeliverable,
+	"spamtrap":      VerdictUndeliverable,
+	"abuse":         VerdictUndeliverable,
+	"disposable":    VerdictUndeliverable,
+	"catch-all":     VerdictRisky,
+	"catch_all":     VerdictRisky,
+	"catchall":      VerdictRisky,
+	"accept_all":    VerdictRisky,
+	"risky":         VerdictRisky,
+}
+
+// HTTPVerificationService verifies addresses with a verification API that takes the address as
+// a query parameter of a GET request and replies with a JSON object holding its status. The
+// fields map the request and response of the API, ie. for ZeroBounce:
+//
+//	s := &emailaddress.HTTPVerificationService{
+//		URL:   "https://api.zerobounce.net/v2/validate",
+//		Query: url.Values{"api_key": {key}},
+//	}
+//
+// Addresses are parsed before the API is called, invalid addresses are undeliverable without a
+// request. Only the verdict of the result is set by the API, the other fields of the SMTP probes
+// stay zero.
+type HTTPVerificationService struct {
+	// URL is the endpoint of the API.
+	URL string
+
+	// AddressParam is the query parameter holding the address, defaults to email.
+	AddressParam string
+
+	// Query and Header are added to every request, ie. with an API key.
+	Query  url.Values
+	Header http.Header
+
+	// StatusField is the field of the JSON response holding the status, defaults to status. Use
+	// dots for nested fields, ie. result.status.
+	StatusField string
+
+	// Statuses maps the statuses of the API, compared case insensitively, to verdicts. Defaults
+	// to DefaultServiceStatuses.
+	Statuses map[string]Verdict
+
+	// Decode, if set, replaces StatusField and Statuses to map the response body to a verdict.
+	Decode func(body []byte) (Verdict, error)
+
+	// Client sends the requests, defaults to http.DefaultClient.
+	Client *http.Client
+}
+
+// Verify parses address and asks the API for its verdict. A failed request or a response that
+// can't be decoded results in VerdictUnknown with the error.
+func (s *HTTPVerificationService) Verify(ctx context.Context, address string) ValidationResult {
+	var r ValidationResult
+	e, err := Parse(address, WithSMTPUTF8())
+	if err != nil {
+		return r.undeliverable(err)
+	}
+	r.Address, r.SyntaxValid = *e, true
+
+	body, err := s.fetch(ctx, e.String())
+	if err != nil {
+		return r.unknown(err)
+	}
+	decode := s.Decode
+	if decode == nil {
+		decode = s.decodeStatus
+	}
+	if r.Verdict, err = decode(body); err != nil {
+		return r.unknown(err)
+	}
+	return r
+}
+
+// fetch sends the request for address and returns the body of the response.
+func (s *HTTPVerificationService) fetch(ctx context.Context, address string) ([]byte, error) {
+	u, err := url.Parse(s.URL)
+	if err != nil {
+		return nil, err
+	}
+	q := u.Query()
+	for k, values := range s.Query {
+		q[k] = append(q[k], values...)
+	}
+	param := s.AddressParam
+	if param == "" {
+		param = "email"
+	}
+	q.Set(param, address)
+	u.RawQuery = q.Encode()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	for k, values := range s.Header {
+		req.Header[k] = append(req.Header[k], values...)
+	}
+	req.Header.Set("Accept", "application/json")
+	client := s.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("verification service %s: %s", u.Host, resp.Status)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxServiceResponseSize))
+}
+
+// decodeStatus maps the status field of the JSON object body to a verdict.
+func (s *HTTPVerificationService) decodeStatus(body []byte) (Verdict, error) {
+	var v interface{}
+	if err := json.Unmarshal(body, &v); err != nil {
+		return VerdictUnknown, err
+	}
+	field := s.StatusField
+	if field == "" {
+		field = "status"
+	}
+	for _, name := range strings.Split(field, ".") {
+		obj, ok := v.(map[string]interface{})
+		if !ok {
+			return VerdictUnknown, fmt.Errorf("verification service response has no field %s", field)
+		}
+		v = obj[name]
+	}
+	status, ok := v.(string)
+	if !ok {
+		return VerdictUnknown, fmt.Errorf("verification service response has no status in field %s", field)
+	}
+	statuses := s.Statuses
+	if statuses == nil {
+		statuses = DefaultServiceStatuses
+	}
+	return statuses[strings.ToLower(status)], nil
+}
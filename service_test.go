@@ -0,0 +1,99 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+)
+
+func TestHTTPVerificationService_Verify(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Query().Get("api_key") != "secret" || r.Header.Get("X-Client") != "test" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Query().Get("email") + r.URL.Query().Get("address") {
+		case "valid@example.com":
+			io.WriteString(w, `{"status":"Valid","sub_status":""}`) // #nosec
+		case "invalid@example.com":
+			io.WriteString(w, `{"status":"invalid"}`) // #nosec
+		case "all@example.com":
+			io.WriteString(w, `{"status":"catch-all","result":{"status":"accept_all"}}`) // #nosec
+		case "odd@example.com":
+			io.WriteString(w, `{"status":"greylisted"}`) // #nosec
+		case "broken@example.com":
+			io.WriteString(w, `{"status":`) // #nosec
+		default:
+			http.Error(w, "failure", http.StatusInternalServerError)
+		}
+	}))
+	defer srv.Close()
+	auth := func(s HTTPVerificationService) *HTTPVerificationService {
+		s.URL, s.Query, s.Header = srv.URL, url.Values{"api_key": {"secret"}}, http.Header{"X-Client": {"test"}}
+		return &s
+	}
+
+	tests := []struct {
+		name    string
+		service *HTTPVerificationService
+		address string
+		want    Verdict
+		wantErr bool
+	}{
+		{"1", auth(HTTPVerificationService{}), "valid@example.com", VerdictDeliverable, false},
+		{"2", auth(HTTPVerificationService{}), "invalid@example.com", VerdictUndeliverable, false},
+		{"3", auth(HTTPVerificationService{}), "all@example.com", VerdictRisky, false},
+		{"4", auth(HTTPVerificationService{StatusField: "result.status"}), "all@example.com", VerdictRisky, false},
+		{"5", auth(HTTPVerificationService{StatusField: "result.status"}), "valid@example.com", VerdictUnknown, true},
+		{"6", auth(HTTPVerificationService{}), "odd@example.com", VerdictUnknown, false},
+		{"7", auth(HTTPVerificationService{}), "broken@example.com", VerdictUnknown, true},
+		{"8", auth(HTTPVerificationService{}), "down@example.com", VerdictUnknown, true},
+		{"9", &HTTPVerificationService{URL: srv.URL}, "valid@example.com", VerdictUnknown, true},
+		{"10", auth(HTTPVerificationService{AddressParam: "address"}), "invalid@example.com", VerdictUndeliverable, false},
+		{"11", auth(HTTPVerificationService{Statuses: map[string]Verdict{"greylisted": VerdictRisky}}), "odd@example.com", VerdictRisky, false},
+		{"12", auth(HTTPVerificationService{Decode: func([]byte) (Verdict, error) { return VerdictDeliverable, nil }}), "odd@example.com", VerdictDeliverable, false},
+		{"13", auth(HTTPVerificationService{}), "valid", VerdictUndeliverable, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.service.Verify(context.Background(), tt.address)
+			if got.Verdict != tt.want || (got.Err != nil) != tt.wantErr {
+				t.Errorf("HTTPVerificationService.Verify() = %v, %v, want %v, wantErr %v", got.Verdict, got.Err, tt.want, tt.wantErr)
+			}
+		})
+	}
+}
+
+// verdictService is a VerificationService returning the same verdict for every address.
+type verdictService Verdict
+
+func (s verdictService) Verify(ctx context.Context, address string) ValidationResult {
+	return ValidationResult{Verdict: Verdict(s), Err: errors.New(address)}
+}
+
+func TestSetVerificationService(t *testing.T) {
+	var _ VerificationService = (*Verifier)(nil)
+	var _ VerificationService = NewRelayValidator("smtp.example.com", 587, nil)
+	defer SetVerificationService(nil)
+
+	e := EmailAddress{"foo", "example.com"}
+	SetVerificationService(verdictService(VerdictRisky))
+	if got := e.Verify(); got.Verdict != VerdictRisky || got.Err.Error() != "foo@example.com" {
+		t.Errorf("EmailAddress.Verify() = %v, %v, want the verdict of the service", got.Verdict, got.Err)
+	}
+	SetVerificationService(nil)
+	if got := currentVerificationService(); got != defaultVerifier {
+		t.Errorf("currentVerificationService() = %v, want the default Verifier", got)
+	}
+}
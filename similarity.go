@@ -0,0 +1,78 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+// keyboardRows is the QWERTY layout used to find neighbouring keys.
+var keyboardRows = []string{"1234567890-", "qwertyuiop", "asdfghjkl", "zxcvbnm"}
+
+// keyPositions maps every key of keyboardRows to its row and column.
+var keyPositions = func() map[rune][2]int {
+	m := make(map[rune][2]int)
+	for row, keys := range keyboardRows {
+		for col, k := range keys {
+			m[k] = [2]int{row, col}
+		}
+	}
+	return m
+}()
+
+// keyboardSubstitution returns the cost of typing y instead of x, hitting a neighbouring key
+// costs half a regular substitution.
+func keyboardSubstitution(x, y rune) float64 {
+	px, okx := keyPositions[x]
+	py, oky := keyPositions[y]
+	if okx && oky && abs(px[0]-py[0]) <= 1 && abs(px[1]-py[1]) <= 1 {
+		return 0.5
+	}
+	return 1
+}
+
+func abs(i int) int {
+	if i < 0 {
+		return -i
+	}
+	return i
+}
+
+// DomainSimilarity returns how similar two domains are, from 0 for completely different domains
+// to 1 for equal domains. It is based on the edit distance between both domains, where typing a
+// neighbouring key on a QWERTY keyboard (ie. gmail.con) and swapping two adjacent characters
+// (ie. gmial.com) are counted as likely typos. Domains are compared case-insensitively.
+func DomainSimilarity(a, b string) float64 {
+	a = strings.TrimSuffix(strings.ToLower(a), ".")
+	b = strings.TrimSuffix(strings.ToLower(b), ".")
+	n := utf8.RuneCountInString(a)
+	if m := utf8.RuneCountInString(b); m > n {
+		n = m
+	}
+	if n == 0 {
+		return 1
+	}
+	return 1 - weightedDistance(a, b, keyboardSubstitution)/float64(n)
+}
+
+// NearestDomain returns the candidate most similar to domain according to DomainSimilarity and
+// its similarity. The first candidate wins a tie. It returns an empty string and 0 if there are
+// no candidates. A policy such as suggesting the nearest domain only above a minimum similarity can
+// be built on top of it:
+//
+//	if d, s := emailaddress.NearestDomain(e.Domain, corporateDomains); s >= 0.8 && s < 1 {
+//		fmt.Printf("did you mean %s@%s?\n", e.LocalPart, d)
+//	}
+func NearestDomain(domain string, candidates []string) (string, float64) {
+	nearest, best := "", 0.0
+	for _, c := range candidates {
+		if s := DomainSimilarity(domain, c); nearest == "" || s > best {
+			nearest, best = c, s
+		}
+	}
+	return nearest, best
+}
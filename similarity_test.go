@@ -0,0 +1,69 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"math"
+	"testing"
+)
+
+func TestDomainSimilarity(t *testing.T) {
+	tests := []struct {
+		name string
+		a    string
+		b    string
+		want float64
+	}{
+		{"1", "gmail.com", "gmail.com", 1},
+		{"2", "GMAIL.com.", "gmail.com", 1},
+		{"3", "gmail.con", "gmail.com", 1 - 0.5/9},
+		{"4", "gmail.cpm", "gmail.com", 1 - 0.5/9},
+		{"5", "gmail.cxm", "gmail.com", 1 - 1.0/9},
+		{"6", "gmial.com", "gmail.com", 1 - 1.0/9},
+		{"7", "gmai.com", "gmail.com", 1 - 1.0/9},
+		{"8", "aaa", "ppp", 0},
+		{"9", "", "", 1},
+		{"10", "", "abc", 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := DomainSimilarity(tt.a, tt.b); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DomainSimilarity() = %v, want %v", got, tt.want)
+			}
+			if got := DomainSimilarity(tt.b, tt.a); math.Abs(got-tt.want) > 1e-9 {
+				t.Errorf("DomainSimilarity() reversed = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNearestDomain(t *testing.T) {
+	candidates := []string{"gmail.com", "hotmail.com", "yahoo.com", "mycompany.com"}
+	tests := []struct {
+		name       string
+		domain     string
+		candidates []string
+		want       string
+	}{
+		{"1", "gmail.con", candidates, "gmail.com"},
+		{"2", "hotmial.com", candidates, "hotmail.com"},
+		{"3", "mycompnay.com", candidates, "mycompany.com"},
+		{"4", "yahoo.com", candidates, "yahoo.com"},
+		{"5", "gmail.com", nil, ""},
+		{"6", "zzz", []string{"aaa", "bbb"}, "aaa"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, similarity := NearestDomain(tt.domain, tt.candidates)
+			if got != tt.want {
+				t.Errorf("NearestDomain() = %v, want %v", got, tt.want)
+			}
+			if want := DomainSimilarity(tt.domain, tt.want); got != "" && similarity != want {
+				t.Errorf("NearestDomain() similarity = %v, want %v", similarity, want)
+			}
+		})
+	}
+}
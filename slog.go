@@ -0,0 +1,41 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build go1.21 && !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// WithSlog logs the DNS queries, SMTP connections, retries and every SMTP reply of the Verifier
+// as debug events to l. The correlation ID of a call is the correlation_id attribute and the
+// context of the call is passed to the handler, like with ContextWithLogger(ctx, SlogLogger(l)).
+func WithSlog(l *slog.Logger) Option {
+	return WithLogger(SlogLogger(l))
+}
+
+// SlogLogger returns a Logger logging debug events to l, see WithSlog.
+func SlogLogger(l *slog.Logger) Logger {
+	return slogLogger{l}
+}
+
+type slogLogger struct {
+	logger *slog.Logger
+}
+
+func (l slogLogger) Printf(format string, v ...interface{}) {
+	l.logger.Debug(fmt.Sprintf(format, v...))
+}
+
+func (l slogLogger) event(ctx context.Context, msg string, args ...interface{}) {
+	if id := CorrelationID(ctx); id != "" {
+		args = append(args, "correlation_id", id)
+	}
+	l.logger.DebugContext(ctx, msg, args...)
+}
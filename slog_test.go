@@ -0,0 +1,52 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build go1.21 && !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"net"
+	"strings"
+	"testing"
+)
+
+func TestWithSlog(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+	var buf bytes.Buffer
+	l := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	v := NewVerifier(WithSlog(l), WithResolver(r), WithPort(s.port()))
+
+	ctx := ContextWithCorrelationID(context.Background(), "req-42")
+	if err := v.ValidateHost(ctx, EmailAddress{"info", "example.com"}); err != nil {
+		t.Fatalf("Verifier.ValidateHost() error = %v", err)
+	}
+
+	tests := []struct {
+		name string
+		want string
+	}{
+		{name: "1", want: `msg="dns: MX example.com: 1 records, error <nil>" correlation_id=req-42`},
+		{name: "2", want: `msg="smtp: reply" host=127.0.0.1 reply="220 `},
+		{name: "3", want: `msg="smtp: reply" host=127.0.0.1 reply="250 `},
+		{name: "4", want: `msg="smtp: RCPT TO info@example.com at 127.0.0.1: error <nil>"`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if !strings.Contains(buf.String(), tt.want) {
+				t.Errorf("log = %s, want %s", buf.String(), tt.want)
+			}
+		})
+	}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		if !strings.Contains(line, "level=DEBUG") || !strings.Contains(line, "correlation_id=req-42") {
+			t.Errorf("line %q, want debug level and correlation ID", line)
+		}
+	}
+}
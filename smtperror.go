@@ -0,0 +1,69 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"net/textproto"
+	"strings"
+)
+
+// SMTPError is the reply of a mail host to a command that failed. Retrieve it from the error of
+// host validation with errors.As. It unwraps to the *textproto.Error of net/smtp.
+type SMTPError struct {
+	// Code is the reply code, ie. 450 or 550.
+	Code int
+
+	// EnhancedCode is the enhanced status code of RFC 3463 the reply starts with, ie. 5.1.1, or
+	// empty if the host doesn't send one.
+	EnhancedCode string
+
+	// Message is the text of the reply, the enhanced status code included.
+	Message string
+}
+
+func newSMTPError(err *textproto.Error) *SMTPError {
+	return &SMTPError{Code: err.Code, EnhancedCode: enhancedCode(err.Msg), Message: err.Msg}
+}
+
+func (e *SMTPError) Error() string {
+	return e.Unwrap().Error()
+}
+
+// Unwrap returns the reply as a *textproto.Error.
+func (e *SMTPError) Unwrap() error {
+	return &textproto.Error{Code: e.Code, Msg: e.Message}
+}
+
+// Temporary reports whether the reply is a transient negative completion reply (4xx), such as a
+// greylisting host asking to try again later. The command may succeed when retried.
+func (e *SMTPError) Temporary() bool {
+	return e.Code >= 400 && e.Code < 500
+}
+
+// Permanent reports whether the reply is a permanent negative completion reply (5xx), such as
+// an unknown recipient. Retrying the command will fail again.
+func (e *SMTPError) Permanent() bool {
+	return e.Code >= 500 && e.Code < 600
+}
+
+// enhancedCode returns the enhanced status code msg starts with, of the form class.subject.detail
+// where class is 2, 4 or 5, subject has at most three digits and detail at most three digits.
+func enhancedCode(msg string) string {
+	code := msg
+	if i := strings.IndexAny(msg, " \n"); i >= 0 {
+		code = msg[:i]
+	}
+	parts := strings.Split(code, ".")
+	if len(parts) != 3 || len(parts[0]) != 1 || strings.IndexByte("245", parts[0][0]) < 0 {
+		return ""
+	}
+	for _, p := range parts[1:] {
+		if len(p) == 0 || len(p) > 3 || strings.Trim(p, "0123456789") != "" {
+			return ""
+		}
+	}
+	return code
+}
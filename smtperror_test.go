@@ -0,0 +1,53 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"errors"
+	"net/textproto"
+	"testing"
+)
+
+func TestSMTPError(t *testing.T) {
+	tests := []struct {
+		name          string
+		err           *textproto.Error
+		wantEnhanced  string
+		wantTemporary bool
+		wantPermanent bool
+	}{
+		{"1", &textproto.Error{Code: 550, Msg: "5.1.1 no such user"}, "5.1.1", false, true},
+		{"2", &textproto.Error{Code: 450, Msg: "4.7.1 greylisted, try again later"}, "4.7.1", true, false},
+		{"3", &textproto.Error{Code: 451, Msg: "try again later"}, "", true, false},
+		{"4", &textproto.Error{Code: 554, Msg: "5.7.1\nrelay denied"}, "5.7.1", false, true},
+		{"5", &textproto.Error{Code: 550, Msg: "5.1.1234 no such user"}, "", false, true},
+		{"6", &textproto.Error{Code: 550, Msg: "3.1.1 no such user"}, "", false, true},
+		{"7", &textproto.Error{Code: 550, Msg: "5.1 no such user"}, "", false, true},
+		{"8", &textproto.Error{Code: 550, Msg: "5.x.1 no such user"}, "", false, true},
+		{"9", &textproto.Error{Code: 250, Msg: "2.0.0 OK"}, "2.0.0", false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			e := newSMTPError(tt.err)
+			if e.EnhancedCode != tt.wantEnhanced {
+				t.Errorf("SMTPError.EnhancedCode = %v, want %v", e.EnhancedCode, tt.wantEnhanced)
+			}
+			if got := e.Temporary(); got != tt.wantTemporary {
+				t.Errorf("SMTPError.Temporary() = %v, want %v", got, tt.wantTemporary)
+			}
+			if got := e.Permanent(); got != tt.wantPermanent {
+				t.Errorf("SMTPError.Permanent() = %v, want %v", got, tt.wantPermanent)
+			}
+			if e.Error() != tt.err.Error() {
+				t.Errorf("SMTPError.Error() = %v, want %v", e.Error(), tt.err.Error())
+			}
+			var tpErr *textproto.Error
+			if !errors.As(e, &tpErr) || *tpErr != *tt.err {
+				t.Errorf("errors.As(SMTPError) = %v, want %v", tpErr, tt.err)
+			}
+		})
+	}
+}
@@ -0,0 +1,97 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strings"
+)
+
+// SPFRecord is a parsed SPF record (RFC 7208), as published by the domains that send mail.
+type SPFRecord struct {
+	// Record is the TXT record, it is empty if the domain has no SPF record.
+	Record string
+
+	// All is the all mechanism with its qualifier, ie. -all, ~all, ?all or +all. It is empty if
+	// the record doesn't end in an all mechanism.
+	All string
+
+	// Senders are the mechanisms that authorize senders, without the + qualifier, ie.
+	// include:_spf.google.com, ip4:192.0.2.0/24 or mx.
+	Senders []string
+
+	// Redirect is the domain of the redirect modifier, whose record applies instead, if any.
+	Redirect string
+}
+
+// Exists reports whether the domain has an SPF record.
+func (r SPFRecord) Exists() bool {
+	return r.Record != ""
+}
+
+// Strict reports whether mail from senders that aren't listed fails (-all) or soft fails (~all).
+func (r SPFRecord) Strict() bool {
+	return r.All == "-all" || r.All == "~all"
+}
+
+// ParseSPF parses an SPF record, ie. v=spf1 include:_spf.google.com ~all.
+func ParseSPF(record string) (*SPFRecord, error) {
+	if !isSPF(record) {
+		return nil, fmt.Errorf("invalid SPF record, missing v=spf1: %q", record)
+	}
+	r := &SPFRecord{Record: record}
+	for _, term := range strings.Fields(record)[1:] {
+		if i := strings.IndexByte(term, '='); i > 0 && !strings.ContainsAny(term[:i], ":/") {
+			if strings.EqualFold(term[:i], "redirect") {
+				r.Redirect = term[i+1:]
+			}
+			continue
+		}
+		qualifier, mechanism := "+", term
+		if strings.IndexByte("+-~?", term[0]) >= 0 {
+			qualifier, mechanism = term[:1], term[1:]
+		}
+		name := mechanism
+		if i := strings.IndexAny(name, ":/"); i >= 0 {
+			name = name[:i]
+		}
+		switch strings.ToLower(name) {
+		case "all":
+			r.All = qualifier + "all"
+		case "include", "a", "mx", "ptr", "ip4", "ip6", "exists":
+			if qualifier == "+" {
+				r.Senders = append(r.Senders, mechanism)
+			}
+		default:
+			return nil, fmt.Errorf("invalid SPF record, unknown mechanism %q", term)
+		}
+	}
+	return r, nil
+}
+
+// isSPF reports whether the TXT record is an SPF record.
+func isSPF(txt string) bool {
+	return len(txt) >= 6 && strings.EqualFold(txt[:6], "v=spf1") && (len(txt) == 6 || txt[6] == ' ')
+}
+
+// parseSPFRecords returns the SPF record among the TXT records of a domain, or an empty record. A
+// domain must have at most one.
+func parseSPFRecords(domain string, txt []string) (*SPFRecord, error) {
+	var record string
+	for _, t := range txt {
+		if !isSPF(t) {
+			continue
+		}
+		if record != "" {
+			return nil, fmt.Errorf("domain %s has multiple SPF records", domain)
+		}
+		record = t
+	}
+	if record == "" {
+		return &SPFRecord{}, nil
+	}
+	return ParseSPF(record)
+}
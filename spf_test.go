@@ -0,0 +1,46 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestParseSPF(t *testing.T) {
+	tests := []struct {
+		name    string
+		record  string
+		want    *SPFRecord
+		strict  bool
+		wantErr bool
+	}{
+		{"1", "v=spf1 include:_spf.google.com ~all", &SPFRecord{"v=spf1 include:_spf.google.com ~all", "~all", []string{"include:_spf.google.com"}, ""}, true, false},
+		{"2", "v=spf1 mx a:mail.example.com ip4:192.0.2.0/24 ip6:2001:db8::/32 -all", &SPFRecord{"v=spf1 mx a:mail.example.com ip4:192.0.2.0/24 ip6:2001:db8::/32 -all", "-all", []string{"mx", "a:mail.example.com", "ip4:192.0.2.0/24", "ip6:2001:db8::/32"}, ""}, true, false},
+		{"3", "V=SPF1 +mx -ip4:192.0.2.1 ?all", &SPFRecord{"V=SPF1 +mx -ip4:192.0.2.1 ?all", "?all", []string{"mx"}, ""}, false, false},
+		{"4", "v=spf1 redirect=_spf.example.com", &SPFRecord{"v=spf1 redirect=_spf.example.com", "", nil, "_spf.example.com"}, false, false},
+		{"5", "v=spf1 a/24 exp=explain.example.com +all", &SPFRecord{"v=spf1 a/24 exp=explain.example.com +all", "+all", []string{"a/24"}, ""}, false, false},
+		{"6", "v=spf1", &SPFRecord{"v=spf1", "", nil, ""}, false, false},
+		{"7", "v=spf10 -all", nil, false, true},
+		{"8", "google-site-verification=abc", nil, false, true},
+		{"9", "v=spf1 foo:bar -all", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := ParseSPF(tt.record)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ParseSPF() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("ParseSPF() = %+v, want %+v", got, tt.want)
+			}
+			if got != nil && got.Strict() != tt.strict {
+				t.Errorf("SPFRecord.Strict() = %v, want %v", got.Strict(), tt.strict)
+			}
+		})
+	}
+}
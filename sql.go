@@ -0,0 +1,60 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"database/sql/driver"
+	"fmt"
+)
+
+// Value implements driver.Valuer, an email address is stored as a string.
+func (e EmailAddress) Value() (driver.Value, error) {
+	return e.String(), nil
+}
+
+// Scan implements sql.Scanner for string and []byte values. The value is parsed and validated
+// locally, an empty value results in the zero EmailAddress. Use NullEmailAddress for columns that
+// may be NULL.
+func (e *EmailAddress) Scan(src interface{}) error {
+	switch v := src.(type) {
+	case string:
+		return e.set(v)
+	case []byte:
+		return e.set(string(v))
+	case nil:
+		return fmt.Errorf("cannot scan NULL into EmailAddress, use NullEmailAddress")
+	default:
+		return fmt.Errorf("cannot scan %T into EmailAddress", src)
+	}
+}
+
+// NullEmailAddress is an EmailAddress that may be NULL, like sql.NullString.
+type NullEmailAddress struct {
+	EmailAddress EmailAddress
+	Valid        bool // Valid is true if EmailAddress is not NULL
+}
+
+// Value implements driver.Valuer.
+func (n NullEmailAddress) Value() (driver.Value, error) {
+	if !n.Valid {
+		return nil, nil
+	}
+	return n.EmailAddress.Value()
+}
+
+// Scan implements sql.Scanner.
+func (n *NullEmailAddress) Scan(src interface{}) error {
+	if src == nil {
+		n.EmailAddress, n.Valid = EmailAddress{}, false
+		return nil
+	}
+	if err := n.EmailAddress.Scan(src); err != nil {
+		n.Valid = false
+		return err
+	}
+	n.Valid = true
+	return nil
+}
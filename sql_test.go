@@ -0,0 +1,69 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"database/sql/driver"
+	"testing"
+)
+
+func TestEmailAddress_Scan(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    EmailAddress
+		wantErr bool
+	}{
+		{"1", "foo@bar.com", EmailAddress{"foo", "bar.com"}, false},
+		{"2", []byte("foo@bar.com"), EmailAddress{"foo", "bar.com"}, false},
+		{"3", "", EmailAddress{}, false},
+		{"4", "foo", EmailAddress{"old", "bar.com"}, true},
+		{"5", nil, EmailAddress{"old", "bar.com"}, true},
+		{"6", int64(42), EmailAddress{"old", "bar.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := EmailAddress{"old", "bar.com"}
+			if err := got.Scan(tt.src); (err != nil) != tt.wantErr {
+				t.Errorf("EmailAddress.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("EmailAddress.Scan() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNullEmailAddress(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     interface{}
+		want    NullEmailAddress
+		value   driver.Value
+		wantErr bool
+	}{
+		{"1", "foo@bar.com", NullEmailAddress{EmailAddress{"foo", "bar.com"}, true}, "foo@bar.com", false},
+		{"2", nil, NullEmailAddress{}, nil, false},
+		{"3", "foo", NullEmailAddress{EmailAddress{"old", "bar.com"}, false}, nil, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := NullEmailAddress{EmailAddress{"old", "bar.com"}, true}
+			if err := got.Scan(tt.src); (err != nil) != tt.wantErr {
+				t.Errorf("NullEmailAddress.Scan() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if got != tt.want {
+				t.Errorf("NullEmailAddress.Scan() = %v, want %v", got, tt.want)
+			}
+			v, err := got.Value()
+			if err != nil || v != tt.value {
+				t.Errorf("NullEmailAddress.Value() = %v, %v, want %v", v, err, tt.value)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"regexp"
+)
+
+// validTableRegexp matches the table names accepted by WriteResultsSQL.
+var validTableRegexp = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// WriteResultsSQL writes host check results to the table of a SQL database in a single
+// transaction. The table is created if it doesn't exist, with the schema:
+//
+//	CREATE TABLE IF NOT EXISTS results (
+//		email      TEXT NOT NULL, -- the address, ie. jane@example.com
+//		local_part TEXT NOT NULL,
+//		domain     TEXT NOT NULL,
+//		status     TEXT NOT NULL, -- verified, invalid or unverifiable
+//		code       TEXT,          -- the Code of the error, ie. EA3003, NULL without error
+//		error      TEXT           -- the error message, NULL without error
+//	)
+//
+// Statements use ? placeholders, as supported by SQLite and MySQL drivers. No driver is imported
+// by this package, so register one such as modernc.org/sqlite or github.com/mattn/go-sqlite3 and
+// open db with it. Results without an address are skipped. Parquet is not supported, it can be
+// produced from the SQLite database by most warehouse tooling.
+func WriteResultsSQL(ctx context.Context, db *sql.DB, table string, results []HostResult) error {
+	if !validTableRegexp.MatchString(table) {
+		return fmt.Errorf("invalid table name %q", table)
+	}
+	tx, err := db.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback() // #nosec
+
+	// #nosec G202 -- the table name is validated above
+	if _, err = tx.ExecContext(ctx, "CREATE TABLE IF NOT EXISTS "+table+" (email TEXT NOT NULL, local_part TEXT NOT NULL, domain TEXT NOT NULL, status TEXT NOT NULL, code TEXT, error TEXT)"); err != nil {
+		return fmt.Errorf("failed creating table %s: %w", table, err)
+	}
+	// #nosec G202 -- the table name is validated above
+	stmt, err := tx.PrepareContext(ctx, "INSERT INTO "+table+" (email, local_part, domain, status, code, error) VALUES (?, ?, ?, ?, ?, ?)")
+	if err != nil {
+		return fmt.Errorf("failed preparing insert into %s: %w", table, err)
+	}
+	defer stmt.Close()
+
+	for _, r := range results {
+		if r.Address == nil {
+			continue
+		}
+		var code, msg sql.NullString
+		if r.Err != nil {
+			code = sql.NullString{String: string(ErrorCode(r.Err)), Valid: ErrorCode(r.Err) != ""}
+			msg = sql.NullString{String: r.Err.Error(), Valid: true}
+		}
+		if _, err = stmt.ExecContext(ctx, r.Address.String(), r.Address.LocalPart, r.Address.Domain, r.Status.String(), code, msg); err != nil {
+			return fmt.Errorf("failed inserting %s: %w", r.Address, err)
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,100 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// testDriver is a database/sql driver that records the statements it executes.
+type testDriver struct {
+	mu        sync.Mutex
+	execs     [][]driver.Value
+	queries   []string
+	committed bool
+}
+
+func (d *testDriver) Open(string) (driver.Conn, error) { return testConn{d}, nil }
+
+type testConn struct{ d *testDriver }
+
+func (c testConn) Prepare(query string) (driver.Stmt, error) { return testStmt{c.d, query}, nil }
+func (c testConn) Close() error                              { return nil }
+func (c testConn) Begin() (driver.Tx, error)                 { return testTx{c.d}, nil }
+
+type testTx struct{ d *testDriver }
+
+func (t testTx) Commit() error {
+	t.d.mu.Lock()
+	defer t.d.mu.Unlock()
+	t.d.committed = true
+	return nil
+}
+func (t testTx) Rollback() error { return nil }
+
+type testStmt struct {
+	d     *testDriver
+	query string
+}
+
+func (s testStmt) Close() error  { return nil }
+func (s testStmt) NumInput() int { return -1 }
+func (s testStmt) Exec(args []driver.Value) (driver.Result, error) {
+	s.d.mu.Lock()
+	defer s.d.mu.Unlock()
+	s.d.queries = append(s.d.queries, s.query)
+	if len(args) > 0 {
+		s.d.execs = append(s.d.execs, args)
+	}
+	return driver.RowsAffected(1), nil
+}
+func (s testStmt) Query([]driver.Value) (driver.Rows, error) { return nil, errors.New("not supported") }
+
+var testSQLDriver = &testDriver{}
+
+func init() {
+	sql.Register("emailaddress-test", testSQLDriver)
+}
+
+func TestWriteResultsSQL(t *testing.T) {
+	db, err := sql.Open("emailaddress-test", "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	results := []HostResult{
+		{Address: &EmailAddress{"jane", "example.com"}, Status: HostVerified},
+		{Address: &EmailAddress{"fake", "example.com"}, Status: HostInvalid, Err: newError(CodeMailboxRejected, errors.New("550 no such user"))},
+		{Address: &EmailAddress{"joe", "example.org"}, Status: HostUnverifiable, Err: errors.New("timeout")},
+		{Address: nil, Status: HostInvalid},
+	}
+	if err := WriteResultsSQL(context.Background(), db, "results", results); err != nil {
+		t.Fatalf("WriteResultsSQL() error = %v", err)
+	}
+	want := [][]driver.Value{
+		{"jane@example.com", "jane", "example.com", "verified", nil, nil},
+		{"fake@example.com", "fake", "example.com", "invalid", "EA3003", "550 no such user"},
+		{"joe@example.org", "joe", "example.org", "unverifiable", nil, "timeout"},
+	}
+	if !reflect.DeepEqual(testSQLDriver.execs, want) {
+		t.Errorf("WriteResultsSQL() inserted %v, want %v", testSQLDriver.execs, want)
+	}
+	if !strings.HasPrefix(testSQLDriver.queries[0], "CREATE TABLE IF NOT EXISTS results (") || !testSQLDriver.committed {
+		t.Errorf("WriteResultsSQL() executed %v, committed %v", testSQLDriver.queries, testSQLDriver.committed)
+	}
+
+	if err := WriteResultsSQL(context.Background(), db, "results; DROP TABLE x", results); err == nil {
+		t.Errorf("WriteResultsSQL() error = nil for an invalid table name")
+	}
+}
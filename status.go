@@ -0,0 +1,55 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "errors"
+
+// ErrUnverifiable is returned when it could not be determined whether an email address is valid,
+// for instance because of a temporary DNS failure.
+var ErrUnverifiable = errors.New("could not be verified")
+
+// HostStatus is the outcome of checking an email address against its remote host.
+type HostStatus int
+
+const (
+	// HostUnverifiable indicates that it could not be determined whether the email address is
+	// valid.
+	HostUnverifiable HostStatus = iota
+
+	// HostVerified indicates that the host accepted the email address.
+	HostVerified
+
+	// HostInvalid indicates that the domain has no mail host, is parked or the host rejected the
+	// email address.
+	HostInvalid
+)
+
+func (s HostStatus) String() string {
+	switch s {
+	case HostVerified:
+		return "verified"
+	case HostInvalid:
+		return "invalid"
+	default:
+		return "unverifiable"
+	}
+}
+
+// MXRecord is a mail host of a domain, see LookupMXRecords. The resolver interface of the net
+// package does not report the TTL of records, so it is not known.
+type MXRecord struct {
+	// Host is the host name of an MX record, with a trailing dot, or an address of the domain
+	// when Fallback is true.
+	Host string
+
+	// Pref is the preference of the MX record, lower values are preferred. It is zero for
+	// addresses.
+	Pref uint16
+
+	// Fallback is true if the domain has no MX records and mail is delivered to the address of
+	// the domain itself (RFC 5321 section 5.1).
+	Fallback bool
+}
@@ -0,0 +1,81 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bytes"
+	"io"
+)
+
+// findReaderBufferSize is the size of the buffer of FindReader.
+const findReaderBufferSize = 64 << 10
+
+// FindReader finds email addresses in r like Find, without reading all of r in memory, and calls
+// fn with every address in the order they appear. It stops when fn returns false. Input is read
+// in chunks of at most 64KiB, which are split at whitespace so addresses spanning two reads are
+// still found. It returns the first error of r other than io.EOF, after searching the bytes read
+// with it.
+func FindReader(r io.Reader, fn func(*EmailAddress) bool, opts ...ParseOption) error {
+	return findReader(r, findReaderBufferSize, fn, newParseOptions(opts))
+}
+
+func findReader(r io.Reader, size int, fn func(*EmailAddress) bool, o parseOptions) error {
+	buf := make([]byte, size)
+	n, found := 0, 0
+	for {
+		m, err := r.Read(buf[n:])
+		n += m
+
+		// The bytes read along with an error are searched too, as after io.EOF.
+		end := n
+		if err == nil {
+			end = chunkEnd(buf[:n], n == len(buf))
+		}
+		more := true
+		findAll(findCommonRegexp, o.prepare(buf[:end]), func(r []byte) bool {
+			if o.lengthLimits() && len(r) > maxAddressLength {
+				return true
+			}
+			e, err := o.parse(string(r))
+			if err != nil || o.skip(e) {
+				return true
+			}
+			found++
+			more = fn(e) && (!o.hardened || found < maxHardenedResults)
+			return more
+		})
+		if !more || err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+		n = copy(buf, buf[end:n])
+	}
+}
+
+// chunkEnd returns the length of the part of b that can be searched without splitting an address
+// or HTML entity, which is up to the last whitespace. If b fills the buffer and has no whitespace,
+// it is split after the last byte that can't be part of an address, or searched as a whole.
+func chunkEnd(b []byte, full bool) int {
+	if i := bytes.LastIndexAny(b, " \t\r\n"); i >= 0 {
+		return i + 1
+	}
+	if !full {
+		return 0
+	}
+	for i := len(b) - 1; i >= 0; i-- {
+		if !isAddressByte(b[i]) {
+			return i + 1
+		}
+	}
+	return len(b)
+}
+
+// isAddressByte reports whether c can be part of an address matched by Find.
+func isAddressByte(c byte) bool {
+	return isAlphanumeric(c) || bytes.IndexByte([]byte("._%+-@"), c) >= 0
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/iotest"
+)
+
+func TestFindReader(t *testing.T) {
+	text := "Send me an email at foo@bar.com, or foo&#64;domain.com.\n" +
+		strings.Repeat("filler ", 10) + "a.much.longer.address@sub.example.org\tbar@baz.co.uk"
+	tests := []struct {
+		name string
+		size int
+		opts []ParseOption
+		text string
+	}{
+		{"1", findReaderBufferSize, nil, text},
+		{"2", 40, nil, text},
+		{"3", 50, nil, text},
+		{"4", 40, []ParseOption{WithHTMLEntityDecoding()}, text},
+		{"5", 40, nil, "foo@bar.com;" + strings.Repeat("x", 20) + ";baz@bar.com"},
+		{"6", 40, []ParseOption{WithHardening()}, strings.Repeat("foo@bar.com ", 1200)},
+		{"7", 40, nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []*EmailAddress
+			r := iotest.HalfReader(strings.NewReader(tt.text))
+			err := findReader(r, tt.size, func(e *EmailAddress) bool {
+				got = append(got, e)
+				return true
+			}, newParseOptions(tt.opts))
+			if err != nil {
+				t.Fatalf("FindReader() error = %v", err)
+			}
+			if want := Find([]byte(tt.text), false, tt.opts...); !reflect.DeepEqual(got, want) {
+				t.Errorf("FindReader() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestFindReader_stop(t *testing.T) {
+	var got []*EmailAddress
+	err := FindReader(strings.NewReader("foo@bar.com baz@bar.com qux@bar.com"), func(e *EmailAddress) bool {
+		got = append(got, e)
+		return len(got) < 2
+	})
+	if err != nil || len(got) != 2 {
+		t.Errorf("FindReader() = %v, %v, want to stop after 2 addresses", got, err)
+	}
+
+	want := errors.New("read failed")
+	err = FindReader(iotest.ErrReader(want), func(*EmailAddress) bool { return true })
+	if err != want {
+		t.Errorf("FindReader() error = %v, want %v", err, want)
+	}
+
+	got = nil
+	err = FindReader(&dataErrReader{"foo@bar.com baz@bar.com", want}, func(e *EmailAddress) bool {
+		got = append(got, e)
+		return true
+	})
+	if err != want || len(got) != 2 {
+		t.Errorf("FindReader() = %v, %v, want 2 addresses and %v", got, err, want)
+	}
+}
+
+// dataErrReader returns all of data together with err on the first read.
+type dataErrReader struct {
+	data string
+	err  error
+}
+
+func (r *dataErrReader) Read(p []byte) (int, error) {
+	return copy(p, r.data), r.err
+}
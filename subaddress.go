@@ -0,0 +1,50 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "strings"
+
+// DefaultTagSeparator is the separator of subaddresses used by most mail providers, as in
+// user+newsletter@domain.com (RFC 5233).
+const DefaultTagSeparator = '+'
+
+// LocalPartBase returns the local part without the tag of a subaddress, ie. user for
+// user+newsletter@domain.com. See SplitTag.
+func (e EmailAddress) LocalPartBase() string {
+	base, _ := e.SplitTag(DefaultTagSeparator)
+	return base
+}
+
+// Tag returns the tag of a subaddress, ie. newsletter for user+newsletter@domain.com, or an empty
+// string if the address has no tag. See SplitTag.
+func (e EmailAddress) Tag() string {
+	_, tag := e.SplitTag(DefaultTagSeparator)
+	return tag
+}
+
+// StripTag returns the email address without the tag of a subaddress, ie. user@domain.com for
+// user+newsletter@domain.com. Unlike Canonical it keeps the case and the dots of the local part.
+func (e EmailAddress) StripTag() EmailAddress {
+	e.LocalPart = e.LocalPartBase()
+	return e
+}
+
+// SplitTag splits the local part at the first separator into the base and the tag of a
+// subaddress, ie. '-' for systems such as qmail and Yahoo or '=' for others. The tag is empty if
+// the local part has no separator, starts with it or is quoted.
+func (e EmailAddress) SplitTag(separator byte) (base, tag string) {
+	return splitTag(e.LocalPart, separator)
+}
+
+func splitTag(local string, separator byte) (base, tag string) {
+	if separator == 0 || strings.HasPrefix(local, `"`) {
+		return local, ""
+	}
+	if i := strings.IndexByte(local, separator); i > 0 {
+		return local[:i], local[i+1:]
+	}
+	return local, ""
+}
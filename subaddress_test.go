@@ -0,0 +1,62 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "testing"
+
+func TestEmailAddress_Tag(t *testing.T) {
+	tests := []struct {
+		name  string
+		email EmailAddress
+		base  string
+		tag   string
+	}{
+		{"1", EmailAddress{"user+newsletter", "domain.com"}, "user", "newsletter"},
+		{"2", EmailAddress{"user", "domain.com"}, "user", ""},
+		{"3", EmailAddress{"User.Name+a+b", "domain.com"}, "User.Name", "a+b"},
+		{"4", EmailAddress{"user+", "domain.com"}, "user", ""},
+		{"5", EmailAddress{"+user", "domain.com"}, "+user", ""},
+		{"6", EmailAddress{`"user+tag"`, "domain.com"}, `"user+tag"`, ""},
+		{"7", EmailAddress{"user-tag", "domain.com"}, "user-tag", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.email.LocalPartBase(); got != tt.base {
+				t.Errorf("EmailAddress.LocalPartBase() = %v, want %v", got, tt.base)
+			}
+			if got := tt.email.Tag(); got != tt.tag {
+				t.Errorf("EmailAddress.Tag() = %v, want %v", got, tt.tag)
+			}
+			want := EmailAddress{tt.base, tt.email.Domain}
+			if got := tt.email.StripTag(); got != want {
+				t.Errorf("EmailAddress.StripTag() = %v, want %v", got, want)
+			}
+		})
+	}
+}
+
+func TestEmailAddress_SplitTag(t *testing.T) {
+	tests := []struct {
+		name      string
+		email     EmailAddress
+		separator byte
+		base      string
+		tag       string
+	}{
+		{"1", EmailAddress{"user-tag", "yahoo.com"}, '-', "user", "tag"},
+		{"2", EmailAddress{"user=tag", "domain.com"}, '=', "user", "tag"},
+		{"3", EmailAddress{"user+tag", "domain.com"}, '-', "user+tag", ""},
+		{"4", EmailAddress{"user+tag", "domain.com"}, 0, "user+tag", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			base, tag := tt.email.SplitTag(tt.separator)
+			if base != tt.base || tag != tt.tag {
+				t.Errorf("EmailAddress.SplitTag() = %v, %v, want %v, %v", base, tag, tt.base, tt.tag)
+			}
+		})
+	}
+}
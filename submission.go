@@ -0,0 +1,119 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+)
+
+// WithImplicitTLS makes connections to mail hosts start with a TLS handshake, as the submission
+// port 465 expects (RFC 8314), instead of upgrading with STARTTLS. Use it with WithPort(465). The
+// certificate is verified as with STARTTLS, see WithTLSConfig and WithRequireTLS.
+func WithImplicitTLS() Option {
+	return func(v *Verifier) {
+		v.implicitTLS = true
+	}
+}
+
+// WithAuth authenticates with a after greeting the mail host, ie. with smtp.PlainAuth to probe
+// through your own submission server, see WithRelay. The host must announce AUTH, most do so
+// only over TLS. A rejected authentication fails the probe with CodeSMTPRejected.
+func WithAuth(a smtp.Auth) Option {
+	return func(v *Verifier) {
+		v.auth = a
+	}
+}
+
+// WithRelay probes host, ie. the submission server of your own mail infrastructure, instead of
+// the mail hosts of the domain. The domain must still have mail hosts. Whether the answer tells
+// anything about the recipient depends on the relay, which may accept any recipient.
+func WithRelay(host string) Option {
+	return func(v *Verifier) {
+		v.relay = host
+	}
+}
+
+// implicitTLSPort is the submission port that expects implicit TLS (RFC 8314).
+const implicitTLSPort = 465
+
+// RelayValidator is a Verifier that probes recipients through a relay, the SMTP server of your
+// own mail infrastructure, instead of the mail hosts of their domain, which can rarely be
+// reached on port 25 from cloud hosts. The domain must still have mail hosts. Relays that accept
+// any recipient make every address deliverable, or risky for Verify since the relay looks like a
+// catch-all host, so use one that checks recipients, ie. with sender callouts.
+type RelayValidator struct {
+	*Verifier
+}
+
+// NewRelayValidator returns a RelayValidator that probes through host on port, authenticating
+// with auth unless it is nil. Port 465 uses implicit TLS, other ports upgrade with STARTTLS when
+// the relay offers it. opts configure the Verifier further, ie. with WithSenderAddress.
+func NewRelayValidator(host string, port int, auth smtp.Auth, opts ...Option) *RelayValidator {
+	relayOpts := []Option{WithRelay(host), WithPort(port), WithAuth(auth)}
+	if port == implicitTLSPort {
+		relayOpts = append(relayOpts, WithImplicitTLS())
+	}
+	return &RelayValidator{Verifier: NewVerifier(append(relayOpts, opts...)...)}
+}
+
+// probeTargets returns the hosts to probe for a domain with the mail hosts hosts.
+func (v *Verifier) probeTargets(hosts []string) []string {
+	if v.relay != "" {
+		return []string{v.relay}
+	}
+	return hosts
+}
+
+// authenticate performs the AUTH exchange of RFC 4954 with a, like the Auth method of
+// smtp.Client, which can't be used since the Client doesn't know the connection was upgraded to
+// TLS.
+func authenticate(text *textproto.Conn, a smtp.Auth, host string, caps *Capabilities) error {
+	if !caps.Has("AUTH") {
+		return fmt.Errorf("%s does not support AUTH", host)
+	}
+	mech, resp, err := a.Start(&smtp.ServerInfo{
+		Name: strings.TrimSuffix(host, "."),
+		TLS:  caps.TLS != nil,
+		Auth: strings.Fields(caps.Extensions["AUTH"]),
+	})
+	if err != nil {
+		return err
+	}
+	code, msg, err := exchange(text, 0, "%s", strings.TrimSpace("AUTH "+mech+" "+base64.StdEncoding.EncodeToString(resp)))
+	for err == nil {
+		var challenge []byte
+		switch code {
+		case 334:
+			challenge, err = base64.StdEncoding.DecodeString(msg)
+		case 235:
+			challenge = []byte(msg) // the final reply is not encoded
+		default:
+			err = &textproto.Error{Code: code, Msg: msg}
+		}
+		if err == nil {
+			resp, err = a.Next(challenge, code == 334)
+		}
+		if err != nil {
+			var tpErr *textproto.Error
+			if !errors.As(err, &tpErr) {
+				exchange(text, 501, "*") // #nosec cancel the exchange
+			}
+			return err
+		}
+		if resp == nil {
+			return nil
+		}
+		code, msg, err = exchange(text, 0, "%s", base64.StdEncoding.EncodeToString(resp))
+	}
+	return err
+}
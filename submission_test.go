@@ -0,0 +1,148 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/smtp"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithImplicitTLS(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	s := newTestServer(t, "127.0.0.1:0", withTLS(&tls.Config{Certificates: srv.TLS.Certificates}, true))
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		valid   bool
+		wantErr bool
+	}{
+		{"1", []Option{WithImplicitTLS(), WithTLSConfig(&tls.Config{RootCAs: roots})}, true, false},
+		{"2", []Option{WithImplicitTLS()}, false, false},
+		{"3", []Option{WithImplicitTLS(), WithRequireTLS()}, false, true},
+		{"4", nil, false, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append([]Option{WithPort(s.port()), WithCommandTimeout(500 * time.Millisecond)}, tt.opts...)...)
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+			caps, err := v.InspectMX(context.Background(), "example.com")
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Verifier.InspectMX() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if err != nil {
+				return
+			}
+			if caps.TLS == nil || caps.TLS.CertificateValid != tt.valid {
+				t.Errorf("Verifier.InspectMX() TLS = %+v, want valid %v", caps.TLS, tt.valid)
+			}
+		})
+	}
+}
+
+func TestWithAuth(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.auth = func(line string) string {
+		// AUTH PLAIN with the base64 of "\x00user\x00secret".
+		if line == "AUTH PLAIN AHVzZXIAc2VjcmV0" {
+			return "235 2.7.0 authentication successful"
+		}
+		return "535 5.7.8 authentication credentials invalid"
+	}
+	relay := s.host()
+
+	tests := []struct {
+		name     string
+		opts     []Option
+		wantCode Code
+	}{
+		{"1", []Option{WithRelay(relay), WithAuth(smtp.PlainAuth("", "user", "secret", relay))}, ""},
+		{"2", []Option{WithRelay(relay), WithAuth(smtp.PlainAuth("", "user", "wrong", relay))}, CodeSMTPRejected},
+		{"3", []Option{WithRelay(relay), WithAuth(smtp.PlainAuth("", "user", "secret", "mail.example.com"))}, CodeSMTPFailure},
+		{"4", []Option{WithRelay(relay)}, ""},
+		{"5", nil, CodeSMTPUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append([]Option{WithPort(s.port()), WithDialTimeout(500 * time.Millisecond)}, tt.opts...)...)
+			// The mail host of the domain can't be reached, only the relay can.
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "192.0.2.1", Pref: 10}}}}
+			err := v.ValidateHost(context.Background(), EmailAddress{"info", "example.com"})
+			if ErrorCode(err) != tt.wantCode || (err == nil) != (tt.wantCode == "") {
+				t.Errorf("Verifier.ValidateHost() error = %v, want code %v", err, tt.wantCode)
+			}
+		})
+	}
+
+	if err := authenticate(nil, smtp.PlainAuth("", "user", "secret", relay), relay, &Capabilities{}); err == nil {
+		t.Errorf("authenticate() error = nil for a host without AUTH")
+	}
+}
+
+func TestWithAuth_transcript(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.auth = func(line string) string { return "235 2.7.0 authentication successful" }
+	var buf bytes.Buffer
+	v := NewVerifier(WithRelay(s.host()), WithPort(s.port()), WithAuth(smtp.PlainAuth("", "user", "secret", s.host())), WithTranscript(&buf))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "192.0.2.1", Pref: 10}}}}
+	if err := v.ValidateHost(context.Background(), EmailAddress{"info", "example.com"}); err != nil {
+		t.Fatalf("Verifier.ValidateHost() error = %v", err)
+	}
+	if got := buf.String(); strings.Contains(got, "AHVzZXIAc2VjcmV0") || !strings.Contains(got, "C: AUTH PLAIN ***\n") {
+		t.Errorf("WithTranscript() = %q, want the credentials redacted", got)
+	}
+	if got := s.commands(); len(got) < 2 || got[1] != "AUTH PLAIN AHVzZXIAc2VjcmV0" {
+		t.Errorf("commands = %q, want the credentials sent", got)
+	}
+}
+
+func TestNewRelayValidator(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.auth = func(string) string { return "235 2.7.0 authentication successful" }
+	s.rcpt = func(addr string) string {
+		if addr == "info@example.com" {
+			return "250 OK"
+		}
+		return "550 5.1.1 no such user"
+	}
+	r := NewRelayValidator(s.host(), s.port(), smtp.PlainAuth("", "user", "secret", s.host()), WithDialTimeout(500*time.Millisecond))
+	r.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "192.0.2.1", Pref: 10}}}}
+
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want HostStatus
+	}{
+		{"1", EmailAddress{"info", "example.com"}, HostVerified},
+		{"2", EmailAddress{"nobody", "example.com"}, HostInvalid},
+		{"3", EmailAddress{"info", "example.net"}, HostInvalid},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got, err := r.CheckHost(context.Background(), tt.e); got != tt.want {
+				t.Errorf("RelayValidator.CheckHost() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+	if got := NewRelayValidator("smtp.example.com", 465, nil); !got.implicitTLS || got.port != 465 || got.relay != "smtp.example.com" {
+		t.Errorf("NewRelayValidator() = %+v, want implicit TLS to port 465", got.Verifier)
+	}
+}
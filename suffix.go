@@ -0,0 +1,44 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import "strings"
+
+// SuffixPolicy decides whether FindWithSuffix accepts an address by its domain, which is
+// lowercased.
+type SuffixPolicy func(domain string) bool
+
+var (
+	// ICANNSuffixes accepts domains with a public suffix managed by ICANN, such as foo.co.uk, as
+	// FindWithIcannSuffix does.
+	ICANNSuffixes SuffixPolicy = func(domain string) bool {
+		_, icann := publicSuffix(domain)
+		return icann
+	}
+
+	// PublicSuffixes accepts domains with any suffix of the public suffix list, those managed by
+	// ICANN and private ones such as foo.blogspot.com, but not domains such as foo.fakesuffix.
+	PublicSuffixes SuffixPolicy = func(domain string) bool {
+		suffix, icann := publicSuffix(domain)
+		// Suffixes that are not in the list are the last label of the domain, which is never a
+		// private suffix.
+		return icann || strings.IndexByte(suffix, '.') >= 0
+	}
+)
+
+// SuffixList returns a SuffixPolicy accepting domains with one of the suffixes in list, ie. the
+// internal domains of a company that are not in the public suffix list.
+func SuffixList(list DomainList) SuffixPolicy {
+	return func(domain string) bool {
+		return listed(list, strings.TrimSuffix(domain, "."))
+	}
+}
+
+// FindWithSuffix is like FindWithIcannSuffix, but returns the email addresses whose domain is
+// accepted by policy, ie. PublicSuffixes to include private suffixes.
+func FindWithSuffix(haystack []byte, policy SuffixPolicy, validateHost bool, opts ...ParseOption) (emails []*EmailAddress) {
+	return FindWithOptions(haystack, findOptionsOf(validateHost, opts, WithSuffixPolicy(policy))...)
+}
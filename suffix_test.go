@@ -0,0 +1,32 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestFindWithSuffix(t *testing.T) {
+	text := []byte("foo@bar.com foo@bar.blogspot.com foo@bar.fakesuffix foo@x.corp.internal")
+	tests := []struct {
+		name   string
+		policy SuffixPolicy
+		want   []*EmailAddress
+	}{
+		{"1", ICANNSuffixes, []*EmailAddress{{"foo", "bar.com"}}},
+		{"2", PublicSuffixes, []*EmailAddress{{"foo", "bar.com"}, {"foo", "bar.blogspot.com"}}},
+		{"3", SuffixList(NewDomainList([]string{"corp.internal"})), []*EmailAddress{{"foo", "x.corp.internal"}}},
+		{"4", func(string) bool { return false }, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := FindWithSuffix(text, tt.policy, false); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("FindWithSuffix() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,32 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+)
+
+// FetchSuffixList downloads and parses the public suffix list at url, ie. PublicSuffixListURL.
+// Pass the result to SetSuffixProvider to use it. See ParseSuffixList.
+func FetchSuffixList(ctx context.Context, url string) (SuffixProvider, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetching public suffix list: %s", resp.Status)
+	}
+	return ParseSuffixList(resp.Body)
+}
@@ -0,0 +1,38 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestFetchSuffixList(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/public_suffix_list.dat" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, testSuffixList) // #nosec
+	}))
+	defer srv.Close()
+
+	p, err := FetchSuffixList(context.Background(), srv.URL+"/public_suffix_list.dat")
+	if err != nil {
+		t.Fatalf("FetchSuffixList() error = %v", err)
+	}
+	if suffix, icann := p.PublicSuffix("x.corp.internal"); suffix != "corp.internal" || icann {
+		t.Errorf("PublicSuffix() = %v, %v, want corp.internal, false", suffix, icann)
+	}
+	if _, err := FetchSuffixList(context.Background(), srv.URL+"/missing"); err == nil {
+		t.Error("FetchSuffixList() of a missing list error = nil, want an error")
+	}
+}
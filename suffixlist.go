@@ -0,0 +1,184 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync/atomic"
+
+	"golang.org/x/net/idna"
+	"golang.org/x/net/publicsuffix"
+)
+
+// PublicSuffixListURL is the location of the upstream public suffix list, see FetchSuffixList.
+const PublicSuffixListURL = "https://publicsuffix.org/list/public_suffix_list.dat"
+
+// SuffixProvider returns the public suffix of a domain, such as co.uk for foo.co.uk, and whether
+// it is managed by ICANN. It has the signature of PublicSuffix of golang.org/x/net/publicsuffix,
+// domains are lowercased. Implementations must be safe for concurrent use.
+type SuffixProvider interface {
+	PublicSuffix(domain string) (suffix string, icann bool)
+}
+
+// bundledSuffixes is the public suffix list of golang.org/x/net/publicsuffix, which is as old as
+// the version of the module.
+type bundledSuffixes struct{}
+
+func (bundledSuffixes) PublicSuffix(domain string) (string, bool) {
+	return publicsuffix.PublicSuffix(domain)
+}
+
+// suffixProviderValue wraps a SuffixProvider, so implementations of different types can be
+// stored in an atomic.Value.
+type suffixProviderValue struct {
+	SuffixProvider
+}
+
+var suffixProvider atomic.Value
+
+func init() {
+	suffixProvider.Store(suffixProviderValue{bundledSuffixes{}})
+}
+
+// SetSuffixProvider replaces the public suffix list used by the package, ie. by TLD,
+// ValidateIcanSuffix and FindWithIcannSuffix, with p. Nil restores the list bundled with
+// golang.org/x/net/publicsuffix. It is safe to call while other goroutines use the list, so long
+// running services can refresh it periodically with a list of ParseSuffixList or FetchSuffixList.
+func SetSuffixProvider(p SuffixProvider) {
+	if p == nil {
+		p = bundledSuffixes{}
+	}
+	suffixProvider.Store(suffixProviderValue{p})
+}
+
+// publicSuffix returns the public suffix of domain according to the current SuffixProvider.
+func publicSuffix(domain string) (suffix string, icann bool) {
+	return suffixProvider.Load().(suffixProviderValue).PublicSuffix(domain)
+}
+
+// effectiveTLDPlusOne returns the public suffix of domain plus one label, like
+// publicsuffix.EffectiveTLDPlusOne, according to the current SuffixProvider.
+func effectiveTLDPlusOne(domain string) (string, error) {
+	if strings.HasPrefix(domain, ".") || strings.HasSuffix(domain, ".") || strings.Contains(domain, "..") {
+		return "", fmt.Errorf("empty label in domain %q", domain)
+	}
+	suffix, _ := publicSuffix(domain)
+	if len(domain) <= len(suffix) {
+		return "", fmt.Errorf("cannot derive eTLD+1 for domain %q", domain)
+	}
+	i := len(domain) - len(suffix) - 1
+	if domain[i] != '.' {
+		return "", fmt.Errorf("invalid public suffix %q for domain %q", suffix, domain)
+	}
+	return domain[1+strings.LastIndexByte(domain[:i], '.'):], nil
+}
+
+// suffixList is a SuffixProvider of rules in the format of the public suffix list. The maps hold
+// whether a rule is in the ICANN section.
+type suffixList struct {
+	rules      map[string]bool
+	wildcards  map[string]bool // rules of the form *.name, keyed by name
+	exceptions map[string]bool // rules of the form !name, keyed by name
+}
+
+// ParseSuffixList parses a list in the format of the upstream public_suffix_list.dat, with rules
+// for ICANN suffixes and, after the ===BEGIN PRIVATE DOMAINS=== marker, private ones.
+// Internationalized rules are converted to punycode, as are the domains of the bundled list. The
+// returned SuffixProvider follows the algorithm of https://publicsuffix.org/list/, domains that
+// match no rule have their last label as suffix, which is not managed by ICANN.
+func ParseSuffixList(r io.Reader) (SuffixProvider, error) {
+	l := &suffixList{
+		rules:      map[string]bool{},
+		wildcards:  map[string]bool{},
+		exceptions: map[string]bool{},
+	}
+	icann := true
+	s := bufio.NewScanner(r)
+	for n := 1; s.Scan(); n++ {
+		line := strings.TrimSpace(s.Text())
+		if strings.HasPrefix(line, "//") {
+			if strings.Contains(line, "===BEGIN PRIVATE DOMAINS===") {
+				icann = false
+			}
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) > 0 {
+			if err := l.add(fields[0], icann); err != nil {
+				return nil, fmt.Errorf("public suffix list line %d: %v", n, err)
+			}
+		}
+	}
+	if err := s.Err(); err != nil {
+		return nil, err
+	}
+	if len(l.rules)+len(l.wildcards) == 0 {
+		return nil, fmt.Errorf("public suffix list has no rules")
+	}
+	return l, nil
+}
+
+// LoadSuffixList reads and parses the public suffix list in the file at path. See
+// ParseSuffixList.
+func LoadSuffixList(path string) (SuffixProvider, error) {
+	f, err := os.Open(path) // #nosec
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	return ParseSuffixList(f)
+}
+
+func (l *suffixList) add(rule string, icann bool) error {
+	m := l.rules
+	switch {
+	case strings.HasPrefix(rule, "*."):
+		m, rule = l.wildcards, rule[2:]
+	case strings.HasPrefix(rule, "!"):
+		m, rule = l.exceptions, rule[1:]
+	}
+	name, err := idna.ToASCII(strings.ToLower(rule))
+	if err != nil || name == "" || strings.Contains(name, "*") ||
+		strings.HasPrefix(name, ".") || strings.HasSuffix(name, ".") || strings.Contains(name, "..") {
+		return fmt.Errorf("invalid rule %q", rule)
+	}
+	m[name] = icann
+	return nil
+}
+
+func (l *suffixList) PublicSuffix(domain string) (string, bool) {
+	domain = strings.ToLower(domain)
+	// An exception rule prevails, its suffix is the rule without its first label.
+	for name := domain; ; {
+		if icann, ok := l.exceptions[name]; ok {
+			if i := strings.IndexByte(name, '.'); i >= 0 {
+				return name[i+1:], icann
+			}
+		}
+		i := strings.IndexByte(name, '.')
+		if i < 0 {
+			break
+		}
+		name = name[i+1:]
+	}
+	// Otherwise the longest rule prevails, names are tried from the longest.
+	for name := domain; ; {
+		i := strings.IndexByte(name, '.')
+		if icann, ok := l.rules[name]; ok {
+			return name, icann
+		}
+		if i < 0 {
+			return name, false
+		}
+		if icann, ok := l.wildcards[name[i+1:]]; ok {
+			return name, icann
+		}
+		name = name[i+1:]
+	}
+}
@@ -0,0 +1,110 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testSuffixList = `// ===BEGIN ICANN DOMAINS===
+com
+uk
+co.uk
+*.ck
+!www.ck
+рф
+
+// ===END ICANN DOMAINS===
+// ===BEGIN PRIVATE DOMAINS===
+blogspot.com
+corp.internal // a comment
+// ===END PRIVATE DOMAINS===
+`
+
+func TestParseSuffixList(t *testing.T) {
+	p, err := ParseSuffixList(strings.NewReader(testSuffixList))
+	if err != nil {
+		t.Fatalf("ParseSuffixList() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		domain string
+		suffix string
+		icann  bool
+	}{
+		{"1", "foo.com", "com", true},
+		{"2", "foo.co.uk", "co.uk", true},
+		{"3", "foo.bar.uk", "uk", true},
+		{"4", "foo.bar.ck", "bar.ck", true},
+		{"5", "www.ck", "ck", true},
+		{"6", "foo.www.ck", "ck", true},
+		{"7", "foo.blogspot.com", "blogspot.com", false},
+		{"8", "x.corp.internal", "corp.internal", false},
+		{"9", "foo.fakesuffix", "fakesuffix", false},
+		{"10", "foo.xn--p1ai", "xn--p1ai", true},
+		{"11", "Foo.COM", "com", true},
+		{"12", "com", "com", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			suffix, icann := p.PublicSuffix(tt.domain)
+			if suffix != tt.suffix || icann != tt.icann {
+				t.Errorf("PublicSuffix() = %v, %v, want %v, %v", suffix, icann, tt.suffix, tt.icann)
+			}
+		})
+	}
+}
+
+func TestParseSuffixList_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		list string
+	}{
+		{"1", ""},
+		{"2", "// only comments\n"},
+		{"3", "com\n*\n"},
+		{"4", "com\nfoo..bar\n"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := ParseSuffixList(strings.NewReader(tt.list)); err == nil {
+				t.Error("ParseSuffixList() error = nil, want an error")
+			}
+		})
+	}
+}
+
+func TestSetSuffixProvider(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "public_suffix_list.dat")
+	if err := os.WriteFile(path, []byte(testSuffixList), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	p, err := LoadSuffixList(path)
+	if err != nil {
+		t.Fatalf("LoadSuffixList() error = %v", err)
+	}
+	SetSuffixProvider(p)
+	defer SetSuffixProvider(nil)
+
+	e := EmailAddress{"user", "mail.x.corp.internal"}
+	if got, want := e.RegistrableDomain(), "x.corp.internal"; got != want {
+		t.Errorf("EmailAddress.RegistrableDomain() = %v, want %v", got, want)
+	}
+	if err := e.ValidateIcanSuffix(); err == nil {
+		t.Error("EmailAddress.ValidateIcanSuffix() error = nil, want an error")
+	}
+	if got, want := (EmailAddress{"user", "foo.github.io"}).TLD(), "io"; got != want {
+		t.Errorf("EmailAddress.TLD() = %v, want %v", got, want)
+	}
+
+	SetSuffixProvider(nil)
+	if got, want := (EmailAddress{"user", "foo.github.io"}).TLD(), "github.io"; got != want {
+		t.Errorf("EmailAddress.TLD() with the bundled list = %v, want %v", got, want)
+	}
+}
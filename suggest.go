@@ -0,0 +1,199 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"strings"
+	"sync/atomic"
+)
+
+// maxCompletions is the maximum number of domains returned by SuggestCompletions.
+const maxCompletions = 5
+
+// popularDomains are the domains of widely used mail providers, most popular first.
+var popularDomains = []string{
+	"gmail.com", "yahoo.com", "hotmail.com", "outlook.com", "aol.com", "icloud.com",
+	"hotmail.co.uk", "yahoo.co.uk", "live.com", "msn.com", "gmx.de", "web.de", "qq.com",
+	"163.com", "mail.ru", "yandex.ru", "comcast.net", "yahoo.fr", "hotmail.fr", "orange.fr",
+	"free.fr", "gmx.net", "gmx.com", "me.com", "googlemail.com", "protonmail.com", "proton.me",
+	"t-online.de", "libero.it", "ymail.com", "live.co.uk", "btinternet.com", "sbcglobal.net",
+	"verizon.net", "att.net", "bellsouth.net", "rediffmail.com", "zoho.com", "mac.com",
+	"wanadoo.fr", "laposte.net", "uol.com.br", "bol.com.br", "hotmail.it", "yahoo.es",
+	"hotmail.es", "yahoo.in", "outlook.fr", "live.nl", "ziggo.nl", "telenet.be", "shaw.ca",
+	"rogers.com", "bigpond.com", "optusnet.com.au", "naver.com", "daum.net", "126.com",
+	"sina.com",
+}
+
+// commonTLDs are the most used top level domains, most used first. They break ties between
+// corrections of a misspelled top level domain.
+var commonTLDs = []string{
+	"com", "net", "org", "de", "uk", "fr", "ru", "it", "nl", "br", "au", "ca", "es", "io", "co",
+	"info", "us", "in", "jp", "cn", "eu", "ch", "be", "pl", "se",
+}
+
+// suggestDomains holds the domains returned by PopularDomains.
+var suggestDomains atomic.Value
+
+func init() {
+	suggestDomains.Store(popularDomains)
+}
+
+// PopularDomains returns the domains of widely used mail providers, most popular first. They are
+// the candidates of SuggestCompletions and SuggestDomain and can be used with NearestDomain to
+// correct typos.
+func PopularDomains() []string {
+	return append([]string(nil), currentPopularDomains()...)
+}
+
+// SetPopularDomains replaces the domains returned by PopularDomains, most popular first, ie. with
+// the domains most used by your own users. It is safe to call while other goroutines suggest
+// domains.
+func SetPopularDomains(domains []string) {
+	list := make([]string, len(domains))
+	for i, d := range domains {
+		list[i] = strings.TrimSuffix(strings.ToLower(d), ".")
+	}
+	suggestDomains.Store(list)
+}
+
+func currentPopularDomains() []string {
+	return suggestDomains.Load().([]string)
+}
+
+// SuggestDomain returns a likely correction of a misspelled domain, such as gmail.com for
+// gmail.con or example.com for example.cmo, or an empty string if the domain looks right. The
+// domain is compared with PopularDomains by edit distance, where typing a neighbouring key counts
+// as a likely typo. Otherwise a top level domain that is not on the public suffix list is
+// corrected to the nearest one that is.
+func (e EmailAddress) SuggestDomain() string {
+	domain := strings.TrimSuffix(strings.ToLower(e.Domain), ".")
+	if domain == "" || strings.HasPrefix(domain, "[") {
+		return ""
+	}
+	popular := currentPopularDomains()
+	for _, d := range popular {
+		if d == domain {
+			return ""
+		}
+	}
+	if d, _ := NearestDomain(domain, popular); d != "" && editDistance(domain, d) <= maxLookalikeDistance(d) &&
+		!countryVariant(domain, d) {
+		return d
+	}
+
+	i := strings.LastIndexByte(domain, '.')
+	if i < 0 {
+		return ""
+	}
+	if tld := suggestTLD(domain[i+1:]); tld != "" {
+		return domain[:i+1] + tld
+	}
+	return ""
+}
+
+// Suggest returns the email address with a likely correction of its domain, see SuggestDomain, or
+// an empty string if there is none. The address is not validated.
+func Suggest(email string) string {
+	i := strings.LastIndexByte(email, '@')
+	if i < 0 {
+		return ""
+	}
+	e := EmailAddress{LocalPart: email[:i], Domain: email[i+1:]}
+	if d := e.SuggestDomain(); d != "" {
+		return e.LocalPart + "@" + d
+	}
+	return ""
+}
+
+// countryVariant reports whether domain is the popular domain under another valid public suffix
+// that is not a single typo away, such as live.ca for live.com.
+func countryVariant(domain, popular string) bool {
+	suffix, icann := publicSuffix(domain)
+	popularSuffix, _ := publicSuffix(popular)
+	return icann && strings.TrimSuffix(domain, suffix) == strings.TrimSuffix(popular, popularSuffix) &&
+		editDistance(suffix, popularSuffix) > 1
+}
+
+// suggestTLD returns the top level domain of the public suffix list that is one edit away from
+// tld and the most likely typo, or an empty string if tld is on the list or no such domain exists.
+func suggestTLD(tld string) string {
+	if isICANNSuffix(tld) {
+		return ""
+	}
+	best, bestCost, bestRank := "", 0.0, 0
+	for _, c := range tldEdits(tld) {
+		if !isICANNSuffix(c) {
+			continue
+		}
+		cost, rank := weightedDistance(tld, c, keyboardSubstitution), tldRank(c)
+		if best == "" || cost < bestCost || cost == bestCost && rank < bestRank {
+			best, bestCost, bestRank = c, cost, rank
+		}
+	}
+	return best
+}
+
+// tldEdits returns the strings one deletion, transposition, substitution or insertion of a letter
+// away from tld.
+func tldEdits(tld string) []string {
+	const letters = "abcdefghijklmnopqrstuvwxyz"
+	var edits []string
+	for i := 0; i <= len(tld); i++ {
+		if i < len(tld) {
+			edits = append(edits, tld[:i]+tld[i+1:])
+		}
+		if i+1 < len(tld) {
+			edits = append(edits, tld[:i]+tld[i+1:i+2]+tld[i:i+1]+tld[i+2:])
+		}
+		for _, c := range letters {
+			if i < len(tld) {
+				edits = append(edits, tld[:i]+string(c)+tld[i+1:])
+			}
+			edits = append(edits, tld[:i]+string(c)+tld[i:])
+		}
+	}
+	return edits
+}
+
+func isICANNSuffix(tld string) bool {
+	if tld == "" {
+		return false
+	}
+	suffix, icann := publicSuffix(tld)
+	return icann && suffix == tld
+}
+
+// tldRank returns the position of tld in commonTLDs, or len(commonTLDs) if it's not in the list.
+func tldRank(tld string) int {
+	for i, t := range commonTLDs {
+		if t == tld {
+			return i
+		}
+	}
+	return len(commonTLDs)
+}
+
+// SuggestCompletions returns up to 5 popular domains that complete the domain of a partially
+// typed email address, most popular first. For example jane@gm returns gmail.com, gmx.de,
+// gmx.net and gmx.com. Without an @ the whole input is taken as the start of the domain. Nothing
+// is returned for an empty domain, the comparison is case-insensitive.
+func SuggestCompletions(partial string) []string {
+	prefix := partial[strings.LastIndexByte(partial, '@')+1:]
+	prefix = strings.ToLower(strings.TrimSpace(prefix))
+	if prefix == "" {
+		return nil
+	}
+	var completions []string
+	for _, d := range currentPopularDomains() {
+		if strings.HasPrefix(d, prefix) {
+			completions = append(completions, d)
+			if len(completions) == maxCompletions {
+				break
+			}
+		}
+	}
+	return completions
+}
@@ -0,0 +1,108 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSuggestCompletions(t *testing.T) {
+	tests := []struct {
+		name    string
+		partial string
+		want    []string
+	}{
+		{"1", "jane@gm", []string{"gmail.com", "gmx.de", "gmx.net", "gmx.com"}},
+		{"2", "jane@GMA", []string{"gmail.com"}},
+		{"3", "hot", []string{"hotmail.com", "hotmail.co.uk", "hotmail.fr", "hotmail.it", "hotmail.es"}},
+		{"4", "jane@gmail.com", []string{"gmail.com"}},
+		{"5", "jane@", nil},
+		{"6", "jane@example", nil},
+		{"7", "", nil},
+		{"8", "a@b@yahoo.c", []string{"yahoo.com", "yahoo.co.uk"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := SuggestCompletions(tt.partial); !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("SuggestCompletions() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPopularDomains(t *testing.T) {
+	got := PopularDomains()
+	got[0] = "changed"
+	if popularDomains[0] != "gmail.com" {
+		t.Errorf("PopularDomains() shares its backing array")
+	}
+	if d, s := NearestDomain("gmial.com", PopularDomains()); d != "gmail.com" || s < 0.8 {
+		t.Errorf("NearestDomain(gmial.com, PopularDomains()) = %v, %v, want gmail.com", d, s)
+	}
+}
+
+func TestEmailAddress_SuggestDomain(t *testing.T) {
+	tests := []struct {
+		name string
+		e    EmailAddress
+		want string
+	}{
+		{"1", EmailAddress{"jane", "gmail.con"}, "gmail.com"},
+		{"2", EmailAddress{"jane", "gmial.com"}, "gmail.com"},
+		{"3", EmailAddress{"jane", "Hotmial.com"}, "hotmail.com"},
+		{"4", EmailAddress{"jane", "yahoo.co.uk"}, ""},
+		{"5", EmailAddress{"jane", "gmail.com."}, ""},
+		{"6", EmailAddress{"jane", "example.cmo"}, "example.com"},
+		{"7", EmailAddress{"jane", "example.con"}, "example.com"},
+		{"8", EmailAddress{"jane", "example.co.ukk"}, "example.co.uk"},
+		{"9", EmailAddress{"jane", "example.org"}, ""},
+		{"10", EmailAddress{"jane", "example.cm"}, ""},
+		{"11", EmailAddress{"jane", "example.xyzzy"}, ""},
+		{"12", EmailAddress{"jane", "[192.0.2.1]"}, ""},
+		{"13", EmailAddress{"jane", "localhost"}, ""},
+		{"14", EmailAddress{"jane", "live.ca"}, ""},
+		{"15", EmailAddress{"jane", "gmail.co"}, "gmail.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.e.SuggestDomain(); got != tt.want {
+				t.Errorf("EmailAddress.SuggestDomain() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSuggest(t *testing.T) {
+	tests := []struct {
+		name  string
+		email string
+		want  string
+	}{
+		{"1", "jane@gmail.con", "jane@gmail.com"},
+		{"2", "jane@gmail.com", ""},
+		{"3", "jane", ""},
+		{"4", `"a@b"@yaho.com`, `"a@b"@yahoo.com`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Suggest(tt.email); got != tt.want {
+				t.Errorf("Suggest() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestSetPopularDomains(t *testing.T) {
+	defer SetPopularDomains(PopularDomains())
+	SetPopularDomains([]string{"Corp.example."})
+	if got := (EmailAddress{"jane", "crop.example"}).SuggestDomain(); got != "corp.example" {
+		t.Errorf("EmailAddress.SuggestDomain() = %v, want corp.example", got)
+	}
+	if got := SuggestCompletions("jane@co"); !reflect.DeepEqual(got, []string{"corp.example"}) {
+		t.Errorf("SuggestCompletions() = %v, want [corp.example]", got)
+	}
+}
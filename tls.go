@@ -0,0 +1,191 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/smtp"
+	"net/textproto"
+	"strings"
+)
+
+// TLSState describes the TLS connection negotiated with a mail host after STARTTLS.
+type TLSState struct {
+	// Version and CipherSuite are the negotiated TLS version and cipher suite, ie.
+	// tls.VersionTLS13 and tls.TLS_AES_128_GCM_SHA256.
+	Version     uint16
+	CipherSuite uint16
+
+	// ServerName is the name the certificate of the host was verified against.
+	ServerName string
+
+	// CertificateValid reports whether the certificate chain of the host is trusted and valid
+	// for ServerName. Mail hosts often present self-signed or expired certificates, the
+	// connection is still encrypted unless WithRequireTLS is used.
+	CertificateValid bool
+
+	// CertificateError is the reason the certificate is not valid, nil if it is.
+	CertificateError error
+}
+
+// VersionName returns the name of the TLS version, ie. TLS 1.3.
+func (s *TLSState) VersionName() string {
+	switch s.Version {
+	case tls.VersionTLS10:
+		return "TLS 1.0"
+	case tls.VersionTLS11:
+		return "TLS 1.1"
+	case tls.VersionTLS12:
+		return "TLS 1.2"
+	case tls.VersionTLS13:
+		return "TLS 1.3"
+	default:
+		return fmt.Sprintf("0x%04X", s.Version)
+	}
+}
+
+// CipherSuiteName returns the name of the cipher suite, ie. TLS_AES_128_GCM_SHA256.
+func (s *TLSState) CipherSuiteName() string {
+	return tls.CipherSuiteName(s.CipherSuite)
+}
+
+// WithTLSConfig sets the configuration of the TLS connections made with STARTTLS, ie. to use
+// other root certificates or a minimum version. The certificate of the host is always verified
+// after the handshake and reported in TLSState rather than failing the handshake, so
+// InsecureSkipVerify makes no difference. ServerName defaults to the mail host.
+func WithTLSConfig(c *tls.Config) Option {
+	return func(v *Verifier) {
+		v.tlsConfig = c
+	}
+}
+
+// WithRequireTLS makes a probe fail with CodeTLSRequired when the mail host does not offer
+// STARTTLS or presents a certificate that is not valid. By default a host is probed over
+// plaintext when it lacks STARTTLS or rejects the command.
+func WithRequireTLS() Option {
+	return func(v *Verifier) {
+		v.requireTLS = true
+	}
+}
+
+// startTLS upgrades conn, the connection of client, to TLS when host offered STARTTLS and greets
+// the host again with helo, replacing the extensions of caps. It returns nil if the connection
+// was not upgraded. A handshake failure leaves the connection unusable and is returned as is.
+//
+// STARTTLS of net/smtp would put TLS on top of conn, the upgrade is made below it so the layers
+// in between, such as the transcript, see the plaintext conversation.
+func (v *Verifier) startTLS(client *smtp.Client, conn *upgradableConn, host, helo string, caps *Capabilities) (*TLSState, error) {
+	if !caps.StartTLS() {
+		if v.requireTLS {
+			return nil, newError(CodeTLSRequired, fmt.Errorf("%s does not offer STARTTLS", host))
+		}
+		return nil, nil
+	}
+
+	if err := command(client.Text, 220, "STARTTLS"); err != nil {
+		var tpErr *textproto.Error
+		if errors.As(err, &tpErr) && !v.requireTLS {
+			return nil, nil // the host refused, the connection is still usable in plaintext
+		}
+		return nil, err
+	}
+	s, err := v.handshake(conn, host)
+	if err != nil {
+		return s, err
+	}
+	// The host forgets what it knew about the client (RFC 3207 section 4.2) and may announce
+	// other extensions, such as AUTH.
+	_, msg, err := exchange(client.Text, 250, "EHLO %s", helo)
+	if err != nil {
+		return nil, err
+	}
+	caps.Extensions = parseExtensions(msg)
+	return s, nil
+}
+
+// handshake upgrades conn to TLS and verifies the certificate of host. An invalid certificate
+// is only an error with WithRequireTLS, the TLSState is returned with it.
+func (v *Verifier) handshake(conn *upgradableConn, host string) (*TLSState, error) {
+	var config *tls.Config
+	if v.tlsConfig != nil {
+		config = v.tlsConfig.Clone()
+	} else {
+		config = &tls.Config{}
+	}
+	if config.ServerName == "" {
+		config.ServerName = strings.TrimSuffix(host, ".")
+	}
+	roots := config.RootCAs
+	config.InsecureSkipVerify = true // #nosec verified below, so invalid certificates can be reported
+
+	cs, err := conn.upgrade(config)
+	if err != nil {
+		return nil, err
+	}
+	s := &TLSState{
+		Version:          cs.Version,
+		CipherSuite:      cs.CipherSuite,
+		ServerName:       config.ServerName,
+		CertificateError: verifyCertificate(cs, roots, config.ServerName),
+	}
+	s.CertificateValid = s.CertificateError == nil
+	if !s.CertificateValid && v.requireTLS {
+		return s, newError(CodeTLSRequired, fmt.Errorf("certificate of %s is not valid: %w", host, s.CertificateError))
+	}
+	return s, nil
+}
+
+// verifyCertificate verifies the certificate chain of cs against roots, the system roots if nil,
+// for name.
+func verifyCertificate(cs tls.ConnectionState, roots *x509.CertPool, name string) error {
+	if len(cs.PeerCertificates) == 0 {
+		return errors.New("no certificate presented")
+	}
+	opts := x509.VerifyOptions{DNSName: name, Roots: roots, Intermediates: x509.NewCertPool()}
+	for _, c := range cs.PeerCertificates[1:] {
+		opts.Intermediates.AddCert(c)
+	}
+	_, err := cs.PeerCertificates[0].Verify(opts)
+	return err
+}
+
+// upgradableConn is the connection of an SMTP session, which can be switched to TLS in place.
+type upgradableConn struct {
+	net.Conn
+}
+
+// upgrade performs a TLS handshake over the connection, which is then used for reads and writes.
+func (c *upgradableConn) upgrade(config *tls.Config) (tls.ConnectionState, error) {
+	tc := tls.Client(c.Conn, config)
+	if err := tc.Handshake(); err != nil {
+		return tls.ConnectionState{}, err
+	}
+	c.Conn = tc
+	return tc.ConnectionState(), nil
+}
+
+// command sends a command net/smtp has no method for and reads its reply, which must have code.
+func command(text *textproto.Conn, code int, format string, args ...interface{}) error {
+	_, _, err := exchange(text, code, format, args...)
+	return err
+}
+
+// exchange is like command, but returns the reply. A code of 0 accepts any reply.
+func exchange(text *textproto.Conn, code int, format string, args ...interface{}) (int, string, error) {
+	id, err := text.Cmd(format, args...)
+	if err != nil {
+		return 0, "", err
+	}
+	text.StartResponse(id)
+	defer text.EndResponse(id)
+	return text.ReadResponse(code)
+}
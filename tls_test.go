@@ -0,0 +1,98 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestVerifier_Verify_StartTLS(t *testing.T) {
+	// The certificate of httptest is valid for 127.0.0.1.
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+	roots := x509.NewCertPool()
+	roots.AddCert(srv.Certificate())
+
+	plain := newTestServer(t, "127.0.0.1:0")
+	refused := newTestServer(t, "127.0.0.1:0")
+	refused.extensions = []string{"STARTTLS"}
+	secure := newTestServer(t, "127.0.0.1:0", withTLS(&tls.Config{Certificates: srv.TLS.Certificates, MinVersion: tls.VersionTLS12}, false))
+	for _, s := range []*testServer{plain, refused, secure} {
+		s.rcpt = func(addr string) string {
+			if addr == "info@example.com" {
+				return "250 OK"
+			}
+			return "550 no such user"
+		}
+	}
+
+	tests := []struct {
+		name     string
+		server   *testServer
+		opts     []Option
+		verdict  Verdict
+		tls      bool
+		valid    bool
+		wantCode Code
+	}{
+		{"1", secure, []Option{WithTLSConfig(&tls.Config{RootCAs: roots})}, VerdictDeliverable, true, true, ""},
+		{"2", secure, nil, VerdictDeliverable, true, false, ""},
+		{"3", secure, []Option{WithRequireTLS()}, VerdictUnknown, true, false, CodeTLSRequired},
+		{"4", secure, []Option{WithRequireTLS(), WithTLSConfig(&tls.Config{RootCAs: roots})}, VerdictDeliverable, true, true, ""},
+		{"5", plain, nil, VerdictDeliverable, false, false, ""},
+		{"6", plain, []Option{WithRequireTLS()}, VerdictUnknown, false, false, CodeTLSRequired},
+		{"7", refused, nil, VerdictDeliverable, false, false, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append([]Option{WithPort(tt.server.port())}, tt.opts...)...)
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: tt.server.host(), Pref: 10}}}}
+			got := v.Verify(context.Background(), "info@example.com")
+			if got.Verdict != tt.verdict || ErrorCode(got.Err) != tt.wantCode {
+				t.Errorf("Verifier.Verify() = %v, %v, want %v, %v", got.Verdict, got.Err, tt.verdict, tt.wantCode)
+			}
+			if (got.TLS != nil) != tt.tls {
+				t.Fatalf("Verifier.Verify() TLS = %+v, want TLS %v", got.TLS, tt.tls)
+			}
+			if got.TLS == nil {
+				return
+			}
+			if got.TLS.CertificateValid != tt.valid || (got.TLS.CertificateError == nil) != tt.valid {
+				t.Errorf("Verifier.Verify() certificate valid = %v, %v, want %v", got.TLS.CertificateValid, got.TLS.CertificateError, tt.valid)
+			}
+			if got.TLS.Version < tls.VersionTLS12 || got.TLS.CipherSuiteName() == "" || got.TLS.ServerName != "127.0.0.1" {
+				t.Errorf("Verifier.Verify() TLS = %v %v %v", got.TLS.VersionName(), got.TLS.CipherSuiteName(), got.TLS.ServerName)
+			}
+		})
+	}
+}
+
+func TestTLSState_VersionName(t *testing.T) {
+	tests := []struct {
+		name    string
+		version uint16
+		want    string
+	}{
+		{"1", tls.VersionTLS12, "TLS 1.2"},
+		{"2", tls.VersionTLS13, "TLS 1.3"},
+		{"3", 0x0300, "0x0300"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := (&TLSState{Version: tt.version}).VersionName(); got != tt.want {
+				t.Errorf("TLSState.VersionName() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
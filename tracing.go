@@ -0,0 +1,178 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+)
+
+// Tracer starts the spans of the network steps of a Verifier, ie. by adapting a trace.Tracer of
+// OpenTelemetry, so a slow validation can be broken down in a request trace:
+//
+//	type otelTracer struct{ tracer trace.Tracer }
+//
+//	func (t otelTracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, emailaddress.Span) {
+//		kv := make([]attribute.KeyValue, 0, len(attrs))
+//		for k, v := range attrs {
+//			kv = append(kv, attribute.String(k, v))
+//		}
+//		ctx, span := t.tracer.Start(ctx, name, trace.WithAttributes(kv...))
+//		return ctx, otelSpan{span}
+//	}
+//
+// The spans are emailaddress.ValidateHost, emailaddress.CheckHost and emailaddress.Verify around
+// the methods of the same name, dns.MX, dns.IP, dns.TXT and dns.PTR around DNS queries,
+// smtp.probe around the conversation with a mail host, with the children smtp.connect,
+// smtp.greeting and smtp. followed by the verb of every command, ie. smtp.RCPT. The attributes
+// never hold the validated address, only its domain.
+type Tracer interface {
+	Start(ctx context.Context, name string, attrs map[string]string) (context.Context, Span)
+}
+
+// Span is a span started by a Tracer. End is called once, with the error of the step or nil.
+type Span interface {
+	End(err error)
+}
+
+// WithTracer traces the validations of the Verifier with t.
+func WithTracer(t Tracer) Option {
+	return func(v *Verifier) {
+		v.tracer = t
+	}
+}
+
+// nopSpan is the Span of a Verifier without Tracer.
+type nopSpan struct{}
+
+func (nopSpan) End(error) {}
+
+// startSpan starts a span with the tracer of v, if it has one.
+func (v *Verifier) startSpan(ctx context.Context, name string, attrs map[string]string) (context.Context, Span) {
+	if v.tracer == nil {
+		return ctx, nopSpan{}
+	}
+	return v.tracer.Start(ctx, name, attrs)
+}
+
+// tracingResolver starts a span for every query of resolver.
+type tracingResolver struct {
+	resolver resolver
+	tracer   Tracer
+}
+
+func (r *tracingResolver) LookupMX(ctx context.Context, name string) ([]*net.MX, error) {
+	ctx, span := r.tracer.Start(ctx, "dns.MX", map[string]string{"dns.name": name})
+	mx, err := r.resolver.LookupMX(ctx, name)
+	span.End(err)
+	return mx, err
+}
+
+func (r *tracingResolver) LookupIP(ctx context.Context, network, host string) ([]net.IP, error) {
+	ctx, span := r.tracer.Start(ctx, "dns.IP", map[string]string{"dns.name": host, "dns.network": network})
+	ips, err := r.resolver.LookupIP(ctx, network, host)
+	span.End(err)
+	return ips, err
+}
+
+func (r *tracingResolver) LookupTXT(ctx context.Context, name string) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "dns.TXT", map[string]string{"dns.name": name})
+	txt, err := r.resolver.LookupTXT(ctx, name)
+	span.End(err)
+	return txt, err
+}
+
+func (r *tracingResolver) LookupAddr(ctx context.Context, addr string) ([]string, error) {
+	ctx, span := r.tracer.Start(ctx, "dns.PTR", map[string]string{"dns.name": addr})
+	names, err := r.resolver.LookupAddr(ctx, addr)
+	span.End(err)
+	return names, err
+}
+
+// commandSpans starts a span for every command of an SMTP conversation, and one for the
+// greeting, which ends with the last line of its reply. It is a line observer of lineConn.
+type commandSpans struct {
+	ctx    context.Context
+	tracer Tracer
+	host   string
+	span   Span
+}
+
+// newCommandSpans returns the spans of the conversation with host, or nil without tracer. The
+// span of the greeting starts right away.
+func (v *Verifier) newCommandSpans(ctx context.Context, host string) *commandSpans {
+	if v.tracer == nil {
+		return nil
+	}
+	s := &commandSpans{ctx: ctx, tracer: v.tracer, host: host}
+	s.start("smtp.greeting", "")
+	return s
+}
+
+func (s *commandSpans) start(name, verb string) {
+	attrs := map[string]string{"smtp.host": s.host}
+	if verb != "" {
+		attrs["smtp.command"] = verb
+	}
+	_, s.span = s.tracer.Start(s.ctx, name, attrs)
+}
+
+// observe ends the span of the current command with the last line of its reply and starts the
+// span of the next command. Only the verb of a command is used, not its argument.
+func (s *commandSpans) observe(dir string, line []byte) {
+	if dir == "C" {
+		s.end(nil)
+		verb := string(line)
+		if i := bytes.IndexAny(line, " :"); i >= 0 {
+			verb = string(line[:i])
+		}
+		verb = strings.ToUpper(verb)
+		s.start("smtp."+verb, verb)
+		return
+	}
+	// The last line of a reply has a space after the code, the others a hyphen.
+	if len(line) < 4 || line[3] == '-' {
+		return
+	}
+	var err error
+	if line[0] >= '4' {
+		err = fmt.Errorf("%s", line)
+	}
+	s.end(err)
+}
+
+func (s *commandSpans) end(err error) {
+	if s.span != nil {
+		s.span.End(err)
+		s.span = nil
+	}
+}
+
+// observer returns the line observer of s, or nil if s is nil.
+func (s *commandSpans) observer() func(dir string, line []byte) {
+	if s == nil {
+		return nil
+	}
+	return s.observe
+}
+
+// close ends the span of a command that got no reply.
+func (s *commandSpans) close() {
+	if s != nil {
+		s.end(errors.New("connection closed without reply"))
+	}
+}
+
+// domainOf returns the part of address after the last @, the domain attribute of a span.
+func domainOf(address string) string {
+	return address[strings.LastIndexByte(address, '@')+1:]
+}
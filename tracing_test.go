@@ -0,0 +1,96 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// recordingTracer is a Tracer recording the spans that ended, with their error.
+type recordingTracer struct {
+	mu    sync.Mutex
+	spans []string
+}
+
+type recordingSpan struct {
+	tracer *recordingTracer
+	name   string
+}
+
+func (t *recordingTracer) Start(ctx context.Context, name string, attrs map[string]string) (context.Context, Span) {
+	return ctx, &recordingSpan{tracer: t, name: name}
+}
+
+func (s *recordingSpan) End(err error) {
+	s.tracer.mu.Lock()
+	defer s.tracer.mu.Unlock()
+	s.tracer.spans = append(s.tracer.spans, fmt.Sprintf("%s %v", s.name, err != nil))
+}
+
+func TestWithTracer(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		if addr == "info@example.com" {
+			return "250 OK"
+		}
+		return "550 5.1.1 no such user"
+	}
+	r := &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+
+	tests := []struct {
+		name  string
+		local string
+		want  []string
+	}{
+		{
+			name:  "1",
+			local: "info",
+			want: []string{
+				"dns.MX false",
+				"smtp.connect false",
+				"smtp.greeting false",
+				"smtp.EHLO false",
+				"smtp.MAIL false",
+				"smtp.RCPT false",
+				"smtp.RSET false",
+				"smtp.QUIT false",
+				"smtp.probe false",
+				"emailaddress.ValidateHost false",
+			},
+		},
+		{
+			name:  "2",
+			local: "nobody",
+			want: []string{
+				"dns.MX false",
+				"smtp.connect false",
+				"smtp.greeting false",
+				"smtp.EHLO false",
+				"smtp.MAIL false",
+				"smtp.RCPT true",
+				"smtp.probe true",
+				"emailaddress.ValidateHost true",
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tr := &recordingTracer{}
+			v := NewVerifier(WithPort(s.port()), WithTracer(tr), WithResolver(r))
+			v.ValidateHost(context.Background(), EmailAddress{tt.local, "example.com"}) // #nosec
+			if !reflect.DeepEqual(tr.spans, tt.want) {
+				t.Errorf("spans = %v, want %v", tr.spans, tt.want)
+			}
+		})
+	}
+}
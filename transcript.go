@@ -0,0 +1,120 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net"
+	"sync"
+	"time"
+)
+
+// WithTranscript writes the SMTP conversations of the Verifier to w, one line per command sent
+// (C:) and reply line received (S:), prefixed with the mail host and the time since the
+// connection was made:
+//
+//	mx.example.com +1.204ms S: 220 mx.example.com ESMTP
+//	mx.example.com +1.311ms C: EHLO example.com
+//
+// After STARTTLS the conversation is still written in plaintext. The lines of concurrent
+// conversations are interleaved, but every line is written with a single call to w. The
+// transcript contains the addresses that are validated, but not the credentials of WithAuth: the
+// client lines of the AUTH exchange are written as "AUTH PLAIN ***" and "***".
+func WithTranscript(w io.Writer) Option {
+	return func(v *Verifier) {
+		v.transcript = &transcript{w: w}
+	}
+}
+
+// transcript writes the lines of SMTP conversations to w.
+type transcript struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// observer returns a line observer writing the conversation with host to t, or nil if t is nil.
+func (t *transcript) observer(host string) func(dir string, line []byte) {
+	if t == nil {
+		return nil
+	}
+	start := time.Now()
+	return func(dir string, line []byte) {
+		t.mu.Lock()
+		defer t.mu.Unlock()
+		fmt.Fprintf(t.w, "%s +%v %s: %s\n", host, time.Since(start).Round(time.Microsecond), dir, line) // #nosec
+	}
+}
+
+// lineConn passes every complete line read from the connection (S) and written to it (C),
+// without line ending, to its observers.
+type lineConn struct {
+	net.Conn
+	observers []func(dir string, line []byte)
+
+	// read and written hold the incomplete last line of either direction.
+	read, written []byte
+
+	// redact hides the arguments of the client lines from the observers, while the
+	// credentials of an AUTH exchange are sent.
+	redact bool
+}
+
+// observe adds fn to the observers of c, unless it is nil.
+func (c *lineConn) observe(fn func(dir string, line []byte)) {
+	if fn != nil {
+		c.observers = append(c.observers, fn)
+	}
+}
+
+func (c *lineConn) Read(b []byte) (int, error) {
+	n, err := c.Conn.Read(b)
+	if len(c.observers) > 0 {
+		c.read = c.lines(c.read, b[:n], "S")
+	}
+	return n, err
+}
+
+func (c *lineConn) Write(b []byte) (int, error) {
+	n, err := c.Conn.Write(b)
+	if len(c.observers) > 0 {
+		c.written = c.lines(c.written, b[:n], "C")
+	}
+	return n, err
+}
+
+// lines appends b to the incomplete line buf, passes the complete lines to the observers and
+// returns the remainder.
+func (c *lineConn) lines(buf, b []byte, dir string) []byte {
+	buf = append(buf, b...)
+	for {
+		i := bytes.IndexByte(buf, '\n')
+		if i < 0 {
+			return buf
+		}
+		line := bytes.TrimRight(buf[:i], "\r")
+		if c.redact && dir == "C" {
+			line = redactLine(line)
+		}
+		for _, fn := range c.observers {
+			fn(dir, line)
+		}
+		buf = buf[i+1:]
+	}
+}
+
+// redactLine returns line of an AUTH exchange without its credentials, keeping the mechanism of
+// the AUTH command.
+func redactLine(line []byte) []byte {
+	fields := bytes.Fields(line)
+	if len(fields) >= 2 && bytes.EqualFold(fields[0], []byte("AUTH")) {
+		return []byte(string(fields[0]) + " " + string(fields[1]) + " ***")
+	}
+	return []byte("***")
+}
@@ -0,0 +1,85 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestWithTranscript(t *testing.T) {
+	srv := httptest.NewTLSServer(http.NotFoundHandler())
+	defer srv.Close()
+
+	tests := []struct {
+		name      string
+		tlsConfig *tls.Config
+		want      []string
+	}{
+		{"1", nil, []string{
+			"S: 220 test ESMTP",
+			"C: EHLO example.com",
+			"S: 250-test greets you",
+			"S: 250-PIPELINING",
+			"S: 250 8BITMIME",
+			"C: MAIL FROM:<hello@example.com> BODY=8BITMIME",
+			"S: 250 OK",
+			"C: RCPT TO:<info@example.com>",
+			"S: 550 no such user",
+		}},
+		{"2", &tls.Config{Certificates: srv.TLS.Certificates}, []string{
+			"S: 220 test ESMTP",
+			"C: EHLO example.com",
+			"S: 250-test greets you",
+			"S: 250-PIPELINING",
+			"S: 250-8BITMIME",
+			"S: 250 STARTTLS",
+			"C: STARTTLS",
+			"S: 220 ready to start TLS",
+			"C: EHLO example.com",
+			"S: 250-test greets you",
+			"S: 250-PIPELINING",
+			"S: 250-8BITMIME",
+			"S: 250 STARTTLS",
+			"C: MAIL FROM:<hello@example.com> BODY=8BITMIME",
+			"S: 250 OK",
+			"C: RCPT TO:<info@example.com>",
+			"S: 550 no such user",
+		}},
+	}
+	prefix := regexp.MustCompile(`^127\.0\.0\.1 \+[0-9.µmns]+ `)
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0", withTLS(tt.tlsConfig, false))
+			s.rcpt = func(string) string { return "550 no such user" }
+			var buf bytes.Buffer
+			v := NewVerifier(WithPort(s.port()), WithTranscript(&buf))
+			if err := v.TryHost(context.Background(), s.host(), EmailAddress{"info", "example.com"}); ErrorCode(err) != CodeMailboxRejected {
+				t.Fatalf("Verifier.TryHost() error = %v, want %v", err, CodeMailboxRejected)
+			}
+
+			var got []string
+			for _, line := range strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n") {
+				if !prefix.MatchString(line) {
+					t.Errorf("transcript line %q has no host and time", line)
+				}
+				got = append(got, prefix.ReplaceAllString(line, ""))
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("transcript = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,132 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// DomainMatcher is implemented by a DomainList that matches subdomains itself. The Verifier
+// calls Match with the domain of an email address instead of calling Contains with the domain and
+// each of its parent domains.
+type DomainMatcher interface {
+	Match(domain string) bool
+}
+
+// DomainTrie matches domains against a large set of rules in time proportional to the number of
+// labels of the domain, independent of the number of rules. The rules are stored in a trie of
+// reversed labels and support three forms:
+//
+//	example.com     matches example.com only
+//	*.example.com   matches the subdomains of example.com, but not example.com itself
+//	.example.com    matches example.com and its subdomains
+//
+// A DomainTrie can't be changed after it is created and is safe for concurrent use.
+type DomainTrie struct {
+	root trieNode
+}
+
+type trieNode struct {
+	children map[string]*trieNode
+	exact    bool
+	wildcard bool
+}
+
+// NewDomainTrie returns a DomainTrie for the rules. It returns an error for a rule with a
+// wildcard anywhere but the first label or with empty labels.
+func NewDomainTrie(rules []string) (*DomainTrie, error) {
+	t := &DomainTrie{}
+	for _, r := range rules {
+		if err := t.add(r); err != nil {
+			return nil, err
+		}
+	}
+	return t, nil
+}
+
+// ReadDomainTrie reads a DomainTrie from r with one rule per line. Empty lines and comments
+// starting with # are skipped.
+func ReadDomainTrie(r io.Reader) (*DomainTrie, error) {
+	var rules []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := stripComment(scanner.Text()); line != "" {
+			rules = append(rules, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewDomainTrie(rules)
+}
+
+// WatchDomainTrie is like WatchDomainList, but reads the file with ReadDomainTrie.
+func WatchDomainTrie(path string, interval time.Duration, onError func(error)) (*FileDomainList, error) {
+	return watchFile(path, interval, onError, func(r io.Reader) (DomainList, error) {
+		return ReadDomainTrie(r)
+	})
+}
+
+func (t *DomainTrie) add(rule string) error {
+	r := strings.TrimSuffix(strings.ToLower(strings.TrimSpace(rule)), ".")
+	exact, wildcard := true, false
+	switch {
+	case strings.HasPrefix(r, "*."):
+		r, exact, wildcard = r[2:], false, true
+	case strings.HasPrefix(r, "."):
+		r, wildcard = r[1:], true
+	}
+	labels := strings.Split(r, ".")
+	n := &t.root
+	for i := len(labels) - 1; i >= 0; i-- {
+		l := labels[i]
+		if l == "" || strings.Contains(l, "*") {
+			return fmt.Errorf("invalid domain rule %q", rule)
+		}
+		child, ok := n.children[l]
+		if !ok {
+			if n.children == nil {
+				n.children = make(map[string]*trieNode)
+			}
+			child = &trieNode{}
+			n.children[l] = child
+		}
+		n = child
+	}
+	n.exact = n.exact || exact
+	n.wildcard = n.wildcard || wildcard
+	return nil
+}
+
+// Match reports whether any rule matches the domain.
+func (t *DomainTrie) Match(domain string) bool {
+	domain = strings.TrimSuffix(strings.ToLower(domain), ".")
+	n := &t.root
+	for domain != "" {
+		var label string
+		if i := strings.LastIndexByte(domain, '.'); i >= 0 {
+			label, domain = domain[i+1:], domain[:i]
+		} else {
+			label, domain = domain, ""
+		}
+		if n = n.children[label]; n == nil {
+			return false
+		}
+		if n.wildcard && domain != "" {
+			return true
+		}
+	}
+	return n != &t.root && n.exact
+}
+
+// Contains reports whether any rule matches the domain, it is the same as Match.
+func (t *DomainTrie) Contains(domain string) bool {
+	return t.Match(domain)
+}
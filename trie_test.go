@@ -0,0 +1,89 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"fmt"
+	"strings"
+	"testing"
+)
+
+func TestDomainTrie_Match(t *testing.T) {
+	trie, err := NewDomainTrie([]string{"example.com", "*.wild.com", ".suffix.com", "Upper.COM.", "com.au"})
+	if err != nil {
+		t.Fatalf("NewDomainTrie() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{"1", "example.com", true},
+		{"2", "mail.example.com", false},
+		{"3", "wild.com", false},
+		{"4", "mail.wild.com", true},
+		{"5", "a.b.wild.com", true},
+		{"6", "suffix.com", true},
+		{"7", "mail.suffix.com", true},
+		{"8", "notsuffix.com", false},
+		{"9", "upper.com", true},
+		{"10", "EXAMPLE.com.", true},
+		{"11", "com", false},
+		{"12", "au", false},
+		{"13", "", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := trie.Match(tt.domain); got != tt.want {
+				t.Errorf("DomainTrie.Match(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestNewDomainTrie_invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule string
+	}{
+		{"1", "mail.*.com"},
+		{"2", "*."},
+		{"3", "foo..com"},
+		{"4", "**.com"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewDomainTrie([]string{tt.rule}); err == nil {
+				t.Errorf("NewDomainTrie(%q) error = nil, want error", tt.rule)
+			}
+		})
+	}
+}
+
+func TestReadDomainTrie(t *testing.T) {
+	trie, err := ReadDomainTrie(strings.NewReader("# rules\n*.mailinator.com\n\nexample.com # exact\n"))
+	if err != nil {
+		t.Fatalf("ReadDomainTrie() error = %v", err)
+	}
+	if !trie.Match("foo.mailinator.com") || trie.Match("mailinator.com") || !trie.Match("example.com") {
+		t.Errorf("ReadDomainTrie() did not load the rules")
+	}
+}
+
+func BenchmarkDomainTrie_Match(b *testing.B) {
+	rules := make([]string, 0, 300000)
+	for i := 0; i < 100000; i++ {
+		rules = append(rules, fmt.Sprintf("exact%d.com", i), fmt.Sprintf("*.wild%d.net", i), fmt.Sprintf(".suffix%d.org", i))
+	}
+	trie, err := NewDomainTrie(rules)
+	if err != nil {
+		b.Fatal(err)
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		trie.Match("mail.wild99999.net")
+	}
+}
@@ -0,0 +1,182 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+)
+
+// Verdict summarizes whether mail to an address will be delivered.
+type Verdict int
+
+const (
+	// VerdictUnknown indicates that it could not be determined whether mail will be delivered,
+	// ie. because the mail host could not be reached.
+	VerdictUnknown Verdict = iota
+
+	// VerdictDeliverable indicates that the mail host accepted the recipient.
+	VerdictDeliverable
+
+	// VerdictUndeliverable indicates that the address is invalid, the domain has no mail host or
+	// the mail host rejected the recipient.
+	VerdictUndeliverable
+
+	// VerdictRisky indicates that the mail host accepts the recipient, but accepts any other
+	// recipient of the domain as well, so mail may still bounce.
+	VerdictRisky
+)
+
+func (v Verdict) String() string {
+	switch v {
+	case VerdictDeliverable:
+		return "deliverable"
+	case VerdictUndeliverable:
+		return "undeliverable"
+	case VerdictRisky:
+		return "risky"
+	default:
+		return "unknown"
+	}
+}
+
+// ValidationResult holds the outcome of every step of Verify.
+type ValidationResult struct {
+	// Address is the parsed address, it is empty if SyntaxValid is false.
+	Address EmailAddress
+
+	// SyntaxValid reports whether the address is well formed.
+	SyntaxValid bool
+
+	// HasMX reports whether the domain has a mail host, from its MX records or, when it has none,
+	// from its address records.
+	HasMX bool
+
+	// Host is the mail host that gave the answer of the SMTP steps.
+	Host string
+
+	// SMTPConnected reports whether the mail host was reached and greeted.
+	SMTPConnected bool
+
+	// RecipientAccepted reports whether the mail host accepted the recipient.
+	RecipientAccepted bool
+
+	// CatchAll reports whether the mail host accepts any recipient of the domain. It is only
+	// detected when the recipient was accepted.
+	CatchAll bool
+
+	// SMTPCode, SMTPEnhancedCode and SMTPMessage are the reply of the mail host to the command
+	// that failed, they are zero if no command failed. See SMTPError.
+	SMTPCode         int
+	SMTPEnhancedCode string
+	SMTPMessage      string
+
+	// Capabilities are the SMTP extensions announced by the mail host, such as SIZE, STARTTLS,
+	// SMTPUTF8, PIPELINING and 8BITMIME, nil if it could not be greeted. An internationalized
+	// address is undeliverable, with CodeSMTPUTF8Unsupported, to a host without SMTPUTF8.
+	Capabilities *Capabilities
+
+	// TLS is the TLS connection negotiated with the mail host, nil if the probe was made over
+	// plaintext.
+	TLS *TLSState
+
+	// Verdict summarizes the result.
+	Verdict Verdict
+
+	// Err is the error of the step that decided the verdict, nil if the address is deliverable.
+	Err error
+}
+
+// Verify is like ValidateHost, but reports the outcome of every step of the validation instead of
+// only an error, so callers can make their own decisions. When the recipient is accepted the mail
+// host is probed with a random address to detect a catch-all host. Use SetVerificationService to
+// verify with another service, such as a third-party API.
+func (e EmailAddress) Verify() ValidationResult {
+	return currentVerificationService().Verify(context.Background(), e.String())
+}
+
+// Verify parses address, internationalized addresses included, and validates it against its mail
+// host, reporting the outcome of every step. See EmailAddress.Verify.
+func (v *Verifier) Verify(ctx context.Context, address string) (r ValidationResult) {
+	ctx, span := v.startSpan(ctx, "emailaddress.Verify", map[string]string{"email.domain": domainOf(address)})
+	defer func() { span.End(r.Err) }()
+	e, err := Parse(address, WithSMTPUTF8())
+	if err != nil {
+		return r.undeliverable(err)
+	}
+	r.Address, r.SyntaxValid = *e, true
+
+	if err = v.checkLists(e.Domain); err != nil {
+		return r.undeliverable(err)
+	}
+	if c, ok := v.resultCache.get(e.String()); ok && c.Result != nil {
+		return c.result(r)
+	}
+	r, definitive := v.verify(ctx, r)
+	if definitive {
+		v.resultCache.put(resultEntry(r), r.Err)
+	}
+	return r
+}
+
+// verify validates r.Address against its mail host for Verify. It reports whether the result is
+// definitive, so it can be cached: temporary failures, unverifiable addresses and failed catch-all
+// probes are retried.
+func (v *Verifier) verify(ctx context.Context, r ValidationResult) (ValidationResult, bool) {
+	e := &r.Address
+	hosts, err := v.resolveHosts(ctx, e.Domain, true)
+	if err != nil {
+		if errors.Is(err, ErrUnverifiable) || ctx.Err() != nil {
+			return r.unknown(err), false
+		}
+		return r.undeliverable(err), true
+	}
+	r.HasMX = true
+	if err = v.checkParked(e.Domain, hosts[0]); err != nil {
+		return r.undeliverable(err), true
+	}
+
+	r.Host, r.Capabilities, err = v.probeHosts(ctx, hosts, *e)
+	r.SMTPConnected = r.Capabilities != nil
+	if r.SMTPConnected {
+		r.TLS = r.Capabilities.TLS
+	}
+	if err != nil {
+		var smtpErr *SMTPError
+		if errors.As(err, &smtpErr) {
+			r.SMTPCode, r.SMTPEnhancedCode, r.SMTPMessage = smtpErr.Code, smtpErr.EnhancedCode, smtpErr.Message
+		}
+		if rejected(err) {
+			return r.undeliverable(err), true
+		}
+		return r.unknown(err), false
+	}
+	if err = v.notProbed(); err != nil {
+		return r.unknown(err), false
+	}
+	r.RecipientAccepted = true
+
+	r.CatchAll, err = v.detectCatchAll(ctx, r.Host, e.Domain)
+	v.logf(ctx, "verify: %s: catch-all %v, error %v", e.Domain, r.CatchAll, err)
+	if r.CatchAll {
+		r.Verdict = VerdictRisky
+		return r, true
+	}
+	r.Verdict = VerdictDeliverable
+	return r, err == nil
+}
+
+func (r ValidationResult) undeliverable(err error) ValidationResult {
+	r.Verdict, r.Err = VerdictUndeliverable, err
+	return r
+}
+
+func (r ValidationResult) unknown(err error) ValidationResult {
+	r.Verdict, r.Err = VerdictUnknown, err
+	return r
+}
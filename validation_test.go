@@ -0,0 +1,204 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"net"
+	"reflect"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifier_Verify(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		switch {
+		case addr == "info@example.com", strings.HasSuffix(addr, "@catchall.com"):
+			return "250 OK"
+		case addr == "later@example.com":
+			return "451 4.7.1 greylisted"
+		default:
+			return "550 5.1.1 no such user"
+		}
+	}
+	v := NewVerifier(WithPort(s.port()))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{
+		"example.com":  {{Host: s.host(), Pref: 10}},
+		"catchall.com": {{Host: s.host(), Pref: 10}},
+	}}
+
+	tests := []struct {
+		name    string
+		address string
+		want    ValidationResult
+	}{
+		{"1", "info", ValidationResult{Verdict: VerdictUndeliverable}},
+		{"2", "info@example.net", ValidationResult{
+			Address: EmailAddress{"info", "example.net"}, SyntaxValid: true, Verdict: VerdictUndeliverable,
+		}},
+		{"3", "info@example.com", ValidationResult{
+			Address: EmailAddress{"info", "example.com"}, SyntaxValid: true, HasMX: true, Host: s.host(),
+			SMTPConnected: true, RecipientAccepted: true, Verdict: VerdictDeliverable,
+		}},
+		{"4", "info@catchall.com", ValidationResult{
+			Address: EmailAddress{"info", "catchall.com"}, SyntaxValid: true, HasMX: true, Host: s.host(),
+			SMTPConnected: true, RecipientAccepted: true, CatchAll: true, Verdict: VerdictRisky,
+		}},
+		{"5", "nobody@example.com", ValidationResult{
+			Address: EmailAddress{"nobody", "example.com"}, SyntaxValid: true, HasMX: true, Host: s.host(),
+			SMTPConnected: true, SMTPCode: 550, SMTPEnhancedCode: "5.1.1", SMTPMessage: "5.1.1 no such user", Verdict: VerdictUndeliverable,
+		}},
+		{"6", "later@example.com", ValidationResult{
+			Address: EmailAddress{"later", "example.com"}, SyntaxValid: true, HasMX: true, Host: s.host(),
+			SMTPConnected: true, SMTPCode: 451, SMTPEnhancedCode: "4.7.1", SMTPMessage: "4.7.1 greylisted", Verdict: VerdictUnknown,
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := v.Verify(context.Background(), tt.address)
+			if (got.Err != nil) != (tt.want.Verdict != VerdictDeliverable && tt.want.Verdict != VerdictRisky) {
+				t.Errorf("Verifier.Verify() error = %v, verdict %v", got.Err, got.Verdict)
+			}
+			if (got.Capabilities != nil) != tt.want.SMTPConnected {
+				t.Errorf("Verifier.Verify() capabilities = %v, want them if connected", got.Capabilities)
+			}
+			got.Err, got.Capabilities = nil, nil
+			if got != tt.want {
+				t.Errorf("Verifier.Verify() = %+v, want %+v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerdict_String(t *testing.T) {
+	tests := []struct {
+		name string
+		v    Verdict
+		want string
+	}{
+		{"1", VerdictUnknown, "unknown"},
+		{"2", VerdictDeliverable, "deliverable"},
+		{"3", VerdictUndeliverable, "undeliverable"},
+		{"4", VerdictRisky, "risky"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := tt.v.String(); got != tt.want {
+				t.Errorf("Verdict.String() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify_cache(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		switch addr {
+		case "info@example.com":
+			return "250 OK"
+		case "later@example.com":
+			return "451 4.7.1 greylisted"
+		}
+		return "550 5.1.1 no such user"
+	}
+	addr := net.JoinHostPort(s.host(), strconv.Itoa(s.port()))
+
+	tests := []struct {
+		name    string
+		address string
+		store   bool
+		want    Verdict
+		dials   int
+	}{
+		{"1", "nobody@example.com", false, VerdictUndeliverable, 1},
+		{"2", "nobody@example.com", true, VerdictUndeliverable, 1},
+		{"3", "info@example.com", false, VerdictDeliverable, 2},
+		{"4", "info@example.com", true, VerdictDeliverable, 2},
+		{"5", "later@example.com", false, VerdictUnknown, 2},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d := &testDialer{addr: addr}
+			opts := []Option{WithDialer(d), WithCache(time.Minute)}
+			if tt.store {
+				opts = append(opts, WithCacheStore(newMapStore()))
+			}
+			v := NewVerifier(opts...)
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: "mx.example.com", Pref: 10}}}}
+
+			first := v.Verify(context.Background(), tt.address)
+			got := v.Verify(context.Background(), tt.address)
+			if got.Verdict != tt.want || first.Verdict != tt.want {
+				t.Errorf("Verifier.Verify() = %v, then %v, want %v", first.Verdict, got.Verdict, tt.want)
+			}
+			if dials := len(d.dialed()); dials != tt.dials {
+				t.Errorf("Verifier.Verify() dialed %d times, want %d", dials, tt.dials)
+			}
+			if (got.Err == nil) != (first.Err == nil) || got.Err != nil && (got.Err.Error() != first.Err.Error() || ErrorCode(got.Err) != ErrorCode(first.Err)) {
+				t.Errorf("Verifier.Verify() error = %v, then %v", first.Err, got.Err)
+			}
+			if !reflect.DeepEqual(got.Capabilities, first.Capabilities) {
+				t.Errorf("Verifier.Verify() capabilities = %+v, then %+v", first.Capabilities, got.Capabilities)
+			}
+			first.Err, got.Err, first.Capabilities, got.Capabilities = nil, nil, nil, nil
+			if got != first {
+				t.Errorf("Verifier.Verify() = %+v, then %+v", first, got)
+			}
+		})
+	}
+}
+
+func TestVerifier_Verify_SMTPUTF8(t *testing.T) {
+	ascii := newTestServer(t, "127.0.0.1:0")
+	utf8 := newTestServer(t, "127.0.0.1:0")
+	utf8.extensions = []string{"PIPELINING", "SMTPUTF8"}
+	for _, s := range []*testServer{ascii, utf8} {
+		s.rcpt = func(addr string) string {
+			if addr == "info@example.com" || addr == "用户@example.com" {
+				return "250 OK"
+			}
+			return "550 no such user"
+		}
+	}
+
+	tests := []struct {
+		name     string
+		server   *testServer
+		address  string
+		want     Verdict
+		wantCode Code
+		wantRcpt bool
+	}{
+		{"1", utf8, "用户@example.com", VerdictDeliverable, "", true},
+		{"2", ascii, "用户@example.com", VerdictUndeliverable, CodeSMTPUTF8Unsupported, false},
+		{"3", ascii, "info@example.com", VerdictDeliverable, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithPort(tt.server.port()))
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: tt.server.host(), Pref: 10}}}}
+			got := v.Verify(context.Background(), tt.address)
+			if got.Verdict != tt.want || ErrorCode(got.Err) != tt.wantCode {
+				t.Errorf("Verifier.Verify() = %v, %v, want %v, %v", got.Verdict, got.Err, tt.want, tt.wantCode)
+			}
+			if got.Capabilities.SMTPUTF8() != (tt.server == utf8) {
+				t.Errorf("Verifier.Verify() capabilities = %v", got.Capabilities)
+			}
+			sent := false
+			for _, cmd := range tt.server.commands() {
+				sent = sent || cmd == "RCPT TO:<"+tt.address+">"
+			}
+			if sent != tt.wantRcpt {
+				t.Errorf("Verifier.Verify() sent RCPT TO %v = %v, want %v", tt.address, sent, tt.wantRcpt)
+			}
+		})
+	}
+}
@@ -0,0 +1,821 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/smtp"
+	"net/textproto"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultPort is the port used to start a mail transaction with a host.
+const defaultPort = 587
+
+// defaultHostCacheTTL is the time the package level functions cache the mail hosts of a domain.
+const defaultHostCacheTTL = 5 * time.Minute
+
+// heloCacheTTL is how long the names of WithHeloFromReverseDNS are cached.
+const heloCacheTTL = time.Hour
+
+// defaultVerifier is used by the package level validation functions.
+var defaultVerifier = NewVerifier(WithHostCache(defaultHostCacheTTL))
+
+// Verifier validates email addresses against their remote mail hosts. A Verifier is safe for
+// concurrent use by multiple goroutines. Use NewVerifier to create one. The DNS lookups and SMTP
+// conversations of its methods are aborted when their context is cancelled or its deadline
+// passes, in which case the error of the context is returned.
+type Verifier struct {
+	localIP     net.IP
+	localIface  string
+	port        int
+	sender      string
+	dialTimeout time.Duration
+	dialer      Dialer
+	httpClient  *http.Client
+	tlsConfig   *tls.Config
+	requireTLS  bool
+	implicitTLS bool
+	auth        smtp.Auth
+	relay       string
+	cmdTimeout  time.Duration
+	retries     int
+	retryDelay  time.Duration
+	retryRotate bool
+	heloDomain  string
+	heloAuto    bool
+	probeConfig ProbeConfig
+	probeAudit  func(ProbeRecord)
+	probeDepth  ProbeDepth
+	transcript  *transcript
+
+	resolver    resolver
+	hedgeDelay  time.Duration
+	dnsTimeout  time.Duration
+	dnsAttempts int
+	dnsBackoff  time.Duration
+	dnsSoftFail bool
+	mxOnly      bool
+	wildcardDNS bool
+
+	logger  Logger
+	metrics Metrics
+	tracer  Tracer
+	offline bool
+
+	denyList       DomainList
+	disposableList DomainList
+	allowList      DomainList
+
+	parkingHosts    DomainList
+	parkingNetworks []*net.IPNet
+
+	hostCache     *cache
+	resultCache   *cache
+	catchAllCache *cache
+	negativeTTL   time.Duration
+	cacheStore    Cache
+
+	connLimit   connLimiter
+	dnsBudget   *budget
+	probeBudget *budget
+
+	batchWorkers int
+
+	mu        sync.Mutex
+	heloCache map[string]heloEntry
+}
+
+// heloEntry is the cached HELO name of a local IP address.
+type heloEntry struct {
+	name    string
+	expires time.Time
+}
+
+// Option configures a Verifier.
+type Option func(*Verifier)
+
+// WithLocalAddr binds outgoing SMTP connections to the given local IP address. This is needed on
+// multi-homed hosts where probes must originate from the IP whose reverse DNS matches the HELO
+// name.
+func WithLocalAddr(ip net.IP) Option {
+	return func(v *Verifier) {
+		v.localIP = ip
+	}
+}
+
+// WithInterface binds outgoing SMTP connections to an address of the named network interface of
+// the address family of the mail host, preferring IPv4 addresses. Connections to hosts of a family
+// the interface has no address of are not bound. The interface is resolved when connecting, so an
+// unknown interface results in an error from the validation methods.
+func WithInterface(name string) Option {
+	return func(v *Verifier) {
+		v.localIface = name
+	}
+}
+
+// WithPort sets the port mail hosts are contacted on. Defaults to 587, use 25 to talk to a mail
+// host the way other mail hosts do.
+func WithPort(port int) Option {
+	return func(v *Verifier) {
+		v.port = port
+	}
+}
+
+// WithSenderAddress sets the address sent in the MAIL FROM command. By default hello@ followed by
+// the domain of the email address that is validated is used, which many hosts treat as spoofing.
+// Use an address of a domain you control.
+func WithSenderAddress(addr string) Option {
+	return func(v *Verifier) {
+		v.sender = addr
+	}
+}
+
+// WithDialTimeout limits the time to connect to a mail host.
+func WithDialTimeout(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.dialTimeout = d
+	}
+}
+
+// Dialer opens the connections to mail hosts. It is implemented by *net.Dialer and by the dialers
+// of golang.org/x/net/proxy, ie. to probe through a SOCKS5 proxy where outbound connections to
+// port 25 are blocked. A Dialer that also has the DialContext method of *net.Dialer is canceled
+// with the context of the validation.
+type Dialer interface {
+	Dial(network, addr string) (net.Conn, error)
+}
+
+// contextDialer is a Dialer that can be canceled.
+type contextDialer interface {
+	DialContext(ctx context.Context, network, addr string) (net.Conn, error)
+}
+
+// WithDialer sets the dialer used to connect to mail hosts. The local address of WithLocalAddr
+// and WithInterface is not used with a custom dialer, configure it on the dialer instead.
+func WithDialer(d Dialer) Option {
+	return func(v *Verifier) {
+		v.dialer = d
+	}
+}
+
+// WithCommandTimeout limits the time to send every SMTP command and read its reply, so a host
+// that stalls on a single command can't hold the connection.
+func WithCommandTimeout(d time.Duration) Option {
+	return func(v *Verifier) {
+		v.cmdTimeout = d
+	}
+}
+
+// WithRetry makes probes that are answered with a temporary failure (4xx), such as hosts that
+// greylist the first attempt of a sender, attempted up to attempts times. The host is probed again
+// after backoff, this time is doubled after every attempt. Defaults to 1.
+func WithRetry(attempts int, backoff time.Duration) Option {
+	return func(v *Verifier) {
+		v.retries, v.retryDelay = attempts, backoff
+	}
+}
+
+// WithRetryRotation makes the retries of WithRetry probe all mail hosts of the domain again in
+// order of preference, instead of only the host that answered with the temporary failure.
+func WithRetryRotation() Option {
+	return func(v *Verifier) {
+		v.retryRotate = true
+	}
+}
+
+// WithHeloDomain sets the name sent in the HELO/EHLO command. By default the domain of the email
+// address that is validated is used. It takes precedence over WithHeloFromReverseDNS.
+func WithHeloDomain(name string) Option {
+	return func(v *Verifier) {
+		v.heloDomain = name
+	}
+}
+
+// WithHeloFromReverseDNS derives the HELO/EHLO name from the reverse DNS of the local IP address
+// used for the connection. If the IP address has no reverse DNS the address literal (ie.
+// [192.0.2.1]) is used instead. Either name is cached per IP address for an hour.
+func WithHeloFromReverseDNS() Option {
+	return func(v *Verifier) {
+		v.heloAuto = true
+	}
+}
+
+// NewVerifier returns a Verifier configured with the given options.
+func NewVerifier(opts ...Option) *Verifier {
+	v := &Verifier{
+		port:         defaultPort,
+		probeDepth:   ProbeRcpt,
+		probeConfig:  defaultProbeConfig,
+		parkingHosts: defaultParkingHosts,
+		resolver:     defaultResolver{},
+		heloCache:    make(map[string]heloEntry),
+	}
+	for _, opt := range opts {
+		opt(v)
+	}
+	for prefix, c := range map[string]*cache{"host:": v.hostCache, "result:": v.resultCache, "catchall:": v.catchAllCache} {
+		if c != nil {
+			c.negativeTTL, c.store, c.prefix, c.metrics = v.negativeTTL, v.cacheStore, prefix, v.metrics
+		}
+	}
+	for prefix, b := range map[string]*budget{"dnsbudget:": v.dnsBudget, "probebudget:": v.probeBudget} {
+		if b != nil {
+			b.store, b.prefix = v.cacheStore, prefix
+		}
+	}
+	if v.dnsBudget != nil {
+		if h, ok := v.resolver.(*hedgedResolver); ok {
+			for i, r := range h.resolvers {
+				h.resolvers[i] = &budgetResolver{resolver: r, budget: v.dnsBudget, key: h.servers[i]}
+			}
+		} else {
+			v.resolver = &budgetResolver{resolver: v.resolver, budget: v.dnsBudget, key: "system"}
+		}
+	}
+	if v.dnsTimeout > 0 || v.dnsAttempts > 1 {
+		v.resolver = &retryResolver{
+			resolver: v.resolver,
+			timeout:  v.dnsTimeout,
+			attempts: v.dnsAttempts,
+			backoff:  v.dnsBackoff,
+		}
+	}
+	if v.metrics != nil {
+		v.resolver = &metricsResolver{resolver: v.resolver, metrics: v.metrics}
+	}
+	if v.tracer != nil {
+		v.resolver = &tracingResolver{resolver: v.resolver, tracer: v.tracer}
+	}
+	return v
+}
+
+// ValidateHost will test if the email address is actually reachable. It will first try to resolve
+// the host and then start a mail transaction. When the mail host can't be reached or fails
+// temporarily, the backup mail hosts are tried in order of preference.
+func (v *Verifier) ValidateHost(ctx context.Context, e EmailAddress) (err error) {
+	ctx, span := v.startSpan(ctx, "emailaddress.ValidateHost", map[string]string{"email.domain": e.Domain})
+	defer func() { span.End(err) }()
+	if err = v.checkLists(e.Domain); err != nil {
+		return err
+	}
+	hosts, err := v.lookupHosts(ctx, e.Domain)
+	if err != nil {
+		return err
+	}
+	if err = v.checkParked(e.Domain, hosts[0]); err != nil {
+		return err
+	}
+	_, _, err = v.probeHosts(ctx, hosts, e)
+	return err
+}
+
+// CheckHost tests if the email address is reachable like ValidateHost, but distinguishes
+// addresses that are definitively invalid from addresses that could not be verified. A host that
+// can't be reached, for instance because outgoing SMTP traffic is blocked, a temporary DNS failure
+// or a temporary SMTP failure all result in HostUnverifiable. The returned error describes why the
+// address was not verified. Addresses rejected by the deny or disposable list and addresses at
+// parked domains (see WithParkingList) are HostInvalid.
+func (v *Verifier) CheckHost(ctx context.Context, e EmailAddress) (status HostStatus, err error) {
+	ctx, span := v.startSpan(ctx, "emailaddress.CheckHost", map[string]string{"email.domain": e.Domain})
+	defer func() { span.End(err) }()
+	if err = v.checkLists(e.Domain); err != nil {
+		return HostInvalid, err
+	}
+	if c, ok := v.resultCache.get(e.String()); ok {
+		return c.Status, c.err()
+	}
+	status, err = v.checkHost(ctx, e)
+	if status != HostUnverifiable {
+		v.resultCache.put(cacheEntry{Key: e.String(), Status: status}, err)
+	}
+	return status, err
+}
+
+func (v *Verifier) checkHost(ctx context.Context, e EmailAddress) (HostStatus, error) {
+	hosts, err := v.resolveHosts(ctx, e.Domain, true)
+	if err != nil {
+		if errors.Is(err, ErrUnverifiable) {
+			return HostUnverifiable, err
+		}
+		return HostInvalid, err
+	}
+	if err = v.checkParked(e.Domain, hosts[0]); err != nil {
+		return HostInvalid, err
+	}
+	_, _, err = v.probeHosts(ctx, hosts, e)
+	if err == nil {
+		if err = v.notProbed(); err != nil {
+			return HostUnverifiable, err
+		}
+		return HostVerified, nil
+	}
+	if rejected(err) {
+		return HostInvalid, err
+	}
+	return HostUnverifiable, err
+}
+
+// TryHost will verify if we can start a mail transaction with the host. A lot of
+// hosts block this method so don't expect much from it.
+func (v *Verifier) TryHost(ctx context.Context, host string, e EmailAddress) error {
+	return v.probe(ctx, host, e)
+}
+
+// probeHosts probes the mail hosts of a domain in order of preference. When a host can't be
+// reached or replies with a temporary failure the next host is probed, the first definitive answer
+// is returned. If no host gives one, the error of the most preferred host that replied is returned.
+// The host of the returned answer and its capabilities, if it could be greeted, are returned too.
+// Temporary failures are retried as configured by WithRetry.
+func (v *Verifier) probeHosts(ctx context.Context, hosts []string, e EmailAddress) (string, *Capabilities, error) {
+	if v.offline {
+		return hosts[0], nil, nil
+	}
+	hosts = v.probeTargets(hosts)
+	host, caps, err := v.probeAll(ctx, hosts, e)
+	backoff := v.retryDelay
+	for attempt := 1; attempt < v.retries && ErrorCode(err) == CodeSMTPTemporary; attempt++ {
+		v.logf(ctx, "smtp: %s: %v, retrying in %v", host, err, backoff)
+		select {
+		case <-time.After(backoff):
+		case <-ctx.Done():
+			return host, caps, ctx.Err()
+		}
+		backoff *= 2
+		if v.retryRotate {
+			host, caps, err = v.probeAll(ctx, hosts, e)
+		} else {
+			caps, err = v.transaction(ctx, host, e, true)
+		}
+	}
+	return host, caps, err
+}
+
+// probeAll probes every mail host once, see probeHosts.
+func (v *Verifier) probeAll(ctx context.Context, hosts []string, e EmailAddress) (string, *Capabilities, error) {
+	var best struct {
+		host string
+		caps *Capabilities
+		err  error
+	}
+	for _, host := range hosts {
+		caps, err := v.transaction(ctx, host, e, true)
+		if err == nil || ctx.Err() != nil || !fallThrough(err) {
+			return host, caps, err
+		}
+		v.logf(ctx, "smtp: %s: %v, trying the next mail host", host, err)
+		if best.err == nil || ErrorCode(best.err) == CodeSMTPUnreachable && ErrorCode(err) != CodeSMTPUnreachable {
+			best.host, best.caps, best.err = host, caps, err
+		}
+	}
+	return best.host, best.caps, best.err
+}
+
+// fallThrough reports whether err, the result of a probe, is worth probing the next mail host.
+func fallThrough(err error) bool {
+	switch ErrorCode(err) {
+	case CodeSMTPUnreachable, CodeSMTPTemporary, CodeSMTPFailure, CodeTLSRequired, CodeSMTPUTF8Unsupported:
+		return true
+	}
+	return false
+}
+
+// rejected reports whether err, the result of a probe, means mail to the recipient won't be
+// delivered.
+func rejected(err error) bool {
+	switch ErrorCode(err) {
+	case CodeMailboxRejected, CodeSMTPUTF8Unsupported:
+		return true
+	}
+	return false
+}
+
+// probe starts a mail transaction with host for the recipient e. A permanent rejection of the
+// recipient returns an error with CodeMailboxRejected.
+func (v *Verifier) probe(ctx context.Context, host string, e EmailAddress) error {
+	_, err := v.transaction(ctx, host, e, true)
+	return err
+}
+
+// transaction greets host and, if rcpt is true, starts a mail transaction for the recipient e.
+// The capabilities of the host are returned once it has been greeted, also if the recipient is
+// rejected.
+func (v *Verifier) transaction(ctx context.Context, host string, e EmailAddress, rcpt bool) (*Capabilities, error) {
+	var rcpts []EmailAddress
+	if rcpt {
+		rcpts = []EmailAddress{e}
+	}
+	caps, rcptErrs, err := v.session(ctx, host, e, rcpts)
+	if err == nil && len(rcptErrs) > 0 {
+		err = rcptErrs[0]
+	}
+	return caps, err
+}
+
+// sessionError returns err, or else the first error of rcptErrs.
+func sessionError(err error, rcptErrs []error) error {
+	for _, rcptErr := range rcptErrs {
+		if err == nil {
+			err = rcptErr
+		}
+	}
+	return err
+}
+
+// session greets host and starts a mail transaction for the recipients rcpts, which may be none.
+// e is the address the HELO name and sender are chosen for. It returns the error of every
+// recipient, or an error if the transaction could not be started. After a recipient is rejected
+// the others are still tried, unless the connection is lost. When recipients are given the
+// conversation stops at the probe depth of v, leaving them without error. Offline, host is not
+// contacted and an error matching ErrUnverifiable is returned.
+func (v *Verifier) session(ctx context.Context, host string, e EmailAddress, rcpts []EmailAddress) (caps *Capabilities, rcptErrs []error, err error) {
+	if v.offline {
+		return nil, nil, fmt.Errorf("%s not contacted, offline: %w", host, ErrUnverifiable)
+	}
+	e.Domain = asciiDomain(e.Domain)
+	host = unbracketHost(host)
+	if err := v.probeBudget.take(provider(host)); err != nil {
+		return nil, nil, newError(CodeBudgetExhausted, err)
+	}
+	var release func()
+	release, err = v.connLimit.acquire(ctx, host)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	ctx, probeSpan := v.startSpan(ctx, "smtp.probe", map[string]string{"smtp.host": host})
+	defer func() { probeSpan.End(sessionError(err, rcptErrs)) }()
+
+	start := time.Now()
+	dialCtx, dialSpan := v.startSpan(ctx, "smtp.connect", map[string]string{"smtp.host": host})
+	conn, err := v.dial(dialCtx, host)
+	dialSpan.End(err)
+	if v.metrics != nil {
+		v.metrics.SMTPConnect(provider(host), time.Since(start), err)
+	}
+	if err != nil {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return nil, nil, ctxErr
+		}
+		return nil, nil, newError(CodeSMTPUnreachable, err)
+	}
+	if v.metrics != nil {
+		defer func(start time.Time) {
+			v.metrics.SMTPProbe(provider(host), replyClass(sessionError(err, rcptErrs)), time.Since(start))
+		}(time.Now())
+	}
+	stop := watchContext(ctx, conn)
+	defer stop()
+	if v.cmdTimeout > 0 {
+		deadline, _ := ctx.Deadline()
+		conn = &commandConn{Conn: conn, timeout: v.cmdTimeout, deadline: deadline}
+	}
+
+	// fail returns the error of the context if it was cancelled during the conversation, since
+	// the resulting network error doesn't tell why the connection was closed.
+	fail := func(err error, rcpt bool) error {
+		if ctxErr := ctx.Err(); ctxErr != nil {
+			return ctxErr
+		}
+		// The connection deadline may pass just before the context reports it.
+		if d, ok := ctx.Deadline(); ok && !time.Now().Before(d) {
+			return context.DeadlineExceeded
+		}
+		return smtpError(err, rcpt)
+	}
+
+	uc := &upgradableConn{Conn: conn}
+	var tlsState *TLSState
+	if v.implicitTLS {
+		if tlsState, err = v.handshake(uc, host); err != nil {
+			conn.Close() // #nosec
+			if ErrorCode(err) != CodeTLSRequired {
+				err = fail(err, false)
+			}
+			return nil, nil, err
+		}
+	}
+	lc := &lineConn{Conn: uc}
+	lc.observe(v.transcript.observer(host))
+	lc.observe(v.replyObserver(ctx, host))
+	spans := v.newCommandSpans(ctx, host)
+	lc.observe(spans.observer())
+	defer spans.close()
+	cc := &captureConn{Conn: lc}
+	client, err := smtp.NewClient(cc, host)
+	if err != nil {
+		conn.Close() // #nosec
+		return nil, nil, fail(err, false)
+	}
+	defer client.Close()
+	if len(rcpts) > 0 && v.probeDepth == ProbeConnectOnly {
+		command(client.Text, 221, "QUIT") // #nosec Quit would greet the host first
+		return parseCapabilities(host, cc.stop()), make([]error, len(rcpts)), nil
+	}
+
+	helo := v.heloName(ctx, conn, e)
+	if err = client.Hello(helo); err != nil {
+		return nil, nil, fail(err, false)
+	}
+	caps = parseCapabilities(host, cc.stop())
+	caps.TLS = tlsState
+	if !v.implicitTLS {
+		if caps.TLS, err = v.startTLS(client, uc, host, helo, caps); err != nil {
+			if ErrorCode(err) != CodeTLSRequired {
+				err = fail(err, false)
+			}
+			return caps, nil, err
+		}
+	}
+	if v.auth != nil {
+		lc.redact = true
+		err = authenticate(client.Text, v.auth, host, caps)
+		lc.redact = false
+		if err != nil {
+			return caps, nil, fail(err, false)
+		}
+	}
+	if len(rcpts) == 0 || v.probeDepth == ProbeHelo {
+		client.Quit() // #nosec
+		return caps, make([]error, len(rcpts)), nil
+	}
+	if err = client.Mail(v.senderAddress(e)); err != nil {
+		return caps, nil, fail(err, false)
+	}
+	if v.probeDepth == ProbeMailFrom {
+		client.Reset() // #nosec
+		client.Quit()  // #nosec
+		return caps, make([]error, len(rcpts)), nil
+	}
+	rcptErrs = make([]error, len(rcpts))
+	failed := false
+	for i, r := range rcpts {
+		// An internationalized address can't be sent to a host without SMTPUTF8.
+		if r.IsInternational() && !caps.SMTPUTF8() {
+			rcptErrs[i] = newError(CodeSMTPUTF8Unsupported, fmt.Errorf("%s does not support SMTPUTF8, required for %s", host, r))
+			v.logf(ctx, "smtp: RCPT TO %s at %s: error %v", r, host, rcptErrs[i])
+			continue
+		}
+		r.Domain = asciiDomain(r.Domain)
+		err = client.Rcpt(r.String())
+		v.logf(ctx, "smtp: RCPT TO %s at %s: error %v", r, host, err)
+		if err == nil {
+			continue
+		}
+		rcptErrs[i], failed = fail(err, true), true
+		// Without a reply the connection is lost, the remaining recipients fail alike.
+		if ErrorCode(rcptErrs[i]) == "" || ErrorCode(rcptErrs[i]) == CodeSMTPFailure {
+			for j := i + 1; j < len(rcpts); j++ {
+				rcptErrs[j] = rcptErrs[i]
+			}
+			break
+		}
+	}
+	if !failed {
+		client.Reset() // #nosec
+		client.Quit()  // #nosec
+	}
+	return caps, rcptErrs, nil
+}
+
+// watchContext applies the deadline of ctx to conn and closes conn when ctx is cancelled, so a
+// slow or unresponsive host can't block the SMTP commands. The returned function stops watching.
+func watchContext(ctx context.Context, conn net.Conn) func() {
+	if d, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(d) // #nosec
+	}
+	done := make(chan struct{})
+	go func() {
+		select {
+		case <-ctx.Done():
+			conn.Close() // #nosec
+		case <-done:
+		}
+	}()
+	return func() { close(done) }
+}
+
+// senderAddress returns the address for the MAIL FROM command when validating e.
+func (v *Verifier) senderAddress(e EmailAddress) string {
+	if v.sender != "" {
+		return v.sender
+	}
+	return fmt.Sprintf("hello@%s", e.Domain)
+}
+
+// commandConn limits the time of every read and write to timeout, without extending the deadline
+// of the context.
+type commandConn struct {
+	net.Conn
+	timeout  time.Duration
+	deadline time.Time
+}
+
+func (c *commandConn) extend() {
+	d := time.Now().Add(c.timeout)
+	if !c.deadline.IsZero() && c.deadline.Before(d) {
+		d = c.deadline
+	}
+	c.Conn.SetDeadline(d) // #nosec
+}
+
+func (c *commandConn) Read(b []byte) (int, error) {
+	c.extend()
+	return c.Conn.Read(b)
+}
+
+func (c *commandConn) Write(b []byte) (int, error) {
+	c.extend()
+	return c.Conn.Write(b)
+}
+
+// dial opens a connection to the SMTP port of host, bound to the configured local address.
+func (v *Verifier) dial(ctx context.Context, host string) (net.Conn, error) {
+	addr := net.JoinHostPort(host, strconv.Itoa(v.port))
+	if v.dialer == nil {
+		ip, remote, err := v.localAddr(ctx, host)
+		if err != nil {
+			return nil, err
+		}
+		addr = net.JoinHostPort(remote, strconv.Itoa(v.port))
+		d := net.Dialer{Timeout: v.dialTimeout}
+		if ip != nil {
+			d.LocalAddr = &net.TCPAddr{IP: ip}
+		}
+		conn, err := d.DialContext(ctx, "tcp", addr)
+		v.logf(ctx, "smtp: dial %s: error %v", addr, err)
+		return conn, err
+	}
+
+	if v.dialTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, v.dialTimeout)
+		defer cancel()
+	}
+	conn, err := dialContext(ctx, v.dialer, addr)
+	v.logf(ctx, "smtp: dial %s: error %v", addr, err)
+	return conn, err
+}
+
+// dialContext dials addr with d, it returns when ctx is done even if d can't be canceled.
+func dialContext(ctx context.Context, d Dialer, addr string) (net.Conn, error) {
+	if d, ok := d.(contextDialer); ok {
+		return d.DialContext(ctx, "tcp", addr)
+	}
+	type result struct {
+		conn net.Conn
+		err  error
+	}
+	done := make(chan result, 1)
+	go func() {
+		conn, err := d.Dial("tcp", addr)
+		done <- result{conn, err}
+	}()
+	select {
+	case r := <-done:
+		return r.conn, r.err
+	case <-ctx.Done():
+		go func() {
+			if r := <-done; r.conn != nil {
+				r.conn.Close() // #nosec
+			}
+		}()
+		return nil, ctx.Err()
+	}
+}
+
+// heloName returns the name to identify with when connected to a host over conn.
+func (v *Verifier) heloName(ctx context.Context, conn net.Conn, e EmailAddress) string {
+	if v.heloDomain != "" {
+		return v.heloDomain
+	}
+	if !v.heloAuto {
+		return e.Domain
+	}
+	addr, ok := conn.LocalAddr().(*net.TCPAddr)
+	if !ok {
+		return e.Domain
+	}
+	ip := addr.IP.String()
+
+	v.mu.Lock()
+	c, ok := v.heloCache[ip]
+	v.mu.Unlock()
+	if ok && time.Now().Before(c.expires) {
+		return c.name
+	}
+
+	names, err := v.resolver.LookupAddr(ctx, ip)
+	var name string
+	switch {
+	case err == nil && len(names) > 0:
+		name = strings.TrimSuffix(names[0], ".")
+	case addr.IP.To4() == nil:
+		name = fmt.Sprintf("[IPv6:%s]", ip)
+	default:
+		name = fmt.Sprintf("[%s]", ip)
+	}
+	// The address literal is cached too, unless the lookup was cut short by ctx.
+	if ctx.Err() == nil {
+		v.mu.Lock()
+		v.heloCache[ip] = heloEntry{name: name, expires: time.Now().Add(heloCacheTTL)}
+		v.mu.Unlock()
+	}
+	return name
+}
+
+// localAddr returns the local IP a connection to host should be bound to, or nil if the
+// operating system may choose, and the address to dial host at. With WithInterface the addresses
+// of host are looked up to bind to an address of the same family, host is dialed at that address.
+func (v *Verifier) localAddr(ctx context.Context, host string) (net.IP, string, error) {
+	if v.localIP != nil || v.localIface == "" {
+		return v.localIP, host, nil
+	}
+	locals, err := v.interfaceAddrs()
+	if err != nil {
+		return nil, "", err
+	}
+	remotes := []net.IP{net.ParseIP(host)}
+	if remotes[0] == nil {
+		if remotes, err = v.resolver.LookupIP(ctx, "ip", host); err != nil {
+			return nil, host, nil // dialing host reports the error
+		}
+	}
+	if local, remote := sameFamily(locals, remotes); local != nil {
+		return local, remote.String(), nil
+	}
+	return nil, host, nil
+}
+
+// interfaceAddrs returns the usable addresses of the interface of WithInterface, IPv4 addresses
+// first.
+func (v *Verifier) interfaceAddrs() ([]net.IP, error) {
+	iface, err := net.InterfaceByName(v.localIface)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := iface.Addrs()
+	if err != nil {
+		return nil, err
+	}
+	var ips, ipv6 []net.IP
+	for _, a := range addrs {
+		n, ok := a.(*net.IPNet)
+		if !ok || n.IP.IsLinkLocalUnicast() {
+			continue
+		}
+		if n.IP.To4() != nil {
+			ips = append(ips, n.IP)
+		} else {
+			ipv6 = append(ipv6, n.IP)
+		}
+	}
+	if ips = append(ips, ipv6...); len(ips) == 0 {
+		return nil, fmt.Errorf("no usable address found on interface %s", v.localIface)
+	}
+	return ips, nil
+}
+
+// sameFamily returns the first of locals with an address of the same family in remotes, and that
+// address, or nils if the families don't match.
+func sameFamily(locals, remotes []net.IP) (local, remote net.IP) {
+	for _, l := range locals {
+		for _, r := range remotes {
+			if (l.To4() != nil) == (r.To4() != nil) {
+				return l, r
+			}
+		}
+	}
+	return nil, nil
+}
+
+// smtpError returns err, the result of an SMTP command, with a code and replies of the host as an
+// *SMTPError. rcpt reports whether err is the reply to the RCPT command.
+func smtpError(err error, rcpt bool) error {
+	var tpErr *textproto.Error
+	if !errors.As(err, &tpErr) {
+		return newError(CodeSMTPFailure, err)
+	}
+	smtpErr := newSMTPError(tpErr)
+	switch {
+	case smtpErr.Code < 500:
+		return newError(CodeSMTPTemporary, smtpErr)
+	case rcpt:
+		return newError(CodeMailboxRejected, smtpErr)
+	default:
+		return newError(CodeSMTPRejected, smtpErr)
+	}
+}
@@ -0,0 +1,568 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// loopbackInterface returns the name of the loopback interface.
+func loopbackInterface(t *testing.T) string {
+	t.Helper()
+	ifaces, err := net.Interfaces()
+	if err != nil {
+		t.Skip(err)
+	}
+	for _, iface := range ifaces {
+		if iface.Flags&net.FlagLoopback != 0 {
+			return iface.Name
+		}
+	}
+	t.Skip("no loopback interface")
+	return ""
+}
+
+func TestVerifier_TryHost(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		if addr == "fake@example.com" {
+			return "550 no such user"
+		}
+		return "250 OK"
+	}
+
+	tests := []struct {
+		name    string
+		opts    []Option
+		e       EmailAddress
+		wantErr bool
+	}{
+		{"1", nil, EmailAddress{"info", "example.com"}, false},
+		{"2", nil, EmailAddress{"fake", "example.com"}, true},
+		{"3", []Option{WithLocalAddr(net.ParseIP("127.0.0.1"))}, EmailAddress{"info", "example.com"}, false},
+		{"4", []Option{WithInterface(loopbackInterface(t))}, EmailAddress{"info", "example.com"}, false},
+		{"5", []Option{WithInterface("does-not-exist0")}, EmailAddress{"info", "example.com"}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(tt.opts...)
+			v.port = s.port()
+			if err := v.TryHost(context.Background(), s.host(), tt.e); (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.TryHost() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
+
+func TestVerifier_localAddr(t *testing.T) {
+	lo := loopbackInterface(t)
+	tests := []struct {
+		name       string
+		opts       []Option
+		host       string
+		want       net.IP
+		wantRemote string
+		wantErr    bool
+	}{
+		{"1", nil, "mx.example.com", nil, "mx.example.com", false},
+		{"2", []Option{WithLocalAddr(net.ParseIP("192.0.2.1"))}, "mx.example.com", net.ParseIP("192.0.2.1"), "mx.example.com", false},
+		{"3", []Option{WithInterface(lo)}, "127.0.0.1", net.ParseIP("127.0.0.1"), "127.0.0.1", false},
+		{"4", []Option{WithInterface(lo)}, "mx.example.com", net.ParseIP("127.0.0.1"), "127.0.0.2", false},
+		{"5", []Option{WithInterface(lo)}, "unknown.example.com", nil, "unknown.example.com", false},
+		{"6", []Option{WithInterface("does-not-exist0")}, "127.0.0.1", nil, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(tt.opts...)
+			v.resolver = &testResolver{ips: map[string][]net.IP{"mx.example.com": {net.ParseIP("2001:db8::1"), net.ParseIP("127.0.0.2")}}}
+			got, remote, err := v.localAddr(context.Background(), tt.host)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Verifier.localAddr() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !got.Equal(tt.want) || remote != tt.wantRemote {
+				t.Errorf("Verifier.localAddr() = %v, %v, want %v, %v", got, remote, tt.want, tt.wantRemote)
+			}
+		})
+	}
+}
+
+func Test_sameFamily(t *testing.T) {
+	v4, v6 := net.ParseIP("192.0.2.1"), net.ParseIP("2001:db8::1")
+	tests := []struct {
+		name       string
+		locals     []net.IP
+		remotes    []net.IP
+		wantLocal  net.IP
+		wantRemote net.IP
+	}{
+		{"1", []net.IP{v4, v6}, []net.IP{net.ParseIP("2001:db8::2")}, v6, net.ParseIP("2001:db8::2")},
+		{"2", []net.IP{v4, v6}, []net.IP{net.ParseIP("2001:db8::2"), net.ParseIP("192.0.2.2")}, v4, net.ParseIP("192.0.2.2")},
+		{"3", []net.IP{v4}, []net.IP{net.ParseIP("2001:db8::2")}, nil, nil},
+		{"4", []net.IP{v6}, []net.IP{net.ParseIP("192.0.2.2")}, nil, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			local, remote := sameFamily(tt.locals, tt.remotes)
+			if !local.Equal(tt.wantLocal) || !remote.Equal(tt.wantRemote) {
+				t.Errorf("sameFamily() = %v, %v, want %v, %v", local, remote, tt.wantLocal, tt.wantRemote)
+			}
+		})
+	}
+}
+
+func TestVerifier_heloName(t *testing.T) {
+	rdns := &testResolver{addrs: map[string][]string{"127.0.0.1": {"mail.example.org."}}}
+	nordns := &testResolver{err: errors.New("no PTR record")}
+	tests := []struct {
+		name     string
+		opts     []Option
+		resolver *testResolver
+		want     string
+	}{
+		{"1", nil, rdns, "EHLO example.com"},
+		{"2", []Option{WithHeloDomain("probe.example.net")}, rdns, "EHLO probe.example.net"},
+		{"3", []Option{WithHeloFromReverseDNS()}, rdns, "EHLO mail.example.org"},
+		{"4", []Option{WithHeloFromReverseDNS()}, nordns, "EHLO [127.0.0.1]"},
+		{"5", []Option{WithHeloFromReverseDNS(), WithHeloDomain("probe.example.net")}, rdns, "EHLO probe.example.net"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0")
+			v := NewVerifier(tt.opts...)
+			v.port = s.port()
+			v.resolver = tt.resolver
+			if err := v.TryHost(context.Background(), s.host(), EmailAddress{"info", "example.com"}); err != nil {
+				t.Fatalf("Verifier.TryHost() error = %v", err)
+			}
+			if got := s.commands()[0]; got != tt.want {
+				t.Errorf("Verifier.heloName() sent %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_heloNameCache(t *testing.T) {
+	tests := []struct {
+		name     string
+		resolver *testResolver
+		want     string
+	}{
+		{"1", &testResolver{addrs: map[string][]string{"127.0.0.1": {"mail.example.org."}}}, "EHLO mail.example.org"},
+		{"2", &testResolver{}, "EHLO [127.0.0.1]"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := newTestServer(t, "127.0.0.1:0")
+			v := NewVerifier(WithHeloFromReverseDNS())
+			v.port = s.port()
+			v.resolver = tt.resolver
+			for i := 0; i < 3; i++ {
+				if err := v.TryHost(context.Background(), s.host(), EmailAddress{"info", "example.com"}); err != nil {
+					t.Fatalf("Verifier.TryHost() error = %v", err)
+				}
+			}
+			if tt.resolver.queries != 1 {
+				t.Errorf("reverse DNS lookups = %d, want 1", tt.resolver.queries)
+			}
+
+			// An expired name is looked up again.
+			v.mu.Lock()
+			e := v.heloCache["127.0.0.1"]
+			e.expires = time.Now()
+			v.heloCache["127.0.0.1"] = e
+			v.mu.Unlock()
+			if err := v.TryHost(context.Background(), s.host(), EmailAddress{"info", "example.com"}); err != nil {
+				t.Fatalf("Verifier.TryHost() error = %v", err)
+			}
+			if tt.resolver.queries != 2 {
+				t.Errorf("reverse DNS lookups after expiry = %d, want 2", tt.resolver.queries)
+			}
+			for _, cmd := range s.commands() {
+				if strings.HasPrefix(cmd, "EHLO") && cmd != tt.want {
+					t.Errorf("Verifier.heloName() sent %q, want %q", cmd, tt.want)
+				}
+			}
+		})
+	}
+}
+
+func Test_unbracketHost(t *testing.T) {
+	tests := []struct {
+		name string
+		host string
+		want string
+	}{
+		{"1", "mx.example.com.", "mx.example.com."},
+		{"2", "192.0.2.1", "192.0.2.1"},
+		{"3", "[192.0.2.1]", "192.0.2.1"},
+		{"4", "2001:db8::1", "2001:db8::1"},
+		{"5", "[2001:db8::1]", "2001:db8::1"},
+		{"6", "[IPv6:2001:db8::1]", "2001:db8::1"},
+		{"7", "fe80::1%eth0", "fe80::1%eth0"},
+		{"8", "[fe80::1%eth0]", "fe80::1%eth0"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := unbracketHost(tt.host); got != tt.want {
+				t.Errorf("unbracketHost() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_TryHostIPv6(t *testing.T) {
+	s := newTestServer(t, "[::1]:0")
+	tests := []struct {
+		name string
+		host string
+	}{
+		{"1", "::1"},
+		{"2", "[::1]"},
+		{"3", "[IPv6:::1]"},
+		{"4", "::1%" + loopbackInterface(t)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier()
+			v.port = s.port()
+			if err := v.TryHost(context.Background(), tt.host, EmailAddress{"info", "example.com"}); err != nil {
+				t.Errorf("Verifier.TryHost() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifier_CheckHost(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.mail = func(addr string) string {
+		if addr == "hello@blocked.example.com" {
+			return "554 sender rejected"
+		}
+		return "250 OK"
+	}
+	s.rcpt = func(addr string) string {
+		switch addr {
+		case "fake@example.com":
+			return "550 no such user"
+		case "greylisted@example.com":
+			return "450 try again later"
+		}
+		return "250 OK"
+	}
+	closed, _ := net.Listen("tcp", "127.0.0.1:0")
+	closed.Close()
+
+	host := []*net.MX{{Host: s.host(), Pref: 10}}
+	r := &testResolver{
+		mx: map[string][]*net.MX{
+			"example.com":         host,
+			"blocked.example.com": host,
+		},
+	}
+	tests := []struct {
+		name     string
+		e        EmailAddress
+		resolver resolver
+		port     int
+		want     HostStatus
+	}{
+		{"1", EmailAddress{"info", "example.com"}, r, s.port(), HostVerified},
+		{"2", EmailAddress{"fake", "example.com"}, r, s.port(), HostInvalid},
+		{"3", EmailAddress{"greylisted", "example.com"}, r, s.port(), HostUnverifiable},
+		{"4", EmailAddress{"info", "blocked.example.com"}, r, s.port(), HostUnverifiable},
+		{"5", EmailAddress{"info", "example.org"}, r, s.port(), HostInvalid},
+		{"6", EmailAddress{"info", "example.com"}, &testResolver{err: &net.DNSError{Err: "server misbehaving", IsTemporary: true}}, s.port(), HostUnverifiable},
+		{"7", EmailAddress{"info", "example.com"}, r, closed.Addr().(*net.TCPAddr).Port, HostUnverifiable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier()
+			v.resolver = tt.resolver
+			v.port = tt.port
+			got, err := v.CheckHost(context.Background(), tt.e)
+			if got != tt.want {
+				t.Errorf("Verifier.CheckHost() = %v, %v, want %v", got, err, tt.want)
+			}
+			if (err != nil) != (tt.want != HostVerified) {
+				t.Errorf("Verifier.CheckHost() error = %v", err)
+			}
+		})
+	}
+}
+
+func TestVerifier_TryHost_context(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(string) string {
+		time.Sleep(time.Second)
+		return "250 OK"
+	}
+	v := NewVerifier()
+	v.port = s.port()
+
+	timeout, cancelTimeout := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancelTimeout()
+	cancelled, cancel := context.WithCancel(context.Background())
+	time.AfterFunc(100*time.Millisecond, cancel)
+	done, cancelDone := context.WithCancel(context.Background())
+	cancelDone()
+	tests := []struct {
+		name string
+		ctx  context.Context
+		want error
+	}{
+		{"1", timeout, context.DeadlineExceeded},
+		{"2", cancelled, context.Canceled},
+		{"3", done, context.Canceled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			start := time.Now()
+			err := v.TryHost(tt.ctx, s.host(), EmailAddress{"info", "example.com"})
+			if err != tt.want {
+				t.Errorf("Verifier.TryHost() error = %v, want %v", err, tt.want)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Verifier.TryHost() took %v, want it to return when the context is done", elapsed)
+			}
+		})
+	}
+}
+
+func TestVerifier_TryHost_options(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	e := EmailAddress{"info", "example.com"}
+	tests := []struct {
+		name string
+		opts []Option
+		want string
+	}{
+		{"1", nil, "MAIL FROM:<hello@example.com>"},
+		{"2", []Option{WithSenderAddress("verify@example.org")}, "MAIL FROM:<verify@example.org>"},
+		{"3", []Option{WithSenderAddress("verify@example.org"), WithDialTimeout(time.Second)}, "MAIL FROM:<verify@example.org>"},
+		{"4", []Option{WithCommandTimeout(time.Second)}, "MAIL FROM:<hello@example.com>"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(append([]Option{WithPort(s.port())}, tt.opts...)...)
+			if err := v.TryHost(context.Background(), s.host(), e); err != nil {
+				t.Fatalf("Verifier.TryHost() error = %v", err)
+			}
+			var found bool
+			for _, cmd := range s.commands() {
+				found = found || strings.HasPrefix(cmd, tt.want)
+			}
+			if !found {
+				t.Errorf("Verifier.TryHost() commands = %v, want %q", s.commands(), tt.want)
+			}
+		})
+	}
+}
+
+func TestVerifier_TryHost_commandTimeout(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(string) string {
+		time.Sleep(time.Second)
+		return "250 OK"
+	}
+	v := NewVerifier(WithPort(s.port()), WithCommandTimeout(100*time.Millisecond))
+
+	start := time.Now()
+	err := v.TryHost(context.Background(), s.host(), EmailAddress{"info", "example.com"})
+	if err == nil {
+		t.Errorf("Verifier.TryHost() error = nil, want a timeout")
+	}
+	if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+		t.Errorf("Verifier.TryHost() took %v, want it to return after the command timeout", elapsed)
+	}
+}
+
+// testDialer is a Dialer without DialContext, like a dialer of golang.org/x/net/proxy.
+type testDialer struct {
+	addr  string
+	err   error
+	delay time.Duration
+
+	mu    sync.Mutex
+	addrs []string
+}
+
+func (d *testDialer) Dial(network, addr string) (net.Conn, error) {
+	d.mu.Lock()
+	d.addrs = append(d.addrs, addr)
+	d.mu.Unlock()
+	time.Sleep(d.delay)
+	if d.err != nil {
+		return nil, d.err
+	}
+	return net.Dial(network, d.addr)
+}
+
+func (d *testDialer) dialed() []string {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]string(nil), d.addrs...)
+}
+
+func TestVerifier_TryHost_dialer(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	addr := net.JoinHostPort(s.host(), strconv.Itoa(s.port()))
+	e := EmailAddress{"info", "example.com"}
+	tests := []struct {
+		name     string
+		dialer   *testDialer
+		timeout  time.Duration
+		wantCode Code
+	}{
+		{"1", &testDialer{addr: addr}, 0, ""},
+		{"2", &testDialer{err: errors.New("proxy refused connection")}, 0, CodeSMTPUnreachable},
+		{"3", &testDialer{addr: addr, delay: time.Second}, 100 * time.Millisecond, CodeSMTPUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithDialer(tt.dialer), WithPort(2525), WithDialTimeout(tt.timeout))
+			start := time.Now()
+			err := v.TryHost(context.Background(), "mx.example.com", e)
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Errorf("Verifier.TryHost() error = %v, want code %q", err, tt.wantCode)
+			}
+			if elapsed := time.Since(start); elapsed > 500*time.Millisecond {
+				t.Errorf("Verifier.TryHost() took %v, want it to return after the dial timeout", elapsed)
+			}
+			if addrs := tt.dialer.dialed(); len(addrs) != 1 || addrs[0] != "mx.example.com:2525" {
+				t.Errorf("Dialer.Dial() addrs = %v, want [mx.example.com:2525]", addrs)
+			}
+		})
+	}
+
+	v := NewVerifier(WithDialer(&net.Dialer{}), WithPort(s.port()))
+	if err := v.TryHost(context.Background(), s.host(), e); err != nil {
+		t.Errorf("Verifier.TryHost() with a net.Dialer error = %v", err)
+	}
+}
+
+func TestVerifier_ValidateHost_backupMX(t *testing.T) {
+	good := newTestServer(t, "127.0.0.1:0")
+	port := strconv.Itoa(good.port())
+	temporary := newTestServer(t, "127.0.0.2:"+port)
+	temporary.rcpt = func(string) string { return "451 4.3.0 try again later" }
+	rejecting := newTestServer(t, "127.0.0.4:"+port)
+	rejecting.rcpt = func(string) string { return "550 5.1.1 no such user" }
+	const dead = "127.0.0.3"
+
+	tests := []struct {
+		name     string
+		mx       []*net.MX
+		wantCode Code
+	}{
+		{"1", []*net.MX{{Host: dead, Pref: 10}, {Host: good.host(), Pref: 20}}, ""},
+		{"2", []*net.MX{{Host: good.host(), Pref: 20}, {Host: temporary.host(), Pref: 10}}, ""},
+		{"3", []*net.MX{{Host: rejecting.host(), Pref: 10}, {Host: good.host(), Pref: 20}}, CodeMailboxRejected},
+		{"4", []*net.MX{{Host: dead, Pref: 10}, {Host: temporary.host(), Pref: 20}}, CodeSMTPTemporary},
+		{"5", []*net.MX{{Host: dead, Pref: 10}}, CodeSMTPUnreachable},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := NewVerifier(WithPort(good.port()))
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": tt.mx}}
+			err := v.ValidateHost(context.Background(), EmailAddress{"info", "example.com"})
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Errorf("Verifier.ValidateHost() error = %v, want code %q", err, tt.wantCode)
+			}
+		})
+	}
+	if n := len(good.commands()); n != 2*5 {
+		t.Errorf("backup mail host got %d commands, want only the probes of 1 and 2", n)
+	}
+	if n := len(temporary.commands()); n != 2*3 {
+		t.Errorf("temporarily failing mail host got %d commands, want the probes of 2 and 4", n)
+	}
+}
+
+// greylist returns a reply function that fails the first n recipients temporarily, or all of them
+// if n is negative.
+func greylist(n int) func(string) string {
+	var mu sync.Mutex
+	return func(string) string {
+		mu.Lock()
+		defer mu.Unlock()
+		if n != 0 {
+			if n > 0 {
+				n--
+			}
+			return "450 4.7.1 greylisted, try again later"
+		}
+		return "250 OK"
+	}
+}
+
+func TestVerifier_ValidateHost_retry(t *testing.T) {
+	tests := []struct {
+		name     string
+		opts     []Option
+		primary  int // recipients greylisted by the primary host, -1 for all
+		backup   int
+		wantCode Code
+	}{
+		{"1", []Option{WithRetry(3, time.Millisecond)}, 2, -1, ""},
+		{"2", nil, 2, -1, CodeSMTPTemporary},
+		{"3", []Option{WithRetry(2, time.Millisecond)}, 2, -1, CodeSMTPTemporary},
+		{"4", []Option{WithRetry(2, time.Millisecond)}, -1, 1, CodeSMTPTemporary},
+		{"5", []Option{WithRetry(2, time.Millisecond), WithRetryRotation()}, -1, 1, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			primary := newTestServer(t, "127.0.0.1:0")
+			primary.rcpt = greylist(tt.primary)
+			backup := newTestServer(t, "127.0.0.2:"+strconv.Itoa(primary.port()))
+			backup.rcpt = greylist(tt.backup)
+			v := NewVerifier(append(tt.opts, WithPort(primary.port()))...)
+			v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {
+				{Host: primary.host(), Pref: 10}, {Host: backup.host(), Pref: 20},
+			}}}
+			err := v.ValidateHost(context.Background(), EmailAddress{"info", "example.com"})
+			if got := ErrorCode(err); got != tt.wantCode {
+				t.Errorf("Verifier.ValidateHost() error = %v, want code %q", err, tt.wantCode)
+			}
+		})
+	}
+
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = greylist(-1)
+	v := NewVerifier(WithPort(s.port()), WithRetry(2, time.Hour))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if err := v.ValidateHost(ctx, EmailAddress{"info", "example.com"}); err != context.DeadlineExceeded {
+		t.Errorf("Verifier.ValidateHost() error = %v, want %v", err, context.DeadlineExceeded)
+	}
+}
+
+func TestVerifier_ValidateHost_idn(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	v := NewVerifier(WithPort(s.port()))
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"xn--bcher-kva.example": {{Host: s.host(), Pref: 10}}}}
+
+	if err := v.ValidateHost(context.Background(), EmailAddress{"info", "bücher.example"}); err != nil {
+		t.Fatalf("Verifier.ValidateHost() error = %v", err)
+	}
+	var found bool
+	for _, cmd := range s.commands() {
+		found = found || cmd == "RCPT TO:<info@xn--bcher-kva.example>"
+	}
+	if !found {
+		t.Errorf("Verifier.ValidateHost() commands = %v, want the recipient with the ASCII domain", s.commands())
+	}
+}
@@ -0,0 +1,22 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import "context"
+
+// VerifyStage checks the host of every address with v.CheckHost and drops invalid addresses.
+// Addresses that could not be verified are kept if keepUnverifiable is true.
+func VerifyStage(v *Verifier, keepUnverifiable bool) Stage {
+	return StageFunc(func(ctx context.Context, e *EmailAddress) (bool, error) {
+		status, _ := v.CheckHost(ctx, *e)
+		if err := ctx.Err(); err != nil {
+			return false, err
+		}
+		return status == HostVerified || status == HostUnverifiable && keepUnverifiable, nil
+	})
+}
@@ -0,0 +1,65 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+//go:build !nonet && !tinygo
+
+package emailaddress
+
+import (
+	"context"
+	"errors"
+	"net"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestPipeline_Run(t *testing.T) {
+	s := newTestServer(t, "127.0.0.1:0")
+	s.rcpt = func(addr string) string {
+		if strings.HasPrefix(addr, "gone@") {
+			return "550 no such user"
+		}
+		return "250 OK"
+	}
+	v := NewVerifier()
+	v.port = s.port()
+	v.resolver = &testResolver{mx: map[string][]*net.MX{"example.com": {{Host: s.host(), Pref: 10}}}}
+
+	notRole := func(e *EmailAddress) bool { return e.LocalPart != "info" }
+	tests := []struct {
+		name    string
+		stages  []Stage
+		input   []string
+		want    []*EmailAddress
+		wantErr bool
+	}{
+		{"1", nil, []string{" foo@Example.com ", "invalid", "<bar@example.com>"}, []*EmailAddress{{"foo", "example.com"}, {"bar", "example.com"}}, false},
+		{"2", []Stage{DedupeStage(nil)}, []string{"foo@example.com", "FOO@example.com", "bar@example.com"}, []*EmailAddress{{"foo", "example.com"}, {"bar", "example.com"}}, false},
+		{"3", []Stage{SuppressStage([]string{"Foo@example.com"})}, []string{"foo@example.com", "bar@example.com"}, []*EmailAddress{{"bar", "example.com"}}, false},
+		{"4", []Stage{FilterStage(notRole)}, []string{"info@example.com", "bar@example.com"}, []*EmailAddress{{"bar", "example.com"}}, false},
+		{"5", []Stage{VerifyStage(v, false)}, []string{"gone@example.com", "bar@example.com", "foo@example.org"}, []*EmailAddress{{"bar", "example.com"}}, false},
+		{"6", []Stage{StageFunc(func(context.Context, *EmailAddress) (bool, error) { return false, errors.New("failed") })}, []string{"foo@example.com"}, nil, true},
+		{"7", []Stage{DedupeStage(nil)}, []string{"Foo+tag@gmail.com", "foo@gmail.com", "f.oo@googlemail.com", "bar@gmail.com"}, []*EmailAddress{{"Foo+tag", "gmail.com"}, {"bar", "gmail.com"}}, false},
+		{"8", []Stage{DedupeStage(func(e *EmailAddress) string { return e.String() })}, []string{"foo@example.com", "FOO@example.com"}, []*EmailAddress{{"foo", "example.com"}, {"FOO", "example.com"}}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []*EmailAddress
+			sink := SinkFunc(func(ctx context.Context, e *EmailAddress) error {
+				got = append(got, e)
+				return nil
+			})
+			err := NewPipeline(tt.stages...).Run(context.Background(), SliceSource(tt.input), sink)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("Pipeline.Run() error = %v, wantErr %v", err, tt.wantErr)
+				return
+			}
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Pipeline.Run() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,162 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// ReadDomainList reads a compact DomainList from r with one domain per line. Empty lines and
+// comments starting with # are skipped.
+func ReadDomainList(r io.Reader) (DomainList, error) {
+	var domains []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		if line := stripComment(scanner.Text()); line != "" {
+			domains = append(domains, line)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return NewCompactDomainList(domains), nil
+}
+
+// stripComment strips a # comment and surrounding whitespace from a line of a list file.
+func stripComment(line string) string {
+	if i := strings.IndexByte(line, '#'); i >= 0 {
+		line = line[:i]
+	}
+	return strings.TrimSpace(line)
+}
+
+// FileDomainList is a DomainList read from a file with ReadDomainList that is reloaded when the
+// file changes, so allow, deny and disposable lists can be updated without restarting. The new
+// list is swapped in atomically, lookups see either the old or the new list.
+type FileDomainList struct {
+	path     string
+	interval time.Duration
+	onError  func(error)
+	read     func(io.Reader) (DomainList, error)
+
+	list    atomic.Value // DomainList
+	mu      sync.Mutex
+	modTime time.Time
+	size    int64
+
+	stop chan struct{}
+	done chan struct{}
+	once sync.Once
+}
+
+// WatchDomainList reads the domain list at path and checks the file for changes every interval.
+// A file that fails to load, or an interval that isn't positive, makes WatchDomainList return an
+// error. Once watching, load errors are reported to onError, which may be nil, and the
+// previously loaded list is kept. Call Close to stop watching.
+func WatchDomainList(path string, interval time.Duration, onError func(error)) (*FileDomainList, error) {
+	return watchFile(path, interval, onError, ReadDomainList)
+}
+
+func watchFile(path string, interval time.Duration, onError func(error), read func(io.Reader) (DomainList, error)) (*FileDomainList, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("failed watching domain list %s: interval must be positive, got %v", path, interval)
+	}
+	l := &FileDomainList{
+		path:     path,
+		interval: interval,
+		onError:  onError,
+		read:     read,
+		stop:     make(chan struct{}),
+		done:     make(chan struct{}),
+	}
+	if err := l.Reload(); err != nil {
+		return nil, err
+	}
+	go l.watch()
+	return l, nil
+}
+
+// Contains reports whether the domain is in the most recently loaded list.
+func (l *FileDomainList) Contains(domain string) bool {
+	return l.list.Load().(DomainList).Contains(domain)
+}
+
+// Match reports whether the domain or one of its parent domains is in the most recently loaded
+// list, or for a list read with ReadDomainTrie, whether any rule matches the domain.
+func (l *FileDomainList) Match(domain string) bool {
+	return listed(l.list.Load().(DomainList), domain)
+}
+
+// Reload reads the file, regardless of whether it changed.
+func (l *FileDomainList) Reload() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.load()
+}
+
+// Close stops watching the file. The last loaded list remains usable.
+func (l *FileDomainList) Close() error {
+	l.once.Do(func() {
+		close(l.stop)
+	})
+	<-l.done
+	return nil
+}
+
+func (l *FileDomainList) watch() {
+	defer close(l.done)
+	t := time.NewTicker(l.interval)
+	defer t.Stop()
+	for {
+		select {
+		case <-l.stop:
+			return
+		case <-t.C:
+			if err := l.reloadIfChanged(); err != nil && l.onError != nil {
+				l.onError(err)
+			}
+		}
+	}
+}
+
+func (l *FileDomainList) reloadIfChanged() error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	fi, err := os.Stat(l.path)
+	if err != nil {
+		return fmt.Errorf("failed reloading domain list %s: %w", l.path, err)
+	}
+	if fi.ModTime().Equal(l.modTime) && fi.Size() == l.size {
+		return nil
+	}
+	return l.load()
+}
+
+func (l *FileDomainList) load() error {
+	f, err := os.Open(l.path)
+	if err != nil {
+		return fmt.Errorf("failed loading domain list %s: %w", l.path, err)
+	}
+	defer f.Close() // #nosec
+	fi, err := f.Stat()
+	if err != nil {
+		return fmt.Errorf("failed loading domain list %s: %w", l.path, err)
+	}
+	list, err := l.read(f)
+	if err != nil {
+		return fmt.Errorf("failed loading domain list %s: %w", l.path, err)
+	}
+	l.list.Store(list)
+	l.modTime, l.size = fi.ModTime(), fi.Size()
+	return nil
+}
@@ -0,0 +1,67 @@
+// Copyright 2018 The go-emailaddress AUTHORS. All rights reserved.
+//
+// Use of this source code is governed by a MIT
+// license that can be found in the LICENSE file.
+
+package emailaddress
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestReadDomainList(t *testing.T) {
+	l, err := ReadDomainList(strings.NewReader("# disposable providers\nmailinator.com\n\n  Tempmail.net # fast\n"))
+	if err != nil {
+		t.Fatalf("ReadDomainList() error = %v", err)
+	}
+	tests := []struct {
+		name   string
+		domain string
+		want   bool
+	}{
+		{"1", "mailinator.com", true},
+		{"2", "tempmail.net", true},
+		{"3", "# disposable providers", false},
+		{"4", "fast", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := l.Contains(tt.domain); got != tt.want {
+				t.Errorf("DomainList.Contains(%q) = %v, want %v", tt.domain, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestWatchDomainList_missing(t *testing.T) {
+	if _, err := WatchDomainList(filepath.Join(t.TempDir(), "missing.txt"), time.Second, nil); err == nil {
+		t.Errorf("WatchDomainList() error = nil, want error")
+	}
+}
+
+func TestWatchDomainList_interval(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "domains.txt")
+	if err := os.WriteFile(path, []byte("mailinator.com\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, interval := range []time.Duration{0, -time.Second} {
+		if _, err := WatchDomainList(path, interval, nil); err == nil {
+			t.Errorf("WatchDomainList(%v) error = nil, want error", interval)
+		}
+	}
+}
+
+func waitFor(t *testing.T, cond func() bool) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for !cond() {
+		if time.Now().After(deadline) {
+			t.Fatalf("condition not met within a second")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}